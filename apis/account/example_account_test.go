@@ -38,3 +38,25 @@ func ExampleShortUrl() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleGetPermanentImage() {
+	var ctx *offiaccount.OffiAccount
+
+	image, err := account.GetPermanentImage(ctx, "SCENE_STR")
+
+	fmt.Println(image, err)
+}
+
+func ExampleCreateQRCodeBatch() {
+	var ctx *offiaccount.OffiAccount
+
+	results, err := account.CreateQRCodeBatch(ctx, []string{"channel_1", "channel_2"}, 3600)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, result := range results {
+		fmt.Println(result.Scene, result.Url, result.Err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/fastwego/offiaccount"
+)
+
+var ShowQRCodeServerUrl = "https://mp.weixin.qq.com"
+
+const apiShowQRCode = "/cgi-bin/showqrcode"
+
+/*
+通过ticket换取二维码
+
+获取二维码ticket后，开发者可用ticket换取二维码图片。请注意，本接口无须登录态即可调用
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Account_Management/Generating_a_Parametric_QR_Code.html
+
+服务器地址 优先使用 Config.HostOverrides 中 匹配 /cgi-bin/showqrcode 的配置，未配置时 使用 ShowQRCodeServerUrl
+
+GET https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=TICKET
+*/
+func ShowQRCode(ctx *offiaccount.OffiAccount, ticket string) (image []byte, err error) {
+	params := url.Values{}
+	params.Add("ticket", ticket)
+
+	serverUrl := ShowQRCodeServerUrl
+	if host, ok := ctx.HostOverride(apiShowQRCode); ok {
+		serverUrl = host
+	}
+	uri := offiaccount.AppendQuery(offiaccount.JoinUrl(serverUrl, apiShowQRCode), params)
+	response, err := ctx.Client.HTTPClient().Get(uri)
+	if err != nil {
+		return
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("GET %s RETURN %s", uri, response.Status)
+		return
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+/*
+GetPermanentImage 获取 永久字符串参数二维码 图片
+
+首次调用 会创建二维码 ticket 并换取图片，之后 scene_str -> ticket 以及 ticket -> 图片 都缓存在 ctx.AccessToken.Cache 中
+
+永久二维码 同一个 scene_str 的 ticket 不变，所以缓存不设置过期时间
+*/
+func GetPermanentImage(ctx *offiaccount.OffiAccount, sceneStr string) (image []byte, err error) {
+	ticketKey := ctx.CacheKey("qrcode_ticket:" + ctx.Config.Appid + ":" + sceneStr)
+
+	ticket, _ := ctx.AccessToken.Cache.Fetch(ticketKey)
+	if ticket == "" {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"action_name": "QR_LIMIT_STR_SCENE",
+			"action_info": map[string]interface{}{
+				"scene": map[string]string{"scene_str": sceneStr},
+			},
+		})
+
+		var resp []byte
+		resp, err = CreateQRCode(ctx, payload)
+		if err != nil {
+			return
+		}
+
+		result := struct {
+			Ticket string `json:"ticket"`
+		}{}
+		err = json.Unmarshal(resp, &result)
+		if err != nil {
+			return
+		}
+		if result.Ticket == "" {
+			err = fmt.Errorf("%s", string(resp))
+			return
+		}
+		ticket = result.Ticket
+		_ = ctx.AccessToken.Cache.Save(ticketKey, ticket, 0)
+	}
+
+	// 缓存驱动只支持字符串 图片需要 base64 编码后存储
+	imageKey := ctx.CacheKey("qrcode_image:" + ticket)
+	if cached, _ := ctx.AccessToken.Cache.Fetch(imageKey); cached != "" {
+		if image, err = base64.StdEncoding.DecodeString(cached); err == nil {
+			return
+		}
+	}
+
+	image, err = ShowQRCode(ctx, ticket)
+	if err != nil {
+		return
+	}
+	_ = ctx.AccessToken.Cache.Save(imageKey, base64.StdEncoding.EncodeToString(image), 0)
+
+	return
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const (
+	QRCodeExpireSecondsMax = 2592000 // 临时二维码 最长有效期 30 天
+	QRCodeSceneStrMax      = 64      // scene_str 最大长度
+)
+
+// 接口调用次数 已达 每日上限，后续请求 不再发送
+const errcodeDailyQuotaLimit = 45009
+
+// QRCodeResult 批量创建二维码 单个场景的结果 Err 不为 nil 表示 该场景创建失败
+type QRCodeResult struct {
+	Scene         string `json:"scene"`
+	Ticket        string `json:"ticket"`
+	ExpireSeconds int    `json:"expire_seconds"`
+	Url           string `json:"url"`
+	Err           error  `json:"-"`
+}
+
+/*
+CreateQRCodeBatch 批量创建 字符串参数二维码 结果与 scenes 顺序一致
+
+expireSeconds 大于 0 创建临时二维码，等于 0 创建永久二维码
+
+依次调用 /cgi-bin/qrcode/create，通过 Config.RateLimiter 限流 避免触发频率限制；单个场景失败 记录在 QRCodeResult.Err 中 并继续
+
+返回 45009(调用次数达到每日上限) 后 剩余场景 不再请求，直接记录该错误
+*/
+func CreateQRCodeBatch(ctx *offiaccount.OffiAccount, scenes []string, expireSeconds int) (results []QRCodeResult, err error) {
+	if expireSeconds < 0 || expireSeconds > QRCodeExpireSecondsMax {
+		return nil, fmt.Errorf("qrcode: expire_seconds %d out of range [0, %d]", expireSeconds, QRCodeExpireSecondsMax)
+	}
+
+	actionName := "QR_LIMIT_STR_SCENE"
+	if expireSeconds > 0 {
+		actionName = "QR_STR_SCENE"
+	}
+
+	results = make([]QRCodeResult, len(scenes))
+	var quotaErr error
+	for i, scene := range scenes {
+		results[i].Scene = scene
+
+		if quotaErr != nil {
+			results[i].Err = quotaErr
+			continue
+		}
+		if scene == "" || len(scene) > QRCodeSceneStrMax {
+			results[i].Err = fmt.Errorf("qrcode: scene_str length must be 1-%d", QRCodeSceneStrMax)
+			continue
+		}
+
+		params := map[string]interface{}{
+			"action_name": actionName,
+			"action_info": map[string]interface{}{
+				"scene": map[string]string{"scene_str": scene},
+			},
+		}
+		if expireSeconds > 0 {
+			params["expire_seconds"] = expireSeconds
+		}
+		payload, _ := json.Marshal(params)
+
+		resp, callErr := CreateQRCode(ctx, payload)
+		if callErr != nil {
+			results[i].Err = callErr
+
+			var wxErr *offiaccount.WXError
+			if errors.As(callErr, &wxErr) && wxErr.Errcode == errcodeDailyQuotaLimit {
+				quotaErr = callErr
+			}
+			continue
+		}
+
+		if callErr = json.Unmarshal(resp, &results[i]); callErr != nil {
+			results[i].Err = callErr
+			continue
+		}
+		results[i].Scene = scene
+		if results[i].Ticket == "" {
+			results[i].Err = fmt.Errorf("%s", string(resp))
+		}
+	}
+
+	return
+}
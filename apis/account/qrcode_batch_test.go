@@ -0,0 +1,92 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestCreateQRCodeBatch(t *testing.T) {
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiCreateQRCode, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		payload, _ := ioutil.ReadAll(r.Body)
+		params := struct {
+			ExpireSeconds int    `json:"expire_seconds"`
+			ActionName    string `json:"action_name"`
+			ActionInfo    struct {
+				Scene struct {
+					SceneStr string `json:"scene_str"`
+				} `json:"scene"`
+			} `json:"action_info"`
+		}{}
+		_ = json.Unmarshal(payload, &params)
+		if params.ActionName != "QR_STR_SCENE" || params.ExpireSeconds != 3600 {
+			t.Errorf("payload = %s", payload)
+		}
+
+		switch params.ActionInfo.Scene.SceneStr {
+		case "busy":
+			w.Write([]byte(`{"errcode":45011,"errmsg":"api minute-quota reach limit"}`))
+			return
+		case "quota":
+			w.Write([]byte(`{"errcode":45009,"errmsg":"reach max api daily quota limit"}`))
+			return
+		}
+		w.Write([]byte(`{"ticket":"TICKET_` + params.ActionInfo.Scene.SceneStr + `","expire_seconds":3600,"url":"http://weixin.qq.com/q/` + params.ActionInfo.Scene.SceneStr + `"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestCreateQRCodeBatch", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	scenes := []string{"a", "busy", "", "b", "quota", "c"}
+	results, err := CreateQRCodeBatch(ctx, scenes, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(scenes) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(scenes))
+	}
+	for i, scene := range scenes {
+		if results[i].Scene != scene {
+			t.Errorf("results[%d].Scene = %q, want %q", i, results[i].Scene, scene)
+		}
+	}
+	if results[0].Err != nil || results[0].Ticket != "TICKET_a" || results[3].Ticket != "TICKET_b" || results[3].Url != "http://weixin.qq.com/q/b" {
+		t.Errorf("results = %+v", results)
+	}
+	if results[1].Err == nil || results[2].Err == nil || results[4].Err == nil {
+		t.Errorf("results = %+v, want errors for busy, empty and quota", results)
+	}
+
+	// 45009 后 不再请求
+	if results[5].Err == nil || !strings.Contains(results[5].Err.Error(), "45009") || calls != 4 {
+		t.Errorf("results[5] = %+v calls = %d, want 45009 without request", results[5], calls)
+	}
+
+	if _, err = CreateQRCodeBatch(ctx, scenes, QRCodeExpireSecondsMax+1); err == nil {
+		t.Errorf("CreateQRCodeBatch() want error for expire_seconds out of range")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestGetPermanentImage(t *testing.T) {
+	ShowQRCodeServerUrl = test.MockSvr.URL
+
+	var showCalls int
+	mockImage := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00, 0xfe}
+	test.MockSvrHandler.HandleFunc(apiShowQRCode, func(w http.ResponseWriter, r *http.Request) {
+		showCalls++
+		if r.URL.Query().Get("ticket") != "TICKET_GetPermanentImage" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(mockImage)
+	})
+
+	// 预置 ticket 缓存 只验证图片的获取和缓存
+	ctx := test.MockOffiAccount
+	ctx.AccessToken.Cache.Delete("qrcode_image:TICKET_GetPermanentImage")
+	ctx.AccessToken.Cache.Save("qrcode_ticket:"+ctx.Config.Appid+":GetPermanentImage", "TICKET_GetPermanentImage", 0)
+
+	for i := 0; i < 2; i++ {
+		image, err := GetPermanentImage(ctx, "GetPermanentImage")
+		if err != nil {
+			t.Fatalf("GetPermanentImage() error = %v", err)
+		}
+		if !reflect.DeepEqual(image, mockImage) {
+			t.Errorf("GetPermanentImage() got = %v, want %v", image, mockImage)
+		}
+	}
+
+	if showCalls != 1 {
+		t.Errorf("GetPermanentImage() showCalls = %d, want 1", showCalls)
+	}
+}
+
+func TestShowQRCode_HostOverrides(t *testing.T) {
+	mockImage := []byte{0x89, 'P', 'N', 'G'}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiShowQRCode || r.URL.Query().Get("ticket") != "TICKET" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(mockImage)
+	}))
+	defer svr.Close()
+
+	transport := &countingTransport{}
+	ctx := offiaccount.New(offiaccount.Config{
+		Appid:         "TestShowQRCode_HostOverrides",
+		HostOverrides: map[string]string{apiShowQRCode: svr.URL},
+		HTTPClient:    &http.Client{Transport: transport},
+	})
+	ctx.SetLogger(nil)
+
+	image, err := ShowQRCode(ctx, "TICKET")
+	if err != nil || !reflect.DeepEqual(image, mockImage) {
+		t.Errorf("ShowQRCode() = %v, %v, want %v", image, err, mockImage)
+	}
+	if transport.calls != 1 {
+		t.Errorf("Config.HTTPClient calls = %d, want 1", transport.calls)
+	}
+}
+
+type countingTransport struct {
+	calls int
+}
+
+func (transport *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -84,7 +84,7 @@ func AddVoiceToRecoForText(ctx *offiaccount.OffiAccount, media string, params ur
 		}
 
 	}()
-	return ctx.Client.HTTPPost(apiAddVoiceToRecoForText+"?"+params.Encode(), r, m.FormDataContentType())
+	return ctx.Client.HTTPPostWithParams(apiAddVoiceToRecoForText, params, r, m.FormDataContentType())
 }
 
 /*
@@ -97,7 +97,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Intelligent_Interface/AI_O
 POST https://api.weixin.qq.com/cgi-bin/media/voice/queryrecoresultfortext?access_token=ACCESS_TOKEN&voice_id=xxxxxx&lang=zh_CN
 */
 func QueryRecoResultForText(ctx *offiaccount.OffiAccount, payload []byte, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPPost(apiQueryRecoResultForText+"?"+params.Encode(), bytes.NewReader(payload), "application/json;charset=utf-8")
+	return ctx.Client.HTTPPostWithParams(apiQueryRecoResultForText, params, bytes.NewReader(payload), "application/json;charset=utf-8")
 }
 
 /*
@@ -110,7 +110,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Intelligent_Interface/AI_O
 POST https://api.weixin.qq.com/cgi-bin/media/voice/translatecontent?access_token=ACCESS_TOKEN&lfrom=xxx&lto=xxx
 */
 func TranslateContent(ctx *offiaccount.OffiAccount, payload []byte, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPPost(apiTranslateContent+"?"+params.Encode(), bytes.NewReader(payload), "application/json;charset=utf-8")
+	return ctx.Client.HTTPPostWithParams(apiTranslateContent, params, bytes.NewReader(payload), "application/json;charset=utf-8")
 }
 
 /*
@@ -15,8 +15,10 @@
 package ai_test
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/offiaccount/apis/ai"
@@ -132,3 +134,23 @@ func ExampleAICrop() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleRecognizeVoiceAsync() {
+	var ctx *offiaccount.OffiAccount
+
+	stdctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	for result := range ai.RecognizeVoiceAsync(ctx, stdctx, "VOICE_ID", nil) {
+		fmt.Println(result.Text, result.Final, result.Err)
+	}
+}
+
+func ExampleWithOCRRetry() {
+	var ctx *offiaccount.OffiAccount
+
+	// 101001 图片错误 等待后重试，只作用于 本次调用
+	resp, err := ai.WithOCRRetry(ctx, ai.OCRCommon, []byte(`{"img_url":"IMG_URL"}`))
+
+	fmt.Println(resp, err)
+}
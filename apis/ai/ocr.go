@@ -0,0 +1,104 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// Point 图片上的坐标点
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Quadrangle 识别区域的四个顶点
+type Quadrangle struct {
+	LeftTop     Point `json:"left_top"`
+	RightTop    Point `json:"right_top"`
+	RightBottom Point `json:"right_bottom"`
+	LeftBottom  Point `json:"left_bottom"`
+}
+
+// OCRCommonItem 通用印刷体识别出的一段文字
+type OCRCommonItem struct {
+	Text string     `json:"text"`
+	Pos  Quadrangle `json:"pos"`
+}
+
+// OCRCommonResult 通用印刷体OCR识别结果
+type OCRCommonResult struct {
+	Items   []OCRCommonItem `json:"items"`
+	ImgSize struct {
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"img_size"`
+}
+
+/*
+ParseOCRCommon 解析 OCRCommon 的响应
+
+	{
+	  "errcode": 0,
+	  "errmsg": "ok",
+	  "items": [
+	    {
+	      "text": "腾讯",
+	      "pos": {
+	        "left_top": {"x": 575, "y": 519},
+	        "right_top": {"x": 744, "y": 519},
+	        "right_bottom": {"x": 744, "y": 532},
+	        "left_bottom": {"x": 573, "y": 532}
+	      }
+	    }
+	  ],
+	  "img_size": {"w": 1280, "h": 720}
+	}
+*/
+func ParseOCRCommon(resp []byte) (result OCRCommonResult, err error) {
+	err = json.Unmarshal(resp, &result)
+	return
+}
+
+// BankcardResult 银行卡OCR识别结果
+type BankcardResult struct {
+	Number           string `json:"number"` // 识别出的卡号 可能包含空格
+	NormalizedNumber string `json:"-"`      // 去除空白字符后的卡号
+}
+
+/*
+ParseBankcardResult 解析 OCRBankcard 的响应 并去除卡号中的空白字符
+
+	{
+	  "errcode": 0,
+	  "errmsg": "ok",
+	  "number": "6212 2612 0200 1234 567"
+	}
+*/
+func ParseBankcardResult(resp []byte) (result BankcardResult, err error) {
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	result.NormalizedNumber = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, result.Number)
+	return
+}
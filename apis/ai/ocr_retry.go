@@ -0,0 +1,52 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// ErrcodeOCRImageError OCR 图片错误，使用 img_url 时 可能是 图片服务器 暂时无法访问
+const ErrcodeOCRImageError = 101001
+
+// OCR 图片错误 重试间隔 和 最多重试次数
+var (
+	ocrRetryDelay      = 500 * time.Millisecond
+	ocrRetryMaxRetries = 2
+)
+
+// OCRFunc OCR 接口 函数，如 OCRCommon、OCRIDCard
+type OCRFunc func(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error)
+
+/*
+WithOCRRetry 调用 OCR 接口 call，errcode 为 101001（图片错误）时 等待后重试，最多重试 2 次
+
+只作用于 本次调用，不影响 公众号实例 其他接口 的重试策略（Config.RetryClassifier）；
+access_token 过期 等 仍由 Client 按 RetryClassifier 刷新重试，不占用 101001 的重试次数
+
+	resp, err := ai.WithOCRRetry(app, ai.OCRCommon, payload)
+*/
+func WithOCRRetry(ctx *offiaccount.OffiAccount, call OCRFunc, payload []byte) (resp []byte, err error) {
+	for retries := 0; ; retries++ {
+		resp, err = call(ctx, payload)
+		wxErr, ok := offiaccount.AsWXError(err)
+		if !ok || wxErr.Errcode != ErrcodeOCRImageError || retries >= ocrRetryMaxRetries {
+			return
+		}
+		time.Sleep(ocrRetryDelay)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/offiaccount"
+)
+
+func TestWithOCRRetry(t *testing.T) {
+	ocrRetryDelay = time.Millisecond
+	defer func() { ocrRetryDelay = 500 * time.Millisecond }()
+
+	var calls, failures int
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiOCRCommon, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			_, _ = w.Write([]byte(`{"errcode":101001,"errmsg":"image error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","items":[]}`))
+	})
+	handler.HandleFunc(apiSemantic, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"errcode":101001,"errmsg":"image error"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := offiaccount.New(offiaccount.Config{Appid: "TestWithOCRRetry", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	// 不使用 WithOCRRetry 不重试
+	calls, failures = 0, 1
+	if _, err := OCRCommon(app, nil); err == nil || calls != 1 {
+		t.Errorf("OCRCommon() error = %v, calls = %d, want no retry by default", err, calls)
+	}
+
+	// 重试 2 次后成功
+	calls, failures = 0, 2
+	if _, err := WithOCRRetry(app, OCRCommon, nil); err != nil || calls != 3 {
+		t.Errorf("WithOCRRetry() error = %v, calls = %d, want success after 2 retries", err, calls)
+	}
+
+	// 超过 最多重试次数
+	calls, failures = 0, 5
+	_, err := WithOCRRetry(app, OCRCommon, nil)
+	if wxErr, ok := offiaccount.AsWXError(err); !ok || wxErr.Errcode != ErrcodeOCRImageError || calls != 3 {
+		t.Errorf("WithOCRRetry() error = %v, calls = %d, want 101001 after 3 calls", err, calls)
+	}
+
+	// 实例的 其他接口 不受影响
+	calls = 0
+	if _, err := Semantic(app, nil); err == nil || calls != 1 {
+		t.Errorf("Semantic() error = %v, calls = %d, want no retry", err, calls)
+	}
+}
+
+func TestWithOCRRetry_TokenRefresh(t *testing.T) {
+	ocrRetryDelay = time.Millisecond
+	defer func() { ocrRetryDelay = 500 * time.Millisecond }()
+
+	var tokens, calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	})
+	handler.HandleFunc(apiOCRCommon, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Query().Get("access_token") == "ACCESS_TOKEN_1":
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+		case calls <= 3:
+			_, _ = w.Write([]byte(`{"errcode":101001,"errmsg":"image error"}`))
+		default:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","items":[]}`))
+		}
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := offiaccount.New(offiaccount.Config{Appid: "TestWithOCRRetry_TokenRefresh", Secret: "SECRET", BaseURL: svr.URL})
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	app.SetLogger(nil)
+
+	// 40001 刷新重试 不占用 101001 的 2 次重试
+	if _, err := WithOCRRetry(app, OCRCommon, nil); err != nil || calls != 4 || tokens != 2 {
+		t.Errorf("WithOCRRetry() error = %v, calls = %d, tokens = %d, want success after refresh and 2 retries", err, calls, tokens)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOCRCommon(t *testing.T) {
+	resp := []byte(`{"errcode":0,"errmsg":"ok","items":[{"text":"腾讯","pos":{"left_top":{"x":575,"y":519},"right_top":{"x":744,"y":519},"right_bottom":{"x":744,"y":532},"left_bottom":{"x":573,"y":532}}},{"text":"微信","pos":{"left_top":{"x":1,"y":2},"right_top":{"x":3,"y":2},"right_bottom":{"x":3,"y":4},"left_bottom":{"x":1,"y":4}}}],"img_size":{"w":1280,"h":720}}`)
+
+	result, err := ParseOCRCommon(resp)
+	if err != nil {
+		t.Fatalf("ParseOCRCommon() error = %v", err)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("ParseOCRCommon() items = %d, want 2", len(result.Items))
+	}
+	want := OCRCommonItem{
+		Text: "腾讯",
+		Pos: Quadrangle{
+			LeftTop:     Point{X: 575, Y: 519},
+			RightTop:    Point{X: 744, Y: 519},
+			RightBottom: Point{X: 744, Y: 532},
+			LeftBottom:  Point{X: 573, Y: 532},
+		},
+	}
+	if !reflect.DeepEqual(result.Items[0], want) {
+		t.Errorf("ParseOCRCommon() got = %+v, want %+v", result.Items[0], want)
+	}
+	if result.ImgSize.W != 1280 || result.ImgSize.H != 720 {
+		t.Errorf("ParseOCRCommon() img_size = %+v", result.ImgSize)
+	}
+
+	if _, err = ParseOCRCommon([]byte(`{`)); err == nil {
+		t.Errorf("ParseOCRCommon() want error on invalid json")
+	}
+}
+
+func TestParseBankcardResult(t *testing.T) {
+	result, err := ParseBankcardResult([]byte(`{"errcode":0,"errmsg":"ok","number":"6212 2612\u00a00200 1234 567"}`))
+	if err != nil {
+		t.Fatalf("ParseBankcardResult() error = %v", err)
+	}
+	if result.Number != "6212 2612\u00a00200 1234 567" || result.NormalizedNumber != "6212261202001234567" {
+		t.Errorf("ParseBankcardResult() got = %+v", result)
+	}
+
+	if _, err = ParseBankcardResult([]byte(`{`)); err == nil {
+		t.Errorf("ParseBankcardResult() want error on invalid json")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// 语音识别结果 轮询间隔 和 最多轮询次数（微信建议 提交语音后 10s 内获取结果）
+var (
+	voicePollInterval = time.Second
+	voicePollAttempts = 10
+)
+
+/*
+RecoResult 语音识别结果
+
+Final 为 true 表示 最终结果；Err 不为 nil 表示 查询失败，之后不再有结果
+*/
+type RecoResult struct {
+	Text  string
+	Final bool
+	Err   error
+}
+
+/*
+RecognizeVoiceAsync 在后台 轮询 语音识别结果 (见 QueryRecoResultForText)，通过 channel 返回
+
+语音需 先通过 AddVoiceToRecoForText 提交；params 中未设置 lang 时 默认 zh_CN
+
+识别文本 变化时 发送 中间结果；连续两次 结果相同 或 达到最多轮询次数 时 发送 最终结果 并关闭 channel
+
+stdctx 取消时 停止轮询 并关闭 channel（进行中的请求 返回后生效）；调用方 不再读取结果时 必须取消 stdctx，否则 后台轮询 会阻塞在发送上
+
+	for result := range ai.RecognizeVoiceAsync(ctx, stdctx, "VOICE_ID", nil) {
+		fmt.Println(result.Text, result.Final, result.Err)
+	}
+*/
+func RecognizeVoiceAsync(ctx *offiaccount.OffiAccount, stdctx context.Context, voiceId string, params url.Values) <-chan RecoResult {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("voice_id", voiceId)
+	if q.Get("lang") == "" {
+		q.Set("lang", "zh_CN")
+	}
+
+	results := make(chan RecoResult, 1)
+	go func() {
+		defer close(results)
+
+		send := func(result RecoResult) bool {
+			select {
+			case results <- result:
+				return true
+			case <-stdctx.Done():
+				return false
+			}
+		}
+
+		var last string
+		for i := 0; i < voicePollAttempts; i++ {
+			if i > 0 {
+				select {
+				case <-time.After(voicePollInterval):
+				case <-stdctx.Done():
+					return
+				}
+			}
+
+			resp, err := QueryRecoResultForText(ctx, nil, q)
+			if err != nil {
+				if stdctx.Err() == nil {
+					send(RecoResult{Err: err})
+				}
+				return
+			}
+			result := struct {
+				Result string `json:"result"`
+			}{}
+			if err = json.Unmarshal(resp, &result); err != nil {
+				send(RecoResult{Err: err})
+				return
+			}
+
+			switch {
+			case result.Result != "" && result.Result == last:
+				send(RecoResult{Text: last, Final: true})
+				return
+			case result.Result != last:
+				last = result.Result
+				if !send(RecoResult{Text: last}) {
+					return
+				}
+			}
+		}
+		send(RecoResult{Text: last, Final: true})
+	}()
+	return results
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// newRecoTestServer 依次返回 replies，最后一个 重复返回；params 记录 最近一次请求的 查询参数
+func newRecoTestServer(t *testing.T, replies []string) (ctx *offiaccount.OffiAccount, params func() url.Values, closeFn func()) {
+	var lock sync.Mutex
+	var last url.Values
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiQueryRecoResultForText, func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		last = r.URL.Query()
+		reply := replies[len(replies)-1]
+		if calls < len(replies) {
+			reply = replies[calls]
+		}
+		calls++
+		lock.Unlock()
+		w.Write([]byte(reply))
+	})
+	svr := httptest.NewServer(handler)
+
+	ctx = offiaccount.New(offiaccount.Config{Appid: t.Name(), BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+	params = func() url.Values {
+		lock.Lock()
+		defer lock.Unlock()
+		return last
+	}
+	return ctx, params, svr.Close
+}
+
+func TestRecognizeVoiceAsync(t *testing.T) {
+	voicePollInterval = time.Millisecond
+	defer func() { voicePollInterval = time.Second }()
+
+	ctx, params, closeFn := newRecoTestServer(t, []string{`{"result":""}`, `{"result":"你好"}`, `{"result":"你好世界"}`, `{"result":"你好世界"}`})
+	defer closeFn()
+
+	var got []RecoResult
+	for result := range RecognizeVoiceAsync(ctx, context.Background(), "VOICE_ID", nil) {
+		got = append(got, result)
+	}
+
+	want := []RecoResult{{Text: "你好"}, {Text: "你好世界"}, {Text: "你好世界", Final: true}}
+	if len(got) != len(want) {
+		t.Fatalf("RecognizeVoiceAsync() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if p := params(); p.Get("voice_id") != "VOICE_ID" || p.Get("lang") != "zh_CN" || p.Get("access_token") != "ACCESS_TOKEN" {
+		t.Errorf("query params = %v", p)
+	}
+}
+
+func TestRecognizeVoiceAsync_Error(t *testing.T) {
+	ctx, params, closeFn := newRecoTestServer(t, []string{`{"errcode":-1,"errmsg":"system error"}`})
+	defer closeFn()
+
+	var got []RecoResult
+	for result := range RecognizeVoiceAsync(ctx, context.Background(), "VOICE_ID", url.Values{"lang": []string{"en_US"}}) {
+		got = append(got, result)
+	}
+	if len(got) != 1 || got[0].Err == nil {
+		t.Errorf("RecognizeVoiceAsync() = %+v, want one error", got)
+	}
+	if p := params(); p.Get("lang") != "en_US" {
+		t.Errorf("query params = %v", p)
+	}
+}
+
+func TestRecognizeVoiceAsync_Cancel(t *testing.T) {
+	ctx, _, closeFn := newRecoTestServer(t, []string{`{"result":"你好"}`, `{"result":"你好世界"}`, `{"result":"你好世界!"}`})
+	defer closeFn()
+
+	stdctx, cancel := context.WithCancel(context.Background())
+	results := RecognizeVoiceAsync(ctx, stdctx, "VOICE_ID", nil)
+	if result := <-results; result.Text != "你好" {
+		t.Errorf("first result = %+v", result)
+	}
+	// 不再读取 取消后 channel 关闭
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		// 取消前 可能已经轮询到 新的结果
+		for ok {
+			_, ok = <-results
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
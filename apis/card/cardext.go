@@ -0,0 +1,116 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/sign"
+	"github.com/fastwego/offiaccount/util"
+)
+
+/*
+CardExtParams JSSDK addCard 接口 cardExt 参数
+
+ApiTicket 为卡券 api_ticket (getticket?type=wx_card)，与 jsapi_ticket 不同
+
+See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#54
+*/
+type CardExtParams struct {
+	ApiTicket           string
+	CardId              string
+	Code                string // 指定的卡券 code 码，只能被领一次。自定义 code 模式的卡券必须填写，非自定义 code 和预存 code 模式的卡券不必填写
+	Openid              string // 指定领取者的 openid，只有该用户能领取。bind_openid 字段为 true 的卡券必须填写
+	Timestamp           string // 为空时 自动填充当前时间
+	NonceStr            string // 为空时 自动生成
+	FixedBeginTimestamp int64  // 卡券在第三方系统的实际领取时间，为东八区时间戳（UTC+8,精确到秒）
+	OuterStr            string // 领取渠道参数，用于标识本次领取的渠道值
+}
+
+/*
+SignCardExt 计算 cardExt 签名
+
+将 api_ticket、timestamp、card_id、code、openid、nonce_str 的 value 值进行字典序排序 拼接后 sha1
+*/
+func SignCardExt(params CardExtParams) (signature string) {
+	return sign.SortedSign(
+		params.ApiTicket,
+		params.Timestamp,
+		params.CardId,
+		params.Code,
+		params.Openid,
+		params.NonceStr,
+	)
+}
+
+/*
+BuildCardExt 生成 addCard 所需的 cardExt JSON 字符串
+
+	wx.addCard({
+	  cardList: [{ cardId: CARD_ID, cardExt: CARD_EXT }],
+	})
+*/
+func BuildCardExt(params CardExtParams) (cardExt string, err error) {
+	if params.ApiTicket == "" || params.CardId == "" {
+		err = fmt.Errorf("api_ticket and card_id required")
+		return
+	}
+	if params.Timestamp == "" {
+		params.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	if params.NonceStr == "" {
+		params.NonceStr = util.GetRandString(16)
+	}
+
+	data, err := json.Marshal(struct {
+		Code                string `json:"code,omitempty"`
+		Openid              string `json:"openid,omitempty"`
+		Timestamp           string `json:"timestamp"`
+		NonceStr            string `json:"nonce_str"`
+		FixedBeginTimestamp int64  `json:"fixed_begintimestamp,omitempty"`
+		OuterStr            string `json:"outer_str,omitempty"`
+		Signature           string `json:"signature"`
+	}{
+		Code:                params.Code,
+		Openid:              params.Openid,
+		Timestamp:           params.Timestamp,
+		NonceStr:            params.NonceStr,
+		FixedBeginTimestamp: params.FixedBeginTimestamp,
+		OuterStr:            params.OuterStr,
+		Signature:           SignCardExt(params),
+	})
+	if err != nil {
+		return
+	}
+
+	return string(data), nil
+}
+
+/*
+NewCardExt 生成 addCard 所需的 cardExt JSON 字符串，未指定 ApiTicket 时 通过 offiaccount.GetWxCardTicket 获取（带缓存）
+*/
+func NewCardExt(ctx *offiaccount.OffiAccount, params CardExtParams) (cardExt string, err error) {
+	if params.ApiTicket == "" {
+		params.ApiTicket, err = offiaccount.GetWxCardTicket(ctx)
+		if err != nil {
+			return
+		}
+	}
+	return BuildCardExt(params)
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/test"
+)
+
+var mockCardExtParams = CardExtParams{
+	ApiTicket: "sM4AOVdWfPE4DxkXGEs8VMCPGGVi4C3VM0P37wVUCFvkVAy_90u5h9nbSlYy3-Sl-HhTdfl2fzFy1AOcHKP7qg",
+	CardId:    "pjZ8Yt1XGILfi-FUsewpnnolGgZk",
+	Openid:    "ojZ8YtyVyr30HheH3CM73y7h4jJE",
+	Timestamp: "1404896688",
+	NonceStr:  "E0o2-at6NcC2OsJiQTlwlP",
+}
+
+func TestSignCardExt(t *testing.T) {
+	want := "c8bbdb237c36e536f9b6c3d2f85dac51c199f019"
+	if got := SignCardExt(mockCardExtParams); got != want {
+		t.Errorf("SignCardExt() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildCardExt(t *testing.T) {
+	cardExt, err := BuildCardExt(mockCardExtParams)
+	if err != nil {
+		t.Fatalf("BuildCardExt() error = %v", err)
+	}
+	want := `{"openid":"ojZ8YtyVyr30HheH3CM73y7h4jJE","timestamp":"1404896688","nonce_str":"E0o2-at6NcC2OsJiQTlwlP","signature":"c8bbdb237c36e536f9b6c3d2f85dac51c199f019"}`
+	if cardExt != want {
+		t.Errorf("BuildCardExt() = %v, want %v", cardExt, want)
+	}
+
+	// 自动填充 timestamp nonce_str
+	params := mockCardExtParams
+	params.Timestamp, params.NonceStr = "", ""
+	cardExt, err = BuildCardExt(params)
+	if err != nil {
+		t.Fatalf("BuildCardExt() error = %v", err)
+	}
+	ext := map[string]string{}
+	_ = json.Unmarshal([]byte(cardExt), &ext)
+	params.Timestamp, params.NonceStr = ext["timestamp"], ext["nonce_str"]
+	if params.Timestamp == "" || params.NonceStr == "" || ext["signature"] != SignCardExt(params) {
+		t.Errorf("BuildCardExt() = %v", cardExt)
+	}
+
+	if _, err = BuildCardExt(CardExtParams{}); err == nil {
+		t.Errorf("BuildCardExt() want error without api_ticket/card_id")
+	}
+}
+
+func TestNewCardExt(t *testing.T) {
+	test.MockSvrHandler.HandleFunc("/cgi-bin/ticket/getticket", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != offiaccount.TicketTypeWxCard {
+			t.Errorf("getticket type = %s, want wx_card", r.URL.Query().Get("type"))
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","ticket":"` + mockCardExtParams.ApiTicket + `","expires_in":7200}`))
+	})
+
+	params := mockCardExtParams
+	params.ApiTicket = ""
+	cardExt, err := NewCardExt(test.MockOffiAccount, params)
+	if err != nil {
+		t.Fatalf("NewCardExt() error = %v", err)
+	}
+
+	ext := struct {
+		Signature string `json:"signature"`
+	}{}
+	_ = json.Unmarshal([]byte(cardExt), &ext)
+	if ext.Signature != SignCardExt(mockCardExtParams) {
+		t.Errorf("NewCardExt() signature = %s, want %s", ext.Signature, SignCardExt(mockCardExtParams))
+	}
+}
@@ -218,3 +218,55 @@ func ExampleGetMemberCardDetail() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleBuildCardExt() {
+	cardExt, err := card.BuildCardExt(card.CardExtParams{
+		ApiTicket: "API_TICKET",
+		CardId:    "CARD_ID",
+		Openid:    "OPENID",
+	})
+
+	fmt.Println(cardExt, err)
+}
+
+func ExampleNewCardExt() {
+	var ctx *offiaccount.OffiAccount
+
+	// api_ticket 自动获取并缓存
+	cardExt, err := card.NewCardExt(ctx, card.CardExtParams{
+		CardId: "CARD_ID",
+		Openid: "OPENID",
+	})
+
+	fmt.Println(cardExt, err)
+}
+
+func ExampleParseConsumeCodeResult() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := card.ConsumeCode(ctx, []byte(`{"code":"12312313"}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := card.ParseConsumeCodeResult(resp)
+
+	fmt.Println(result.Openid, result.CardId, err)
+}
+
+func ExampleListUserCards() {
+	var ctx *offiaccount.OffiAccount
+
+	cards, err := card.ListUserCards(ctx, "OPENID", "")
+
+	fmt.Println(cards, err)
+}
+
+func ExampleSetTestWhitelistUsers() {
+	var ctx *offiaccount.OffiAccount
+
+	err := card.SetTestWhitelistUsers(ctx, []string{"o1Pj9jmZvwSyyyyyyBa4aULW2mA"}, []string{"eventowlss"})
+
+	fmt.Println(err)
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// ConsumeCodeResult 核销 code 的结果 (见 ConsumeCode)，Openid 为 领取该卡券的用户
+type ConsumeCodeResult struct {
+	CardId string
+	Openid string
+}
+
+// ParseConsumeCodeResult 解析 ConsumeCode 响应
+func ParseConsumeCodeResult(resp []byte) (result ConsumeCodeResult, err error) {
+	data := struct {
+		Card struct {
+			CardId string `json:"card_id"`
+		} `json:"card"`
+		Openid string `json:"openid"`
+	}{}
+	if err = json.Unmarshal(resp, &data); err != nil {
+		return
+	}
+	if data.Openid == "" || data.Card.CardId == "" {
+		return result, fmt.Errorf("card consume: openid/card_id not found in %s", resp)
+	}
+	return ConsumeCodeResult{CardId: data.Card.CardId, Openid: data.Openid}, nil
+}
+
+// UserCard 用户卡包中的卡券
+type UserCard struct {
+	CardId string `json:"card_id"`
+	Code   string `json:"code"`
+}
+
+/*
+ListUserCards 获取用户 已领取的卡券 (见 GetUserCardList)
+
+cardId 为空时 返回 该 appid 下全部卡券
+*/
+func ListUserCards(ctx *offiaccount.OffiAccount, openid string, cardId string) (cards []UserCard, err error) {
+	if openid == "" {
+		return nil, fmt.Errorf("card getcardlist: openid required")
+	}
+	payload, err := json.Marshal(struct {
+		Openid string `json:"openid"`
+		CardId string `json:"card_id,omitempty"`
+	}{Openid: openid, CardId: cardId})
+	if err != nil {
+		return
+	}
+
+	resp, err := GetUserCardList(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		CardList []UserCard `json:"card_list"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	return result.CardList, nil
+}
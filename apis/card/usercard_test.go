@@ -0,0 +1,71 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseConsumeCodeResult(t *testing.T) {
+	result, err := ParseConsumeCodeResult([]byte(`{"errcode":0,"errmsg":"ok","card":{"card_id":"pFS7Fjg8kV1IdDz01r4SQwMkuCKc"},"openid":"oFS7Fjl0WsZ9AMZqrI80nbIq8xrA"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CardId != "pFS7Fjg8kV1IdDz01r4SQwMkuCKc" || result.Openid != "oFS7Fjl0WsZ9AMZqrI80nbIq8xrA" {
+		t.Errorf("ParseConsumeCodeResult() = %+v", result)
+	}
+
+	if _, err = ParseConsumeCodeResult([]byte(`{"errcode":0,"errmsg":"ok"}`)); err == nil {
+		t.Error("ParseConsumeCodeResult() want error for missing openid")
+	}
+}
+
+func TestListUserCards(t *testing.T) {
+	var got string
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiGetUserCardList, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		got = string(payload)
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","card_list":[{"code":"xxx1434079154","card_id":"pkAD2jp3zJfVfHNv2JPWCw3TWcsA"},{"code":"xxx1434079155","card_id":"pkAD2jp3zJfVfHNv2JPWCw3TWcsA"}],"has_share_card":true}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestListUserCards", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	cards, err := ListUserCards(ctx, "OPENID", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `{"openid":"OPENID"}` {
+		t.Errorf("ListUserCards() payload = %s", got)
+	}
+	if len(cards) != 2 || cards[0].Code != "xxx1434079154" || cards[1].CardId != "pkAD2jp3zJfVfHNv2JPWCw3TWcsA" {
+		t.Errorf("ListUserCards() = %+v", cards)
+	}
+
+	if _, err = ListUserCards(ctx, "OPENID", "CARD_ID"); err != nil || got != `{"openid":"OPENID","card_id":"CARD_ID"}` {
+		t.Errorf("ListUserCards() payload = %s, err = %v", got, err)
+	}
+	if _, err = ListUserCards(ctx, "", ""); err == nil {
+		t.Error("ListUserCards() want error for empty openid")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+SetTestWhitelistUsers 设置测试白名单 (见 SetTestWhitelist)，白名单中的用户 可领取未通过审核的卡券
+
+openids 为 测试用户的 openid，usernames 为 测试用户的 微信号，至少指定一个；每次设置 会覆盖之前的白名单
+
+	err := card.SetTestWhitelistUsers(ctx, []string{"o1Pj9jmZvwSyyyyyyBa4aULW2mA"}, []string{"eventowlss"})
+*/
+func SetTestWhitelistUsers(ctx *offiaccount.OffiAccount, openids []string, usernames []string) (err error) {
+	if len(openids) == 0 && len(usernames) == 0 {
+		return fmt.Errorf("card testwhitelist: openid or username required")
+	}
+	payload, err := json.Marshal(struct {
+		Openid   []string `json:"openid,omitempty"`
+		Username []string `json:"username,omitempty"`
+	}{Openid: openids, Username: usernames})
+	if err != nil {
+		return
+	}
+
+	_, err = SetTestWhitelist(ctx, payload)
+	return
+}
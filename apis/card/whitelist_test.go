@@ -0,0 +1,62 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestSetTestWhitelistUsers(t *testing.T) {
+	var gotPayload string
+	var resp = `{"errcode":0,"errmsg":"ok"}`
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiSetTestWhitelist, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		gotPayload = string(payload)
+		w.Write([]byte(resp))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestSetTestWhitelistUsers", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	if err := SetTestWhitelistUsers(ctx, []string{"o1Pj9jmZvwSyyyyyyBa4aULW2mA"}, []string{"eventowlss"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"openid":["o1Pj9jmZvwSyyyyyyBa4aULW2mA"],"username":["eventowlss"]}`; gotPayload != want {
+		t.Errorf("payload = %s, want %s", gotPayload, want)
+	}
+
+	if err := SetTestWhitelistUsers(ctx, nil, []string{"eventowlss"}); err != nil || gotPayload != `{"username":["eventowlss"]}` {
+		t.Errorf("payload = %s, err = %v", gotPayload, err)
+	}
+
+	gotPayload = ""
+	if err := SetTestWhitelistUsers(ctx, nil, nil); err == nil || gotPayload != "" {
+		t.Errorf("SetTestWhitelistUsers() want error without request for empty users, payload = %s", gotPayload)
+	}
+
+	resp = `{"errcode":40127,"errmsg":"invalid user-card status"}`
+	err := SetTestWhitelistUsers(ctx, []string{"OPENID"}, nil)
+	if wxErr, ok := offiaccount.AsWXError(err); !ok || wxErr.Errcode != 40127 {
+		t.Errorf("SetTestWhitelistUsers() error = %v, want 40127", err)
+	}
+}
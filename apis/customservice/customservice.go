@@ -112,7 +112,7 @@ func UploadHeadImg(ctx *offiaccount.OffiAccount, media string, params url.Values
 		}
 
 	}()
-	return ctx.Client.HTTPPost(apiUploadHeadImg+"?"+params.Encode(), r, m.FormDataContentType())
+	return ctx.Client.HTTPPostWithParams(apiUploadHeadImg, params, r, m.FormDataContentType())
 }
 
 /*
@@ -203,7 +203,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Customer_Service/Session_c
 GET https://api.weixin.qq.com/customservice/kfsession/getsession?access_token=ACCESS_TOKEN&openid=OPENID
 */
 func KfSessionGet(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiKfSessionGet + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiKfSessionGet, params)
 }
 
 /*
@@ -216,7 +216,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Customer_Service/Session_c
 GET https://api.weixin.qq.com/customservice/kfsession/getsessionlist?access_token=ACCESS_TOKEN&kf_account=KFACCOUNT
 */
 func KfSessionGetList(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiKfSessionGetList + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiKfSessionGetList, params)
 }
 
 /*
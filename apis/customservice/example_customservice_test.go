@@ -145,3 +145,41 @@ func ExampleGetMsgList() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleSendText() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := customservice.SendText(ctx, "OPENID", "Hello World")
+
+	fmt.Println(resp, err)
+}
+
+func ExampleSendMiniProgramPage() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := customservice.SendMiniProgramPage(ctx, "OPENID", customservice.MiniProgramPage{
+		Title:        "title",
+		Appid:        "appid",
+		Pagepath:     "pagepath",
+		ThumbMediaId: "thumb_media_id",
+	})
+
+	fmt.Println(resp, err)
+}
+
+func ExampleParseOnlineKfList() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := customservice.GetOnlineKfList(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	list, err := customservice.ParseOnlineKfList(resp)
+	for _, kf := range list {
+		fmt.Println(kf.KfAccount, kf.WebOnline(), kf.AcceptedCase)
+	}
+
+	fmt.Println(err)
+}
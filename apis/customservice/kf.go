@@ -0,0 +1,58 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customservice
+
+import (
+	"encoding/json"
+)
+
+// 客服在线状态
+const (
+	KfStatusWebOnline = 1 // web 在线
+)
+
+// OnlineKf 在线客服 接待信息
+type OnlineKf struct {
+	KfAccount    string `json:"kf_account"`    // 完整客服帐号，格式为：帐号前缀@公众号微信号
+	Status       int    `json:"status"`        // 客服在线状态，目前为：1、web 在线
+	KfId         string `json:"kf_id"`         // 客服编号
+	AcceptedCase int    `json:"accepted_case"` // 客服当前正在接待的会话数
+}
+
+// WebOnline 客服是否 web 在线
+func (kf OnlineKf) WebOnline() bool {
+	return kf.Status == KfStatusWebOnline
+}
+
+/*
+ParseOnlineKfList 解析 GetOnlineKfList 的响应
+
+	{
+	  "kf_online_list": [
+	    {"kf_account": "test1@test", "status": 1, "kf_id": "1001", "accepted_case": 1},
+	    {"kf_account": "test2@test", "status": 1, "kf_id": "1002", "accepted_case": 2}
+	  ]
+	}
+*/
+func ParseOnlineKfList(resp []byte) (list []OnlineKf, err error) {
+	result := struct {
+		KfOnlineList []OnlineKf `json:"kf_online_list"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	return result.KfOnlineList, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customservice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOnlineKfList(t *testing.T) {
+	resp := []byte(`{"kf_online_list":[{"kf_account":"test1@test","status":1,"kf_id":"1001","accepted_case":1},{"kf_account":"test2@test","status":0,"kf_id":"1002","accepted_case":2}]}`)
+
+	got, err := ParseOnlineKfList(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OnlineKf{
+		{KfAccount: "test1@test", Status: 1, KfId: "1001", AcceptedCase: 1},
+		{KfAccount: "test2@test", Status: 0, KfId: "1002", AcceptedCase: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOnlineKfList() = %+v, want %+v", got, want)
+	}
+	if !got[0].WebOnline() || got[1].WebOnline() {
+		t.Errorf("WebOnline() = %v %v, want true false", got[0].WebOnline(), got[1].WebOnline())
+	}
+
+	if _, err = ParseOnlineKfList([]byte("{")); err == nil {
+		t.Error("ParseOnlineKfList() want error for invalid json")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customservice
+
+import (
+	"encoding/json"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// 客服消息类型
+const (
+	MsgTypeText            = "text"
+	MsgTypeImage           = "image"
+	MsgTypeVoice           = "voice"
+	MsgTypeVideo           = "video"
+	MsgTypeMusic           = "music"
+	MsgTypeNews            = "news"
+	MsgTypeMpNews          = "mpnews"
+	MsgTypeMpNewsArticle   = "mpnewsarticle"
+	MsgTypeMenu            = "msgmenu"
+	MsgTypeWxCard          = "wxcard"
+	MsgTypeMiniProgramPage = "miniprogrampage"
+)
+
+// Video 视频消息
+type Video struct {
+	MediaId      string `json:"media_id"`
+	ThumbMediaId string `json:"thumb_media_id"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Music 音乐消息
+type Music struct {
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MusicUrl     string `json:"musicurl"`
+	HQMusicUrl   string `json:"hqmusicurl"`
+	ThumbMediaId string `json:"thumb_media_id"`
+}
+
+// Article 图文消息（点击跳转到外链） 图文消息条数限制在1条以内
+type Article struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Url         string `json:"url"`
+	PicUrl      string `json:"picurl"`
+}
+
+// MenuItem 菜单消息 选项
+type MenuItem struct {
+	Id      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// Menu 菜单消息 用户点击选项后 会收到 内容为 Content 的文本消息 并携带 bizmsgmenuid
+type Menu struct {
+	HeadContent string     `json:"head_content"`
+	List        []MenuItem `json:"list"`
+	TailContent string     `json:"tail_content"`
+}
+
+// MiniProgramPage 小程序卡片
+type MiniProgramPage struct {
+	Title        string `json:"title"`
+	Appid        string `json:"appid"`
+	Pagepath     string `json:"pagepath"`
+	ThumbMediaId string `json:"thumb_media_id"`
+}
+
+// buildMessage 构造客服消息体 {"touser":"OPENID","msgtype":"TYPE","TYPE":{...}}
+func buildMessage(openid string, msgType string, content interface{}) (payload []byte, err error) {
+	return json.Marshal(map[string]interface{}{
+		"touser":  openid,
+		"msgtype": msgType,
+		msgType:   content,
+	})
+}
+
+func send(ctx *offiaccount.OffiAccount, openid string, msgType string, content interface{}) (resp []byte, err error) {
+	payload, err := buildMessage(openid, msgType, content)
+	if err != nil {
+		return
+	}
+	return SendMessage(ctx, payload)
+}
+
+// SendText 发送文本消息
+func SendText(ctx *offiaccount.OffiAccount, openid string, content string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeText, map[string]string{"content": content})
+}
+
+// SendImage 发送图片消息
+func SendImage(ctx *offiaccount.OffiAccount, openid string, mediaId string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeImage, map[string]string{"media_id": mediaId})
+}
+
+// SendVoice 发送语音消息
+func SendVoice(ctx *offiaccount.OffiAccount, openid string, mediaId string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeVoice, map[string]string{"media_id": mediaId})
+}
+
+// SendVideo 发送视频消息
+func SendVideo(ctx *offiaccount.OffiAccount, openid string, video Video) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeVideo, video)
+}
+
+// SendMusic 发送音乐消息
+func SendMusic(ctx *offiaccount.OffiAccount, openid string, music Music) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeMusic, music)
+}
+
+// SendNews 发送图文消息（点击跳转到外链）
+func SendNews(ctx *offiaccount.OffiAccount, openid string, article Article) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeNews, map[string][]Article{"articles": {article}})
+}
+
+// SendMpNews 发送图文消息（点击跳转到图文消息页面） mediaId 为永久图文素材
+func SendMpNews(ctx *offiaccount.OffiAccount, openid string, mediaId string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeMpNews, map[string]string{"media_id": mediaId})
+}
+
+// SendMpNewsArticle 发送图文消息（点击跳转到图文消息页面） articleId 为发布后的图文消息
+func SendMpNewsArticle(ctx *offiaccount.OffiAccount, openid string, articleId string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeMpNewsArticle, map[string]string{"article_id": articleId})
+}
+
+// SendMenu 发送菜单消息
+func SendMenu(ctx *offiaccount.OffiAccount, openid string, menu Menu) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeMenu, menu)
+}
+
+// SendWxCard 发送卡券
+func SendWxCard(ctx *offiaccount.OffiAccount, openid string, cardId string) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeWxCard, map[string]string{"card_id": cardId})
+}
+
+// SendMiniProgramPage 发送小程序卡片（要求小程序与公众号已关联）
+func SendMiniProgramPage(ctx *offiaccount.OffiAccount, openid string, page MiniProgramPage) (resp []byte, err error) {
+	return send(ctx, openid, MsgTypeMiniProgramPage, page)
+}
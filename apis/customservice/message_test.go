@@ -0,0 +1,58 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customservice
+
+import (
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		msgType     string
+		content     interface{}
+		wantPayload string
+	}{
+		{
+			name:        "text",
+			msgType:     MsgTypeText,
+			content:     map[string]string{"content": "Hello World"},
+			wantPayload: `{"msgtype":"text","text":{"content":"Hello World"},"touser":"OPENID"}`,
+		},
+		{
+			name:        "news",
+			msgType:     MsgTypeNews,
+			content:     map[string][]Article{"articles": {{Title: "Happy Day", Description: "Is Really A Happy Day", Url: "URL", PicUrl: "PIC_URL"}}},
+			wantPayload: `{"msgtype":"news","news":{"articles":[{"title":"Happy Day","description":"Is Really A Happy Day","url":"URL","picurl":"PIC_URL"}]},"touser":"OPENID"}`,
+		},
+		{
+			name:        "miniprogrampage",
+			msgType:     MsgTypeMiniProgramPage,
+			content:     MiniProgramPage{Title: "title", Appid: "appid", Pagepath: "pagepath", ThumbMediaId: "thumb_media_id"},
+			wantPayload: `{"miniprogrampage":{"title":"title","appid":"appid","pagepath":"pagepath","thumb_media_id":"thumb_media_id"},"msgtype":"miniprogrampage","touser":"OPENID"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := buildMessage("OPENID", tt.msgType, tt.content)
+			if err != nil {
+				t.Fatalf("buildMessage() error = %v", err)
+			}
+			if string(payload) != tt.wantPayload {
+				t.Errorf("buildMessage() got = %s, want %s", payload, tt.wantPayload)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"encoding/json"
+)
+
+// ArticleTotal 图文群发总数据 按 (图文, 统计日期) 展开后的一条记录
+type ArticleTotal struct {
+	RefDate string `json:"ref_date"` // 群发日期
+	Msgid   string `json:"msgid"`    // 消息id_图文消息的位置
+	Title   string `json:"title"`
+
+	StatDate                    string `json:"stat_date"`   // 统计日期
+	TargetUser                  int    `json:"target_user"` // 送达人数
+	IntPageReadUser             int    `json:"int_page_read_user"`
+	IntPageReadCount            int    `json:"int_page_read_count"`
+	OriPageReadUser             int    `json:"ori_page_read_user"`
+	OriPageReadCount            int    `json:"ori_page_read_count"`
+	ShareUser                   int    `json:"share_user"`
+	ShareCount                  int    `json:"share_count"`
+	AddToFavUser                int    `json:"add_to_fav_user"`
+	AddToFavCount               int    `json:"add_to_fav_count"`
+	IntPageFromSessionReadUser  int    `json:"int_page_from_session_read_user"`
+	IntPageFromSessionReadCount int    `json:"int_page_from_session_read_count"`
+	IntPageFromHistMsgReadUser  int    `json:"int_page_from_hist_msg_read_user"`
+	IntPageFromHistMsgReadCount int    `json:"int_page_from_hist_msg_read_count"`
+	IntPageFromFeedReadUser     int    `json:"int_page_from_feed_read_user"`
+	IntPageFromFeedReadCount    int    `json:"int_page_from_feed_read_count"`
+	IntPageFromFriendsReadUser  int    `json:"int_page_from_friends_read_user"`
+	IntPageFromFriendsReadCount int    `json:"int_page_from_friends_read_count"`
+	IntPageFromOtherReadUser    int    `json:"int_page_from_other_read_user"`
+	IntPageFromOtherReadCount   int    `json:"int_page_from_other_read_count"`
+	FeedShareFromSessionUser    int    `json:"feed_share_from_session_user"`
+	FeedShareFromSessionCnt     int    `json:"feed_share_from_session_cnt"`
+	FeedShareFromFeedUser       int    `json:"feed_share_from_feed_user"`
+	FeedShareFromFeedCnt        int    `json:"feed_share_from_feed_cnt"`
+	FeedShareFromOtherUser      int    `json:"feed_share_from_other_user"`
+	FeedShareFromOtherCnt       int    `json:"feed_share_from_other_cnt"`
+}
+
+/*
+ParseArticleTotal 解析 GetArticleTotal 的响应
+
+每篇图文的 details 按统计日期 展开为多条 ArticleTotal 记录
+
+	{
+	  "list": [
+	    {
+	      "ref_date": "2014-12-14",
+	      "msgid": "202457380_1",
+	      "title": "马航丢画记",
+	      "details": [
+	        {"stat_date": "2014-12-14", "target_user": 261917, "int_page_read_user": 23676, ...},
+	        {"stat_date": "2014-12-15", "target_user": 261917, "int_page_read_user": 30901, ...}
+	      ]
+	    }
+	  ]
+	}
+*/
+func ParseArticleTotal(resp []byte) (list []ArticleTotal, err error) {
+	result := struct {
+		List []struct {
+			RefDate string            `json:"ref_date"`
+			Msgid   string            `json:"msgid"`
+			Title   string            `json:"title"`
+			Details []json.RawMessage `json:"details"`
+		} `json:"list"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+
+	for _, article := range result.List {
+		for _, detail := range article.Details {
+			record := ArticleTotal{}
+			err = json.Unmarshal(detail, &record)
+			if err != nil {
+				return
+			}
+			record.RefDate, record.Msgid, record.Title = article.RefDate, article.Msgid, article.Title
+			list = append(list, record)
+		}
+	}
+	return
+}
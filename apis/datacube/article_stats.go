@@ -0,0 +1,113 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// 发布能力 获取已发布的图文信息
+const apiFreepublishGetArticle = "/cgi-bin/freepublish/getarticle"
+
+/*
+MsgidFromArticleUrl 由图文消息链接 计算 数据统计接口中的 msgid
+
+图文链接形如 http://mp.weixin.qq.com/s?__biz=MzA...&mid=2247483662&idx=1&sn=...，msgid 为 "mid_idx"，即 "2247483662_1"
+*/
+func MsgidFromArticleUrl(articleUrl string) (msgid string, err error) {
+	u, err := url.Parse(articleUrl)
+	if err != nil {
+		return
+	}
+	query := u.Query()
+	mid, idx := query.Get("mid"), query.Get("idx")
+	if mid == "" || idx == "" {
+		return "", fmt.Errorf("datacube: mid/idx not found in article url %s", articleUrl)
+	}
+	return mid + "_" + idx, nil
+}
+
+/*
+GetArticleStats 获取 已发布图文(发布能力 article_id) 的阅读/分享等数据
+
+发布能力的 article_id 与 数据统计的 msgid 格式不同，对应关系为：
+
+- 通过 /cgi-bin/freepublish/getarticle 获取 article_id 下每篇图文的 url
+- 由 url 中的 mid 和 idx 参数 得到 msgid "mid_idx" (见 MsgidFromArticleUrl)
+- 查询 refDate（图文发布日期，如 "2023-01-01"）的 GetArticleTotal，按 msgid 筛选
+
+GetArticleTotal 对每篇图文 返回发布后若干天的累计数据，这里每篇图文 只返回最新统计日期的一条记录，顺序与 图文在消息中的位置一致；没有统计数据的图文 不返回
+*/
+func GetArticleStats(ctx *offiaccount.OffiAccount, articleId string, refDate string) (stats []ArticleTotal, err error) {
+	payload, err := json.Marshal(map[string]string{"article_id": articleId})
+	if err != nil {
+		return
+	}
+	resp, err := ctx.Client.HTTPPost(apiFreepublishGetArticle, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	article := struct {
+		NewsItem []struct {
+			Title string `json:"title"`
+			Url   string `json:"url"`
+		} `json:"news_item"`
+	}{}
+	if err = json.Unmarshal(resp, &article); err != nil {
+		return
+	}
+
+	var msgids []string
+	for _, item := range article.NewsItem {
+		var msgid string
+		msgid, err = MsgidFromArticleUrl(item.Url)
+		if err != nil {
+			return
+		}
+		msgids = append(msgids, msgid)
+	}
+
+	payload, err = json.Marshal(map[string]string{"begin_date": refDate, "end_date": refDate})
+	if err != nil {
+		return
+	}
+	resp, err = GetArticleTotal(ctx, payload)
+	if err != nil {
+		return
+	}
+	list, err := ParseArticleTotal(resp)
+	if err != nil {
+		return
+	}
+
+	latest := map[string]ArticleTotal{}
+	for _, record := range list {
+		if current, ok := latest[record.Msgid]; !ok || record.StatDate > current.StatDate {
+			latest[record.Msgid] = record
+		}
+	}
+	for _, msgid := range msgids {
+		if record, ok := latest[msgid]; ok {
+			stats = append(stats, record)
+		}
+	}
+	return
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestMsgidFromArticleUrl(t *testing.T) {
+	msgid, err := MsgidFromArticleUrl("http://mp.weixin.qq.com/s?__biz=MzA5NjQ2NjM2NA==&mid=2247483662&idx=2&sn=abc#rd")
+	if err != nil || msgid != "2247483662_2" {
+		t.Errorf("MsgidFromArticleUrl() = %s, %v, want 2247483662_2", msgid, err)
+	}
+	if _, err = MsgidFromArticleUrl("https://mp.weixin.qq.com/s/abcdef"); err == nil {
+		t.Errorf("MsgidFromArticleUrl() want error for short url")
+	}
+}
+
+func TestGetArticleStats(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiFreepublishGetArticle, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		if string(payload) != `{"article_id":"ARTICLE_ID"}` {
+			t.Errorf("getarticle payload = %s", payload)
+		}
+		w.Write([]byte(`{"news_item":[{"title":"第一篇","url":"http://mp.weixin.qq.com/s?__biz=MzA&mid=2247483662&idx=1&sn=a"},{"title":"第二篇","url":"http://mp.weixin.qq.com/s?__biz=MzA&mid=2247483662&idx=2&sn=b"}]}`))
+	})
+	handler.HandleFunc(apiGetArticleTotal, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		if string(payload) != `{"begin_date":"2023-01-01","end_date":"2023-01-01"}` {
+			t.Errorf("getarticletotal payload = %s", payload)
+		}
+		w.Write([]byte(`{"list":[
+{"ref_date":"2023-01-01","msgid":"2247483662_1","title":"第一篇","details":[{"stat_date":"2023-01-01","int_page_read_user":100,"share_user":5},{"stat_date":"2023-01-02","int_page_read_user":150,"share_user":8}]},
+{"ref_date":"2023-01-01","msgid":"1000000001_1","title":"其他","details":[{"stat_date":"2023-01-01","int_page_read_user":1}]},
+{"ref_date":"2023-01-01","msgid":"2247483662_2","title":"第二篇","details":[{"stat_date":"2023-01-01","int_page_read_user":20,"share_user":1}]}
+]}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestGetArticleStats", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	stats, err := GetArticleStats(ctx, "ARTICLE_ID", "2023-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("GetArticleStats() len = %d, want 2: %+v", len(stats), stats)
+	}
+	if stats[0].Msgid != "2247483662_1" || stats[0].StatDate != "2023-01-02" || stats[0].IntPageReadUser != 150 || stats[0].ShareUser != 8 {
+		t.Errorf("stats[0] = %+v", stats[0])
+	}
+	if stats[1].Msgid != "2247483662_2" || stats[1].IntPageReadUser != 20 {
+		t.Errorf("stats[1] = %+v", stats[1])
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"testing"
+)
+
+func TestParseArticleTotal(t *testing.T) {
+	resp := []byte(`{"list":[{"ref_date":"2014-12-14","msgid":"202457380_1","title":"马航丢画记","details":[{"stat_date":"2014-12-14","target_user":261917,"int_page_read_user":23676,"int_page_read_count":25615,"ori_page_read_user":29,"ori_page_read_count":34,"share_user":122,"share_count":994,"add_to_fav_user":1,"add_to_fav_count":3},{"stat_date":"2014-12-15","target_user":261917,"int_page_read_user":30901,"share_user":145,"add_to_fav_user":2}]},{"ref_date":"2014-12-14","msgid":"202457380_2","title":"第二篇","details":[{"stat_date":"2014-12-14","target_user":261917,"int_page_read_user":100}]}]}`)
+
+	list, err := ParseArticleTotal(resp)
+	if err != nil {
+		t.Fatalf("ParseArticleTotal() error = %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("ParseArticleTotal() len = %d, want 3", len(list))
+	}
+
+	second := list[1]
+	if second.Msgid != "202457380_1" || second.Title != "马航丢画记" || second.RefDate != "2014-12-14" || second.StatDate != "2014-12-15" {
+		t.Errorf("ParseArticleTotal() got = %+v", second)
+	}
+	if second.IntPageReadUser != 30901 || second.ShareUser != 145 || second.AddToFavUser != 2 || second.TargetUser != 261917 {
+		t.Errorf("ParseArticleTotal() got = %+v", second)
+	}
+	if list[2].Msgid != "202457380_2" || list[2].IntPageReadUser != 100 {
+		t.Errorf("ParseArticleTotal() got = %+v", list[2])
+	}
+}
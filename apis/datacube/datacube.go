@@ -248,7 +248,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Analytics/Ad_Analysis.html
 GET https://api.weixin.qq.com/publisher/stat?action=publisher_adpos_general&access_token=ACCESS_TOKEN
 */
 func PublisherStat(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiPublisherStat + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiPublisherStat, params)
 }
 
 /*
@@ -183,3 +183,25 @@ func ExampleGetInterfaceSummaryHour() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleGetLatestFollowerCount() {
+	var ctx *offiaccount.OffiAccount
+
+	count, err := datacube.GetLatestFollowerCount(ctx)
+
+	fmt.Println(count, err)
+}
+
+func ExampleGetArticleStats() {
+	var ctx *offiaccount.OffiAccount
+
+	stats, err := datacube.GetArticleStats(ctx, "ARTICLE_ID", "2023-01-01")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, s := range stats {
+		fmt.Println(s.Title, s.IntPageReadUser, s.ShareUser)
+	}
+}
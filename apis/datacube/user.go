@@ -0,0 +1,82 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// 数据统计 日期格式 及 时区（北京时间）
+const refDateLayout = "2006-01-02"
+
+var beijing = time.FixedZone("CST", 8*3600)
+
+// UserCumulateDay 累计用户数据 一天的记录
+type UserCumulateDay struct {
+	RefDate      string `json:"ref_date"`      // 数据日期
+	CumulateUser int    `json:"cumulate_user"` // 总用户量
+}
+
+/*
+ParseUserCumulate 解析 GetUserCumulate 的响应
+
+	{
+	  "list": [
+	    {"ref_date": "2014-12-07", "cumulate_user": 1217056},
+	    {"ref_date": "2014-12-08", "cumulate_user": 1218102}
+	  ]
+	}
+*/
+func ParseUserCumulate(resp []byte) (list []UserCumulateDay, err error) {
+	result := struct {
+		List []UserCumulateDay `json:"list"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	return result.List, nil
+}
+
+/*
+GetLatestFollowerCount 获取 昨日（北京时间）的 总用户量
+
+数据统计 最早只能查询到昨天的数据
+*/
+func GetLatestFollowerCount(ctx *offiaccount.OffiAccount) (count int, err error) {
+	yesterday := time.Now().In(beijing).AddDate(0, 0, -1).Format(refDateLayout)
+
+	payload, err := json.Marshal(map[string]string{"begin_date": yesterday, "end_date": yesterday})
+	if err != nil {
+		return
+	}
+	resp, err := GetUserCumulate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	list, err := ParseUserCumulate(resp)
+	if err != nil {
+		return
+	}
+	if len(list) == 0 {
+		return 0, fmt.Errorf("datacube: no user cumulate data for %s", yesterday)
+	}
+	return list[len(list)-1].CumulateUser, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseUserCumulate(t *testing.T) {
+	resp := []byte(`{"list":[{"ref_date":"2014-12-07","cumulate_user":1217056},{"ref_date":"2014-12-08","cumulate_user":1218102}]}`)
+
+	list, err := ParseUserCumulate(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UserCumulateDay{{RefDate: "2014-12-07", CumulateUser: 1217056}, {RefDate: "2014-12-08", CumulateUser: 1218102}}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("ParseUserCumulate() = %+v, want %+v", list, want)
+	}
+}
+
+func TestGetLatestFollowerCount(t *testing.T) {
+	var payload string
+	var resp = `{"list":[{"ref_date":"2014-12-08","cumulate_user":1218102}]}`
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiGetUserCumulate, func(w http.ResponseWriter, r *http.Request) {
+		p, _ := ioutil.ReadAll(r.Body)
+		payload = string(p)
+		w.Write([]byte(resp))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestGetLatestFollowerCount", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	// 按 北京时间 计算昨天
+	yesterday := time.Now().In(time.FixedZone("CST", 8*3600)).AddDate(0, 0, -1).Format("2006-01-02")
+	count, err := GetLatestFollowerCount(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1218102 {
+		t.Errorf("GetLatestFollowerCount() = %d, want 1218102", count)
+	}
+	if want := `{"begin_date":"` + yesterday + `","end_date":"` + yesterday + `"}`; payload != want {
+		t.Errorf("payload = %s, want %s", payload, want)
+	}
+
+	resp = `{"list":[]}`
+	if _, err = GetLatestFollowerCount(ctx); err == nil {
+		t.Errorf("GetLatestFollowerCount() want error for empty list")
+	}
+}
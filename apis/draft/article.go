@@ -0,0 +1,92 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+IntBool 以 0/1 表示的 布尔值，如 show_cover_pic、need_open_comment
+
+序列化为 0/1；反序列化时 兼容 0/1、"0"/"1" 以及 true/false
+*/
+type IntBool bool
+
+func (b IntBool) MarshalJSON() ([]byte, error) {
+	if b {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+func (b *IntBool) UnmarshalJSON(data []byte) error {
+	switch string(bytes.Trim(data, `"`)) {
+	case "1", "true":
+		*b = true
+	case "0", "false", "", "null":
+		*b = false
+	default:
+		return fmt.Errorf("draft: invalid bool value %s", data)
+	}
+	return nil
+}
+
+// Article 草稿中的 图文消息
+type Article struct {
+	Title              string  `json:"title"`
+	Author             string  `json:"author"`
+	Digest             string  `json:"digest"`
+	Content            string  `json:"content"` // 图文消息的具体内容 支持 HTML 标签
+	ContentSourceUrl   string  `json:"content_source_url"`
+	ThumbMediaId       string  `json:"thumb_media_id"`
+	ShowCoverPic       IntBool `json:"show_cover_pic"`
+	NeedOpenComment    IntBool `json:"need_open_comment"`
+	OnlyFansCanComment IntBool `json:"only_fans_can_comment"`
+	Url                string  `json:"url,omitempty"`       // 草稿的临时链接 仅 Get 返回
+	ThumbUrl           string  `json:"thumb_url,omitempty"` // 封面图片的 URL 仅 Get 返回
+}
+
+// Draft 草稿
+type Draft struct {
+	NewsItem []Article `json:"news_item"`
+}
+
+/*
+ParseDraft 解析 Get 响应
+
+	{
+	  "news_item": [
+	    {
+	      "title": "TITLE",
+	      "author": "AUTHOR",
+	      "digest": "DIGEST",
+	      "content": "CONTENT",
+	      "content_source_url": "CONTENT_SOURCE_URL",
+	      "thumb_media_id": "THUMB_MEDIA_ID",
+	      "show_cover_pic": 1,
+	      "need_open_comment": 0,
+	      "only_fans_can_comment": 0,
+	      "url": "URL"
+	    }
+	  ]
+	}
+*/
+func ParseDraft(resp []byte) (draft Draft, err error) {
+	err = json.Unmarshal(resp, &draft)
+	return
+}
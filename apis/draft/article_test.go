@@ -0,0 +1,77 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draft
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestParseDraft(t *testing.T) {
+	var gotPayload string
+	test.MockSvrHandler.HandleFunc(apiGet, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotPayload = string(body)
+		w.Write([]byte(`{"news_item":[{"title":"TITLE","author":"AUTHOR","digest":"DIGEST","content":"<p>CONTENT</p>","content_source_url":"CONTENT_SOURCE_URL","thumb_media_id":"THUMB_MEDIA_ID","show_cover_pic":1,"need_open_comment":0,"only_fans_can_comment":"1","url":"URL"}]}`))
+	})
+
+	resp, err := Get(test.MockOffiAccount, []byte(`{"media_id":"MEDIA_ID"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPayload != `{"media_id":"MEDIA_ID"}` {
+		t.Errorf("Get() payload = %s", gotPayload)
+	}
+
+	draft, err := ParseDraft(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(draft.NewsItem) != 1 {
+		t.Fatalf("ParseDraft() = %+v", draft)
+	}
+	article := draft.NewsItem[0]
+	if article.Title != "TITLE" || article.Content != "<p>CONTENT</p>" || article.ThumbMediaId != "THUMB_MEDIA_ID" || article.Url != "URL" {
+		t.Errorf("ParseDraft() article = %+v", article)
+	}
+	if !article.ShowCoverPic || article.NeedOpenComment || !article.OnlyFansCanComment {
+		t.Errorf("ParseDraft() int bool fields = %v %v %v", article.ShowCoverPic, article.NeedOpenComment, article.OnlyFansCanComment)
+	}
+
+	// 编辑后 再次提交 布尔字段 输出为 0/1
+	article.Url = ""
+	data, _ := json.Marshal(article)
+	want := `{"title":"TITLE","author":"AUTHOR","digest":"DIGEST","content":"\u003cp\u003eCONTENT\u003c/p\u003e","content_source_url":"CONTENT_SOURCE_URL","thumb_media_id":"THUMB_MEDIA_ID","show_cover_pic":1,"need_open_comment":0,"only_fans_can_comment":1}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	if _, err = ParseDraft([]byte(`{"news_item":[{"show_cover_pic":2}]}`)); err == nil {
+		t.Error("ParseDraft() want error for invalid show_cover_pic")
+	}
+}
+
+func TestIntBool(t *testing.T) {
+	for data, want := range map[string]IntBool{`1`: true, `0`: false, `"1"`: true, `true`: true, `false`: false, `null`: false} {
+		var got IntBool
+		if err := json.Unmarshal([]byte(data), &got); err != nil || got != want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", data, got, err, want)
+		}
+	}
+}
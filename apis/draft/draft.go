@@ -0,0 +1,70 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package draft 草稿箱
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const (
+	apiSwitch = "/cgi-bin/draft/switch"
+	apiGet    = "/cgi-bin/draft/get"
+)
+
+/*
+草稿箱开关设置
+
+开启草稿箱 后 将不再支持 图文素材 相关接口，且 不可逆；checkonly 为 true 时 仅检查状态，不开启
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Temporary_MP_Switch.html
+
+POST https://api.weixin.qq.com/cgi-bin/draft/switch?access_token=ACCESS_TOKEN&checkonly=1
+*/
+func Switch(ctx *offiaccount.OffiAccount, checkonly bool) (enabled bool, err error) {
+	params := url.Values{}
+	if checkonly {
+		params.Add("checkonly", "1")
+	}
+	resp, err := ctx.Client.HTTPPostWithParams(apiSwitch, params, nil, "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		IsOpen int `json:"is_open"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	return result.IsOpen == 1, nil
+}
+
+/*
+获取草稿
+
+新增草稿后，开发者可以根据草稿指定的字段来下载草稿
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Draft_Box/Get_draft.html
+
+POST https://api.weixin.qq.com/cgi-bin/draft/get?access_token=ACCESS_TOKEN
+*/
+func Get(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiGet, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
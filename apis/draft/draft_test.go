@@ -0,0 +1,53 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draft
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestSwitch(t *testing.T) {
+	var checkonly string
+	test.MockSvrHandler.HandleFunc(apiSwitch, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") == "" {
+			t.Errorf("draft/switch should carry access_token")
+		}
+		checkonly = r.URL.Query().Get("checkonly")
+		if checkonly == "1" {
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok","is_open":0}`))
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","is_open":1}`))
+	})
+
+	enabled, err := Switch(test.MockOffiAccount, true)
+	if err != nil || enabled || checkonly != "1" {
+		t.Errorf("Switch(checkonly) = %v, %v, checkonly = %q", enabled, err, checkonly)
+	}
+
+	enabled, err = Switch(test.MockOffiAccount, false)
+	if err != nil || !enabled || checkonly != "" {
+		t.Errorf("Switch() = %v, %v, checkonly = %q", enabled, err, checkonly)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draft_test
+
+import (
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/apis/draft"
+)
+
+func ExampleSwitch() {
+	var ctx *offiaccount.OffiAccount
+
+	// 仅检查 是否已开启
+	enabled, err := draft.Switch(ctx, true)
+
+	fmt.Println(enabled, err)
+}
+
+func ExampleParseDraft() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := draft.Get(ctx, []byte(`{"media_id":"MEDIA_ID"}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	d, err := draft.ParseDraft(resp)
+	for _, article := range d.NewsItem {
+		fmt.Println(article.Title, article.ShowCoverPic, article.Content)
+	}
+	fmt.Println(err)
+}
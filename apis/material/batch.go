@@ -0,0 +1,105 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// BatchgetLimit 获取素材列表 每次最多返回 20 个素材
+const BatchgetLimit = 20
+
+// Item 素材列表中的一项 图文素材的内容未解析 保留在 Content 中
+type Item struct {
+	MediaId    string          `json:"media_id"`
+	Name       string          `json:"name"`
+	UpdateTime int64           `json:"update_time"` // 最后更新时间 unix 时间戳
+	Url        string          `json:"url"`
+	Content    json.RawMessage `json:"content"`
+}
+
+/*
+EachMaterial 分页遍历 某类型的全部永久素材
+
+fn 返回 false 时 停止遍历
+*/
+func EachMaterial(ctx *offiaccount.OffiAccount, mediaType string, fn func(item Item) bool) (err error) {
+	for offset := 0; ; {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type":   mediaType,
+			"offset": offset,
+			"count":  BatchgetLimit,
+		})
+		var resp []byte
+		resp, err = BatchgetMaterial(ctx, payload)
+		if err != nil {
+			return
+		}
+
+		result := struct {
+			TotalCount int    `json:"total_count"`
+			ItemCount  int    `json:"item_count"`
+			Item       []Item `json:"item"`
+		}{}
+		err = json.Unmarshal(resp, &result)
+		if err != nil {
+			return
+		}
+
+		for _, item := range result.Item {
+			if !fn(item) {
+				return
+			}
+		}
+
+		offset += len(result.Item)
+		if len(result.Item) == 0 || offset >= result.TotalCount {
+			return
+		}
+	}
+}
+
+/*
+DeleteAllOfType 删除 某类型中 最后更新时间早于 olderThan 的全部永久素材
+
+先遍历出全部待删除素材 再逐个删除，避免删除过程中 分页偏移错乱
+
+删除失败时 停止并返回 已删除的个数
+*/
+func DeleteAllOfType(ctx *offiaccount.OffiAccount, mediaType string, olderThan time.Time) (deleted int, err error) {
+	var mediaIds []string
+	err = EachMaterial(ctx, mediaType, func(item Item) bool {
+		if time.Unix(item.UpdateTime, 0).Before(olderThan) {
+			mediaIds = append(mediaIds, item.MediaId)
+		}
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	for _, mediaId := range mediaIds {
+		payload, _ := json.Marshal(map[string]string{"media_id": mediaId})
+		_, err = DelMaterial(ctx, payload)
+		if err != nil {
+			return
+		}
+		deleted++
+	}
+	return
+}
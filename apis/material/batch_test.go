@@ -0,0 +1,103 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// newBatchTestServer 模拟 45 个图片素材 第 i 个的更新时间为 i*100，删除 MEDIA_15 时 返回错误
+func newBatchTestServer(t *testing.T, deletedIds *[]string) (*offiaccount.OffiAccount, func()) {
+	const total = 45
+
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiBatchgetMaterial, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			Type   string `json:"type"`
+			Offset int    `json:"offset"`
+			Count  int    `json:"count"`
+		}{}
+		_ = json.Unmarshal(payload, &req)
+		if req.Type != MediaTypeImage || req.Count != BatchgetLimit {
+			t.Errorf("batchget payload = %s", payload)
+		}
+
+		items := []Item{}
+		for i := req.Offset; i < total && i < req.Offset+req.Count; i++ {
+			items = append(items, Item{MediaId: fmt.Sprintf("MEDIA_%d", i), UpdateTime: int64(i * 100)})
+		}
+		resp, _ := json.Marshal(map[string]interface{}{"total_count": total, "item_count": len(items), "item": items})
+		_, _ = w.Write(resp)
+	})
+	handler.HandleFunc(apiDelMaterial, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		req := map[string]string{}
+		_ = json.Unmarshal(payload, &req)
+		if req["media_id"] == "MEDIA_15" {
+			_, _ = w.Write([]byte(`{"errcode":40007,"errmsg":"invalid media_id"}`))
+			return
+		}
+		*deletedIds = append(*deletedIds, req["media_id"])
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	svr := httptest.NewServer(handler)
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestBatch", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+	return ctx, svr.Close
+}
+
+func TestEachMaterial(t *testing.T) {
+	ctx, done := newBatchTestServer(t, nil)
+	defer done()
+
+	var count int
+	err := EachMaterial(ctx, MediaTypeImage, func(item Item) bool {
+		if item.MediaId != fmt.Sprintf("MEDIA_%d", count) {
+			t.Errorf("item %d = %s", count, item.MediaId)
+		}
+		count++
+		return count < 30
+	})
+	if err != nil || count != 30 {
+		t.Errorf("EachMaterial() count = %d, err = %v, want 30 and stop", count, err)
+	}
+}
+
+func TestDeleteAllOfType(t *testing.T) {
+	var deletedIds []string
+	ctx, done := newBatchTestServer(t, &deletedIds)
+	defer done()
+
+	deleted, err := DeleteAllOfType(ctx, MediaTypeImage, time.Unix(1000, 0))
+	if err != nil || deleted != 10 || len(deletedIds) != 10 || deletedIds[9] != "MEDIA_9" {
+		t.Errorf("DeleteAllOfType() deleted = %d %v, err = %v, want MEDIA_0 ~ MEDIA_9", deleted, deletedIds, err)
+	}
+
+	deletedIds = nil
+	deleted, err = DeleteAllOfType(ctx, MediaTypeImage, time.Unix(2000, 0))
+	if err == nil || deleted != 15 {
+		t.Errorf("DeleteAllOfType() deleted = %d, err = %v, want 15 and error", deleted, err)
+	}
+}
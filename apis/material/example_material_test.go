@@ -16,7 +16,11 @@ package material_test
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"os"
+	"time"
 
 	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/offiaccount/apis/material"
@@ -119,3 +123,57 @@ func ExampleBatchgetMaterial() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleReplyImageFromFile() {
+	var ctx *offiaccount.OffiAccount
+
+	file, err := os.Open("/path/to/image.jpg")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer file.Close()
+
+	reply, err := material.ReplyImageFromFile(ctx, file)
+
+	fmt.Println(reply, err)
+}
+
+func ExampleDeleteAllOfType() {
+	var ctx *offiaccount.OffiAccount
+
+	// 删除 90 天前的图片素材
+	deleted, err := material.DeleteAllOfType(ctx, material.MediaTypeImage, time.Now().AddDate(0, 0, -90))
+
+	fmt.Println(deleted, err)
+}
+
+func ExampleMediaStream() {
+	var ctx *offiaccount.OffiAccount
+
+	http.HandleFunc("/media", func(w http.ResponseWriter, r *http.Request) {
+		_, err := material.MediaStream(ctx, r.URL.Query().Get("media_id"), w)
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+}
+
+func ExampleUploadImgBatch() {
+	var ctx *offiaccount.OffiAccount
+
+	var imgs []io.Reader
+	for _, name := range []string{"/path/to/1.jpg", "/path/to/2.png"} {
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer file.Close()
+		imgs = append(imgs, file)
+	}
+
+	urls, err := material.UploadImgBatch(ctx, imgs, 4)
+
+	fmt.Println(urls, err)
+}
@@ -96,7 +96,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/Asset_Management/Get_tempo
 GET https://api.weixin.qq.com/cgi-bin/media/get/jssdk?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
 */
 func MediaGetJssdk(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiMediaGetJssdk + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiMediaGetJssdk, params)
 }
 
 /*
@@ -0,0 +1,124 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/type/type_message"
+)
+
+// 素材类型
+const (
+	MediaTypeImage = "image"
+	MediaTypeVoice = "voice"
+	MediaTypeVideo = "video"
+	MediaTypeThumb = "thumb" // 仅临时素材
+	MediaTypeNews  = "news"  // 仅永久素材
+)
+
+/*
+MediaUploadReader 从 io.Reader 上传临时素材 (见 MediaUpload)
+
+filename 用于告知微信服务器 文件格式，例如 image.jpg
+*/
+func MediaUploadReader(ctx *offiaccount.OffiAccount, mediaType string, filename string, media io.Reader) (resp []byte, err error) {
+	params := url.Values{}
+	params.Add("type", mediaType)
+	return postMedia(ctx, offiaccount.AppendQuery(apiMediaUpload, params), filename, media)
+}
+
+// postMedia 以 multipart 表单 media 字段 上传 文件内容
+func postMedia(ctx *offiaccount.OffiAccount, uri string, filename string, media io.Reader) (resp []byte, err error) {
+	r, w := io.Pipe()
+	defer r.Close() // 请求未发出时 避免写入 goroutine 阻塞
+	m := multipart.NewWriter(w)
+	go func() {
+		part, err := m.CreateFormFile("media", filename)
+		if err == nil {
+			_, err = io.Copy(part, media)
+		}
+		if err == nil {
+			err = m.Close()
+		}
+		_ = w.CloseWithError(err)
+	}()
+
+	return ctx.Client.HTTPPost(uri, r, m.FormDataContentType())
+}
+
+// 根据图片内容 推断文件名
+func imageFilename(img *bufio.Reader) string {
+	head, _ := img.Peek(512)
+	switch http.DetectContentType(head) {
+	case "image/png":
+		return "image.png"
+	case "image/gif":
+		return "image.gif"
+	default:
+		return "image.jpg"
+	}
+}
+
+/*
+ReplyImageFromFile 上传图片为临时素材 并构造 图片回复消息
+
+返回的消息 已设置 MsgType/CreateTime/MediaId，调用方需设置 ToUserName/FromUserName 后回复
+
+	reply, err := material.ReplyImageFromFile(ctx, file)
+	if err != nil {
+		return
+	}
+	reply.ToUserName = type_message.CDATA(msg.FromUserName)
+	reply.FromUserName = type_message.CDATA(msg.ToUserName)
+	err = ctx.Server.Response(w, r, reply)
+*/
+func ReplyImageFromFile(ctx *offiaccount.OffiAccount, img io.Reader) (reply *type_message.ReplyMessageImage, err error) {
+	buffered := bufio.NewReader(img)
+	resp, err := MediaUploadReader(ctx, MediaTypeImage, imageFilename(buffered), buffered)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Type    string `json:"type"`
+		MediaId string `json:"media_id"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	if result.MediaId == "" {
+		return nil, fmt.Errorf("media upload: no media_id in response %s", resp)
+	}
+
+	reply = &type_message.ReplyMessageImage{
+		ReplyMessage: type_message.ReplyMessage{
+			CreateTime: strconv.FormatInt(time.Now().Unix(), 10),
+			MsgType:    type_message.ReplyMsgTypeImage,
+		},
+	}
+	reply.Image.MediaId = type_message.CDATA(result.MediaId)
+	return
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestReplyImageFromFile(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 16)...)
+
+	// 独立的 Mock Server 校验上传内容
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiMediaUpload, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != MediaTypeImage {
+			t.Errorf("type = %s, want image", r.URL.Query().Get("type"))
+		}
+		file, header, err := r.FormFile("media")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(file)
+		if header.Filename != "image.png" || !bytes.Equal(data, png) {
+			t.Errorf("uploaded %s %v, want image.png %v", header.Filename, data, png)
+		}
+		_, _ = w.Write([]byte(`{"type":"image","media_id":"MEDIA_ID","created_at":123456789}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	originUrl := offiaccount.WXServerUrl
+	offiaccount.WXServerUrl = svr.URL
+	defer func() { offiaccount.WXServerUrl = originUrl }()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestReplyImageFromFile"}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	reply, err := ReplyImageFromFile(ctx, bytes.NewReader(png))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Image.MediaId != "MEDIA_ID" || reply.MsgType != "image" || reply.CreateTime == "" {
+		t.Errorf("ReplyImageFromFile() = %+v", reply)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+MediaStream 获取临时素材 (见 MediaGet)，将响应体 直接写入 w，不在内存中缓存整个文件
+
+返回 响应头，可从中读取 Content-Type、Content-Disposition 等；接口返回错误时 返回 *offiaccount.WXError，不写入 w
+
+w 为 http.ResponseWriter 时 写入前 会先设置 Content-Type、Content-Length、Content-Disposition 响应头
+
+注意：视频素材 返回的是 包含 video_url 的 JSON，同样会写入 w
+
+	func handler(w http.ResponseWriter, r *http.Request) {
+		header, err := material.MediaStream(ctx, r.URL.Query().Get("media_id"), w)
+		...
+	}
+*/
+func MediaStream(ctx *offiaccount.OffiAccount, mediaId string, w io.Writer) (header http.Header, err error) {
+	params := url.Values{}
+	params.Add("media_id", mediaId)
+	response, err := ctx.Client.DoRaw(http.MethodGet, offiaccount.AppendQuery(apiMediaGet, params), nil, "")
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	header = response.Header
+	if response.StatusCode != http.StatusOK {
+		return header, fmt.Errorf("Status %s", response.Status)
+	}
+
+	// 错误 以 JSON 返回，体积很小
+	contentType := response.Header.Get("Content-Type")
+	if strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/plain") {
+		resp, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return header, err
+		}
+		wxErr, err := offiaccount.ParseWXError(resp)
+		if err == nil && wxErr.Errcode != 0 {
+			wxErr.Raw = string(resp)
+			return header, &wxErr
+		}
+		copyContentHeader(w, header)
+		_, err = w.Write(resp)
+		return header, err
+	}
+
+	copyContentHeader(w, header)
+	_, err = io.Copy(w, response.Body)
+	return
+}
+
+// copyContentHeader w 为 http.ResponseWriter 时 传递 素材文件 相关的响应头
+func copyContentHeader(w io.Writer, header http.Header) {
+	rw, ok := w.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	for _, key := range []string{"Content-Type", "Content-Length", "Content-Disposition"} {
+		if value := header.Get(key); value != "" {
+			rw.Header().Set(key, value)
+		}
+	}
+}
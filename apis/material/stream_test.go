@@ -0,0 +1,75 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestMediaStream(t *testing.T) {
+	image := strings.Repeat("PNG", 1024)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("media_id") {
+		case "IMAGE":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Disposition", `attachment; filename="a.png"`)
+			_, _ = w.Write([]byte(image))
+		case "VIDEO":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"video_url":"http://example.com/a.mp4"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"errcode":40007,"errmsg":"invalid media_id"}`))
+		}
+	}))
+	defer svr.Close()
+
+	app := offiaccount.New(offiaccount.Config{Appid: "TestMediaStream", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+
+	var buf bytes.Buffer
+	header, err := MediaStream(app, "IMAGE", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != image || header.Get("Content-Disposition") != `attachment; filename="a.png"` {
+		t.Errorf("MediaStream() wrote %d bytes, header = %v", buf.Len(), header)
+	}
+
+	// 透传 响应头
+	rec := httptest.NewRecorder()
+	if _, err = MediaStream(app, "IMAGE", rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Body.String() != image || rec.Header().Get("Content-Type") != "image/png" || rec.Header().Get("Content-Disposition") != `attachment; filename="a.png"` {
+		t.Errorf("MediaStream() ResponseWriter header = %v", rec.Header())
+	}
+
+	buf.Reset()
+	if _, err = MediaStream(app, "VIDEO", &buf); err != nil || buf.String() != `{"video_url":"http://example.com/a.mp4"}` {
+		t.Errorf("MediaStream() video = %s, err = %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	_, err = MediaStream(app, "INVALID", &buf)
+	if wxErr, ok := err.(*offiaccount.WXError); !ok || wxErr.Errcode != 40007 || buf.Len() != 0 {
+		t.Errorf("MediaStream() error = %v, wrote %s", err, buf.String())
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// UploadError 第 Index 张图片 上传失败
+type UploadError struct {
+	Index int
+	Err   error
+}
+
+func (e UploadError) Error() string {
+	return fmt.Sprintf("image %d: %s", e.Index, e.Err)
+}
+
+func (e UploadError) Unwrap() error {
+	return e.Err
+}
+
+// UploadErrors 批量上传时 失败图片的集合 按 Index 排序，未出现在列表中的图片 均已成功
+type UploadErrors []UploadError
+
+func (e UploadErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, uploadErr := range e {
+		msgs = append(msgs, uploadErr.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+/*
+UploadImgBatch 并发上传 图文消息内的图片 (见 MediaUploadImg)，返回的 urls 与 imgs 顺序一致
+
+concurrency 为 同时上传的最大数量，小于 1 时 为 1；每次上传 同样会等待 Config.RateLimiter 放行
+
+部分图片上传失败时 对应的 url 为空，并返回 UploadErrors
+
+	urls, err := material.UploadImgBatch(ctx, imgs, 4)
+	var uploadErrs material.UploadErrors
+	if errors.As(err, &uploadErrs) {
+		for _, e := range uploadErrs {
+			fmt.Println(e.Index, e.Err)
+		}
+	}
+*/
+func UploadImgBatch(ctx *offiaccount.OffiAccount, imgs []io.Reader, concurrency int) (urls []string, err error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(imgs) {
+		concurrency = len(imgs)
+	}
+
+	urls = make([]string, len(imgs))
+	errs := make([]error, len(imgs))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				urls[i], errs[i] = uploadImg(ctx, imgs[i])
+			}
+		}()
+	}
+	for i := range imgs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var uploadErrs UploadErrors
+	for i, uploadErr := range errs {
+		if uploadErr != nil {
+			uploadErrs = append(uploadErrs, UploadError{Index: i, Err: uploadErr})
+		}
+	}
+	if len(uploadErrs) > 0 {
+		return urls, uploadErrs
+	}
+	return urls, nil
+}
+
+// uploadImg 上传 img 按内容 识别 图片格式 作为文件名，返回 图片 url
+func uploadImg(ctx *offiaccount.OffiAccount, img io.Reader) (url string, err error) {
+	buffered := bufio.NewReader(img)
+	resp, err := postMedia(ctx, apiMediaUploadImg, imageFilename(buffered), buffered)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Url string `json:"url"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	if result.Url == "" {
+		return "", fmt.Errorf("material uploadimg: url not found in %s", resp)
+	}
+	return result.Url, nil
+}
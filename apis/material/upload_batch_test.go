@@ -0,0 +1,100 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package material
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestUploadImgBatch(t *testing.T) {
+	var running, maxRunning int32
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiMediaUploadImg, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		file, header, err := r.FormFile("media")
+		if err != nil {
+			w.Write([]byte(`{"errcode":41005,"errmsg":"media data missing"}`))
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		name := string(data)
+		// 越靠前的图片 上传越慢，验证结果顺序
+		time.Sleep(time.Duration(10-len(name)) * time.Millisecond)
+		if strings.HasPrefix(name, "bad") {
+			w.Write([]byte(`{"errcode":40009,"errmsg":"invalid image size"}`))
+			return
+		}
+		resp, _ := json.Marshal(map[string]string{"url": "http://mmbiz.qpic.cn/" + header.Filename + "/" + name})
+		w.Write(resp)
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := offiaccount.New(offiaccount.Config{Appid: "TestUploadImgBatch", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	names := []string{"a", "bb", "bad3", "dddd", "eeeee", "bad66"}
+	imgs := make([]io.Reader, len(names))
+	for i, name := range names {
+		imgs[i] = strings.NewReader(name)
+	}
+
+	urls, err := UploadImgBatch(app, imgs, 2)
+	want := []string{"http://mmbiz.qpic.cn/image.jpg/a", "http://mmbiz.qpic.cn/image.jpg/bb", "", "http://mmbiz.qpic.cn/image.jpg/dddd", "http://mmbiz.qpic.cn/image.jpg/eeeee", ""}
+	for i := range want {
+		if urls[i] != want[i] {
+			t.Errorf("urls[%d] = %s, want %s", i, urls[i], want[i])
+		}
+	}
+	if maxRunning > 2 {
+		t.Errorf("max concurrent uploads = %d, want at most 2", maxRunning)
+	}
+
+	var uploadErrs UploadErrors
+	if !errors.As(err, &uploadErrs) || len(uploadErrs) != 2 || uploadErrs[0].Index != 2 || uploadErrs[1].Index != 5 {
+		t.Fatalf("UploadImgBatch() error = %v, want errors for image 2 and 5", err)
+	}
+	if wxErr, ok := offiaccount.AsWXError(uploadErrs[0]); !ok || wxErr.Errcode != 40009 {
+		t.Errorf("uploadErrs[0] = %v, want errcode 40009", uploadErrs[0])
+	}
+
+	if urls, err = UploadImgBatch(app, nil, 0); err != nil || len(urls) != 0 {
+		t.Errorf("UploadImgBatch() empty = %v, %v", urls, err)
+	}
+
+	// 按内容 识别 png 文件名
+	if urls, err = UploadImgBatch(app, []io.Reader{strings.NewReader("\x89PNG\r\n\x1a\n")}, 1); err != nil || !strings.HasPrefix(urls[0], "http://mmbiz.qpic.cn/image.png/") {
+		t.Errorf("UploadImgBatch() png = %q, %v", urls, err)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"fmt"
+)
+
+const (
+	ButtonTypeClick              = "click"                // 点击推事件
+	ButtonTypeView               = "view"                 // 跳转URL
+	ButtonTypeMiniprogram        = "miniprogram"          // 跳转小程序
+	ButtonTypeScanCodePush       = "scancode_push"        // 扫码推事件
+	ButtonTypeScanCodeWaitMsg    = "scancode_waitmsg"     // 扫码推事件且弹出“消息接收中”提示框
+	ButtonTypePicSysPhoto        = "pic_sysphoto"         // 弹出系统拍照发图
+	ButtonTypePicPhotoOrAlbum    = "pic_photo_or_album"   // 弹出拍照或者相册发图
+	ButtonTypePicWeixin          = "pic_weixin"           // 弹出微信相册发图器
+	ButtonTypeLocationSelect     = "location_select"      // 弹出地理位置选择器
+	ButtonTypeMediaId            = "media_id"             // 下发消息（除文本消息）
+	ButtonTypeViewLimited        = "view_limited"         // 跳转图文消息URL
+	ButtonTypeArticleId          = "article_id"           // 下发发布后的图文消息
+	ButtonTypeArticleViewLimited = "article_view_limited" // 跳转发布后的图文消息URL
+)
+
+/*
+Menu 自定义菜单
+
+	payload, _ := json.Marshal(menu.Menu{Button: buttons})
+	resp, err := menu.Create(ctx, payload)
+*/
+type Menu struct {
+	Button []Button `json:"button"`
+}
+
+/*
+Button 菜单按钮
+
+请使用 NewXxxButton 系列方法构造，构造时会校验对应类型的必填字段
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Custom_Menus/Creating_Custom-Defined_Menu.html
+*/
+type Button struct {
+	Type      string   `json:"type,omitempty"`
+	Name      string   `json:"name"`
+	Key       string   `json:"key,omitempty"`
+	Url       string   `json:"url,omitempty"`
+	MediaId   string   `json:"media_id,omitempty"`
+	Appid     string   `json:"appid,omitempty"`
+	Pagepath  string   `json:"pagepath,omitempty"`
+	ArticleId string   `json:"article_id,omitempty"`
+	SubButton []Button `json:"sub_button,omitempty"`
+}
+
+// field 必填字段 名称 和 值
+type field struct {
+	name  string
+	value string
+}
+
+// requireField 按顺序 校验按钮必填字段，报告 第一个缺失的字段
+func requireField(button Button, fields ...field) (Button, error) {
+	if button.Name == "" {
+		return Button{}, fmt.Errorf("menu button type %s: name required", button.Type)
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			return Button{}, fmt.Errorf("menu button %q type %s: %s required", button.Name, button.Type, f.name)
+		}
+	}
+	return button, nil
+}
+
+// NewParentButton 包含二级菜单的一级菜单
+func NewParentButton(name string, subButtons ...Button) (Button, error) {
+	if len(subButtons) == 0 {
+		return Button{}, fmt.Errorf("menu button %q: sub_button required", name)
+	}
+	return requireField(Button{Name: name, SubButton: subButtons})
+}
+
+// NewClickButton 点击推事件 用户点击后 推送 CLICK 事件，携带 key
+func NewClickButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeClick, Name: name, Key: key}, field{"key", key})
+}
+
+// NewViewButton 跳转 URL
+func NewViewButton(name string, url string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeView, Name: name, Url: url}, field{"url", url})
+}
+
+// NewMiniprogramButton 跳转小程序 不支持小程序的老版本客户端将打开 url
+func NewMiniprogramButton(name string, url string, appid string, pagepath string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeMiniprogram, Name: name, Url: url, Appid: appid, Pagepath: pagepath},
+		field{"url", url}, field{"appid", appid}, field{"pagepath", pagepath})
+}
+
+// NewScanCodePushButton 扫码推事件
+func NewScanCodePushButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeScanCodePush, Name: name, Key: key}, field{"key", key})
+}
+
+// NewScanCodeWaitMsgButton 扫码推事件且弹出“消息接收中”提示框
+func NewScanCodeWaitMsgButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeScanCodeWaitMsg, Name: name, Key: key}, field{"key", key})
+}
+
+// NewPicSysPhotoButton 弹出系统拍照发图
+func NewPicSysPhotoButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypePicSysPhoto, Name: name, Key: key}, field{"key", key})
+}
+
+// NewPicPhotoOrAlbumButton 弹出拍照或者相册发图
+func NewPicPhotoOrAlbumButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypePicPhotoOrAlbum, Name: name, Key: key}, field{"key", key})
+}
+
+// NewPicWeixinButton 弹出微信相册发图器
+func NewPicWeixinButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypePicWeixin, Name: name, Key: key}, field{"key", key})
+}
+
+// NewLocationSelectButton 弹出地理位置选择器
+func NewLocationSelectButton(name string, key string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeLocationSelect, Name: name, Key: key}, field{"key", key})
+}
+
+// NewMediaIdButton 下发消息（除文本消息） media_id 必须是永久素材
+func NewMediaIdButton(name string, mediaId string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeMediaId, Name: name, MediaId: mediaId}, field{"media_id", mediaId})
+}
+
+// NewViewLimitedButton 跳转图文消息 URL media_id 必须是永久图文素材
+func NewViewLimitedButton(name string, mediaId string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeViewLimited, Name: name, MediaId: mediaId}, field{"media_id", mediaId})
+}
+
+// NewArticleIdButton 下发发布后的图文消息
+func NewArticleIdButton(name string, articleId string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeArticleId, Name: name, ArticleId: articleId}, field{"article_id", articleId})
+}
+
+// NewArticleViewLimitedButton 跳转发布后的图文消息 URL
+func NewArticleViewLimitedButton(name string, articleId string) (Button, error) {
+	return requireField(Button{Type: ButtonTypeArticleViewLimited, Name: name, ArticleId: articleId}, field{"article_id", articleId})
+}
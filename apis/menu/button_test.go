@@ -0,0 +1,116 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewButton(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() (Button, error)
+		wantJSON string
+		wantErr  bool
+	}{
+		{
+			name:     "click",
+			build:    func() (Button, error) { return NewClickButton("今日歌曲", "V1001_TODAY_MUSIC") },
+			wantJSON: `{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC"}`,
+		},
+		{
+			name:    "click without key",
+			build:   func() (Button, error) { return NewClickButton("今日歌曲", "") },
+			wantErr: true,
+		},
+		{
+			name:     "media_id",
+			build:    func() (Button, error) { return NewMediaIdButton("图片", "MEDIA_ID1") },
+			wantJSON: `{"type":"media_id","name":"图片","media_id":"MEDIA_ID1"}`,
+		},
+		{
+			name:    "media_id without media_id",
+			build:   func() (Button, error) { return NewMediaIdButton("图片", "") },
+			wantErr: true,
+		},
+		{
+			name:     "article_id",
+			build:    func() (Button, error) { return NewArticleIdButton("文章", "ARTICLE_ID1") },
+			wantJSON: `{"type":"article_id","name":"文章","article_id":"ARTICLE_ID1"}`,
+		},
+		{
+			name: "miniprogram",
+			build: func() (Button, error) {
+				return NewMiniprogramButton("wxa", "http://mp.weixin.qq.com", "wx286b93c14bbf93aa", "pages/lunar/index")
+			},
+			wantJSON: `{"type":"miniprogram","name":"wxa","url":"http://mp.weixin.qq.com","appid":"wx286b93c14bbf93aa","pagepath":"pages/lunar/index"}`,
+		},
+		{
+			name: "miniprogram without url",
+			build: func() (Button, error) {
+				return NewMiniprogramButton("wxa", "", "wx286b93c14bbf93aa", "pages/lunar/index")
+			},
+			wantErr: true,
+		},
+		{
+			name:    "without name",
+			build:   func() (Button, error) { return NewScanCodePushButton("", "rselfmenu_0_1") },
+			wantErr: true,
+		},
+		{
+			name: "parent",
+			build: func() (Button, error) {
+				sub, _ := NewPicWeixinButton("微信相册发图", "rselfmenu_1_2")
+				return NewParentButton("发图", sub)
+			},
+			wantJSON: `{"name":"发图","sub_button":[{"type":"pic_weixin","name":"微信相册发图","key":"rselfmenu_1_2"}]}`,
+		},
+		{
+			name:    "parent without sub_button",
+			build:   func() (Button, error) { return NewParentButton("发图") },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			button, err := tt.build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewButton() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			data, _ := json.Marshal(button)
+			if string(data) != tt.wantJSON {
+				t.Errorf("NewButton() got = %s, want %s", data, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestNewButton_MissingFieldsOrder(t *testing.T) {
+	// 多个必填字段缺失时 总是报告 第一个
+	for i := 0; i < 20; i++ {
+		_, err := NewMiniprogramButton("wxa", "", "", "")
+		if want := `menu button "wxa" type miniprogram: url required`; err == nil || err.Error() != want {
+			t.Fatalf("NewMiniprogramButton() error = %v, want %s", err, want)
+		}
+	}
+	if _, err := NewMiniprogramButton("wxa", "http://mp.weixin.qq.com", "", ""); err == nil || err.Error() != `menu button "wxa" type miniprogram: appid required` {
+		t.Errorf("NewMiniprogramButton() error = %v, want appid required", err)
+	}
+}
@@ -15,6 +15,7 @@
 package menu_test
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/fastwego/offiaccount"
@@ -80,3 +81,68 @@ func ExampleGet() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleNewClickButton() {
+	var ctx *offiaccount.OffiAccount
+
+	click, err := menu.NewClickButton("今日歌曲", "V1001_TODAY_MUSIC")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	view, err := menu.NewViewButton("搜索", "http://www.soso.com/")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	parent, err := menu.NewParentButton("菜单", view)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	payload, _ := json.Marshal(menu.Menu{Button: []menu.Button{click, parent}})
+	resp, err := menu.Create(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleParseSelfMenuInfo() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := menu.GetCurrentSelfmenuInfo(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	info, err := menu.ParseSelfMenuInfo(resp)
+
+	fmt.Println(info.MenuOpen(), info.Button, err)
+}
+
+func ExampleTryMatchParsed() {
+	var ctx *offiaccount.OffiAccount
+
+	config, err := menu.TryMatchParsed(ctx, "OPENID")
+
+	fmt.Println(config.Button, err)
+}
+
+func ExampleValidate() {
+	var ctx *offiaccount.OffiAccount
+
+	click, _ := menu.NewClickButton("今日歌曲", "V1001_TODAY_MUSIC")
+	m := menu.Menu{Button: []menu.Button{click}}
+
+	// 提交前 校验 按钮数量、层级 和 标题长度
+	if err := m.Validate(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	payload, _ := json.Marshal(m)
+	resp, err := menu.Create(ctx, payload)
+
+	fmt.Println(resp, err)
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"encoding/json"
+)
+
+// 公众平台官网 设置的菜单 按钮类型
+const (
+	SelfMenuTypeText  = "text"  // 文本消息 value 为文本内容
+	SelfMenuTypeImg   = "img"   // 图片消息 value 为 mediaID
+	SelfMenuTypeVoice = "voice" // 语音消息 value 为 mediaID
+	SelfMenuTypeVideo = "video" // 视频消息 value 为视频下载链接
+	SelfMenuTypeNews  = "news"  // 图文消息 value 为 mediaID，内容见 NewsInfo
+)
+
+// SelfMenuInfo 当前使用的自定义菜单配置
+type SelfMenuInfo struct {
+	IsMenuOpen int          `json:"is_menu_open"` // 菜单是否开启，0代表未开启，1代表开启
+	Button     []SelfButton `json:"-"`
+}
+
+// MenuOpen 菜单是否开启
+func (info SelfMenuInfo) MenuOpen() bool {
+	return info.IsMenuOpen == 1
+}
+
+// SelfButton 菜单按钮 API 设置的菜单使用 Key/Url 等字段，官网设置的菜单使用 Value/NewsInfo
+type SelfButton struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Key       string       `json:"key"`
+	Url       string       `json:"url"`
+	Appid     string       `json:"appid"`
+	Pagepath  string       `json:"pagepath"`
+	Value     string       `json:"value"`
+	NewsInfo  []SelfNews   `json:"-"`
+	SubButton []SelfButton `json:"-"`
+}
+
+// SelfNews 图文消息
+type SelfNews struct {
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Digest     string `json:"digest"`
+	ShowCover  int    `json:"show_cover"` // 是否显示封面，0为不显示，1为显示
+	CoverUrl   string `json:"cover_url"`
+	ContentUrl string `json:"content_url"`
+	SourceUrl  string `json:"source_url"` // 原文的URL，若置空则无查看原文入口
+}
+
+// UnmarshalJSON 展开 sub_button.list 和 news_info.list
+func (button *SelfButton) UnmarshalJSON(data []byte) error {
+	type selfButton SelfButton
+	raw := struct {
+		*selfButton
+		NewsInfo struct {
+			List []SelfNews `json:"list"`
+		} `json:"news_info"`
+		SubButton struct {
+			List []SelfButton `json:"list"`
+		} `json:"sub_button"`
+	}{selfButton: (*selfButton)(button)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	button.NewsInfo = raw.NewsInfo.List
+	button.SubButton = raw.SubButton.List
+	return nil
+}
+
+/*
+ParseSelfMenuInfo 解析 GetCurrentSelfmenuInfo 的响应
+
+与 Get 不同，sub_button 和 news_info 在响应中 包装在 list 字段内
+
+	{
+	  "is_menu_open": 1,
+	  "selfmenu_info": {
+	    "button": [
+	      {"type": "click", "name": "今日歌曲", "key": "V1001_TODAY_MUSIC"},
+	      {
+	        "name": "菜单",
+	        "sub_button": {
+	          "list": [
+	            {"type": "view", "name": "搜索", "url": "http://www.soso.com/"},
+	            {"type": "news", "name": "图文", "value": "KQb_w_Tiz-nSdVLoTV35Psmty8hGBulGhEdbb9SKs-o", "news_info": {"list": [...]}}
+	          ]
+	        }
+	      }
+	    ]
+	  }
+	}
+*/
+func ParseSelfMenuInfo(resp []byte) (info SelfMenuInfo, err error) {
+	result := struct {
+		IsMenuOpen   int `json:"is_menu_open"`
+		SelfmenuInfo struct {
+			Button []SelfButton `json:"button"`
+		} `json:"selfmenu_info"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	return SelfMenuInfo{IsMenuOpen: result.IsMenuOpen, Button: result.SelfmenuInfo.Button}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelfMenuInfo(t *testing.T) {
+	resp := []byte(`{
+  "is_menu_open": 1,
+  "selfmenu_info": {
+    "button": [
+      {"type": "click", "name": "今日歌曲", "key": "V1001_TODAY_MUSIC"},
+      {
+        "name": "菜单",
+        "sub_button": {
+          "list": [
+            {"type": "view", "name": "搜索", "url": "http://www.soso.com/"},
+            {
+              "type": "news",
+              "name": "图文",
+              "value": "KQb_w_Tiz-nSdVLoTV35Psmty8hGBulGhEdbb9SKs-o",
+              "news_info": {
+                "list": [
+                  {"title": "MULTI_NEWS", "author": "JIMZHENG", "digest": "text", "show_cover": 0, "cover_url": "COVER_URL", "content_url": "CONTENT_URL", "source_url": ""}
+                ]
+              }
+            },
+            {"type": "video", "name": "video", "value": "http://61.182.130.30/vweixinp.tc.qq.com/1007_114bcede9a2244eeb5ab7f76d951df5f.f10.mp4"}
+          ]
+        }
+      },
+      {"type": "text", "name": "文本", "value": "你好"}
+    ]
+  }
+}`)
+
+	got, err := ParseSelfMenuInfo(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SelfMenuInfo{
+		IsMenuOpen: 1,
+		Button: []SelfButton{
+			{Type: ButtonTypeClick, Name: "今日歌曲", Key: "V1001_TODAY_MUSIC"},
+			{Name: "菜单", SubButton: []SelfButton{
+				{Type: ButtonTypeView, Name: "搜索", Url: "http://www.soso.com/"},
+				{Type: SelfMenuTypeNews, Name: "图文", Value: "KQb_w_Tiz-nSdVLoTV35Psmty8hGBulGhEdbb9SKs-o", NewsInfo: []SelfNews{
+					{Title: "MULTI_NEWS", Author: "JIMZHENG", Digest: "text", CoverUrl: "COVER_URL", ContentUrl: "CONTENT_URL"},
+				}},
+				{Type: SelfMenuTypeVideo, Name: "video", Value: "http://61.182.130.30/vweixinp.tc.qq.com/1007_114bcede9a2244eeb5ab7f76d951df5f.f10.mp4"},
+			}},
+			{Type: SelfMenuTypeText, Name: "文本", Value: "你好"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSelfMenuInfo() = %+v, want %+v", got, want)
+	}
+	if !got.MenuOpen() {
+		t.Errorf("MenuOpen() = false, want true")
+	}
+
+	if _, err = ParseSelfMenuInfo([]byte("{")); err == nil {
+		t.Error("ParseSelfMenuInfo() want error for invalid json")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// MatchRule 个性化菜单 匹配规则
+type MatchRule struct {
+	TagId              string `json:"tag_id,omitempty"`
+	Sex                string `json:"sex,omitempty"`
+	Country            string `json:"country,omitempty"`
+	Province           string `json:"province,omitempty"`
+	City               string `json:"city,omitempty"`
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+	Language           string `json:"language,omitempty"`
+}
+
+// MenuConfig 菜单配置 个性化菜单 带有 Matchrule 和 Menuid
+type MenuConfig struct {
+	Button    []Button   `json:"button"`
+	Matchrule *MatchRule `json:"matchrule,omitempty"`
+	Menuid    int64      `json:"menuid,omitempty"`
+}
+
+/*
+TryMatchParsed 测试 指定用户 看到的菜单 并解析为 MenuConfig
+
+userId 可以是粉丝的 OpenID，也可以是粉丝的微信号
+
+没有匹配的个性化菜单时 微信返回默认菜单，结构相同；公众号未创建菜单时 返回 *offiaccount.WXError
+*/
+func TryMatchParsed(ctx *offiaccount.OffiAccount, userId string) (config MenuConfig, err error) {
+	if userId == "" {
+		err = errors.New("menu trymatch: user_id required")
+		return
+	}
+
+	payload, err := json.Marshal(map[string]string{"user_id": userId})
+	if err != nil {
+		return
+	}
+	resp, err := TryMatch(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &config)
+	return
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestTryMatchParsed(t *testing.T) {
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiTryMatch, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		payload, _ := ioutil.ReadAll(r.Body)
+		if string(payload) != `{"user_id":"weixin"}` {
+			t.Errorf("payload = %s", payload)
+		}
+		w.Write([]byte(`{"button":[{"type":"view","name":"tx","url":"http://www.qq.com/","sub_button":[]},{"name":"菜单","sub_button":[{"type":"click","name":"赞一下我们","key":"V1001_GOOD"}]}]}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestTryMatchParsed", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	got, err := TryMatchParsed(ctx, "weixin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MenuConfig{Button: []Button{
+		{Type: ButtonTypeView, Name: "tx", Url: "http://www.qq.com/", SubButton: []Button{}},
+		{Name: "菜单", SubButton: []Button{{Type: ButtonTypeClick, Name: "赞一下我们", Key: "V1001_GOOD"}}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TryMatchParsed() = %+v, want %+v", got, want)
+	}
+
+	if _, err = TryMatchParsed(ctx, ""); err == nil || calls != 1 {
+		t.Errorf("TryMatchParsed() want error without request for empty user_id, calls = %d", calls)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"fmt"
+)
+
+/*
+自定义菜单 限制
+
+标题长度 见 创建接口 name 参数说明：“菜单标题，不超过16个字节，子菜单不超过60个字节”
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Custom_Menus/Creating_Custom-Defined_Menu.html
+*/
+const (
+	MaxButtons            = 3  // 一级菜单 最多 3 个
+	MaxSubButtons         = 5  // 每个一级菜单 最多包含 5 个二级菜单
+	MaxButtonNameBytes    = 16 // 一级菜单 标题 不超过 16 个字节
+	MaxSubButtonNameBytes = 60 // 二级菜单 标题 不超过 60 个字节
+)
+
+// Validate 校验 自定义菜单 (见 Validate)
+func (m Menu) Validate() error {
+	return Validate(m.Button)
+}
+
+/*
+Validate 提交前 校验 菜单按钮 数量、层级 和 标题长度，错误信息 指明 出错的按钮
+
+- 一级菜单 1~3 个，每个一级菜单 最多 5 个二级菜单，二级菜单 不能再包含子菜单
+
+- 一级菜单 标题 不超过 16 个字节，二级菜单 不超过 60 个字节（一个汉字 3 个字节）
+
+- 包含二级菜单的 一级菜单 不能设置 type/key/url 等响应动作，其他菜单 必须设置 type
+*/
+func Validate(buttons []Button) error {
+	if len(buttons) == 0 || len(buttons) > MaxButtons {
+		return fmt.Errorf("menu: %d buttons, want 1-%d", len(buttons), MaxButtons)
+	}
+
+	for i, button := range buttons {
+		path := fmt.Sprintf("button[%d] %q", i, button.Name)
+		if err := validateButton(path, button, MaxButtonNameBytes); err != nil {
+			return err
+		}
+		if len(button.SubButton) == 0 {
+			continue
+		}
+
+		if len(button.SubButton) > MaxSubButtons {
+			return fmt.Errorf("menu %s: %d sub_button, want at most %d", path, len(button.SubButton), MaxSubButtons)
+		}
+		if hasAction(button) {
+			return fmt.Errorf("menu %s: button with sub_button must not set type/key/url/media_id/appid/pagepath/article_id", path)
+		}
+		for j, sub := range button.SubButton {
+			subPath := fmt.Sprintf("button[%d].sub_button[%d] %q", i, j, sub.Name)
+			if len(sub.SubButton) > 0 {
+				return fmt.Errorf("menu %s: sub_button can not be nested", subPath)
+			}
+			if err := validateButton(subPath, sub, MaxSubButtonNameBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateButton 校验 标题 以及 不含子菜单的按钮 必须设置 type
+func validateButton(path string, button Button, maxNameBytes int) error {
+	switch {
+	case button.Name == "":
+		return fmt.Errorf("menu %s: name required", path)
+	case len(button.Name) > maxNameBytes:
+		return fmt.Errorf("menu %s: name is %d bytes, want at most %d", path, len(button.Name), maxNameBytes)
+	case len(button.SubButton) == 0 && button.Type == "":
+		return fmt.Errorf("menu %s: type required", path)
+	}
+	return nil
+}
+
+// hasAction 按钮 是否设置了 响应动作
+func hasAction(button Button) bool {
+	return button.Type != "" || button.Key != "" || button.Url != "" || button.MediaId != "" ||
+		button.Appid != "" || button.Pagepath != "" || button.ArticleId != ""
+}
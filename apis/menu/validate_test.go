@@ -0,0 +1,93 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	click := Button{Type: ButtonTypeClick, Name: "今日歌曲", Key: "V1001_TODAY_MUSIC"}
+	view := Button{Type: ButtonTypeView, Name: "搜索", Url: "http://www.soso.com/"}
+	parent := Button{Name: "菜单", SubButton: []Button{view, click}}
+
+	tests := []struct {
+		name    string
+		buttons []Button
+		wantErr string
+	}{
+		{name: "ok", buttons: []Button{click, parent}},
+		{name: "empty", buttons: nil, wantErr: "0 buttons"},
+		{name: "too many buttons", buttons: []Button{click, click, click, click}, wantErr: "4 buttons"},
+		{
+			name:    "too many sub buttons",
+			buttons: []Button{{Name: "菜单", SubButton: []Button{view, view, view, view, view, view}}},
+			wantErr: `button[0] "菜单": 6 sub_button`,
+		},
+		{
+			name:    "top name too long",
+			buttons: []Button{click, {Type: ButtonTypeClick, Name: "一二三四五六", Key: "KEY"}},
+			wantErr: `button[1] "一二三四五六": name is 18 bytes`,
+		},
+		{
+			name:    "sub name within limit",
+			buttons: []Button{{Name: "菜单", SubButton: []Button{{Type: ButtonTypeClick, Name: strings.Repeat("长", 20), Key: "KEY"}}}},
+		},
+		{
+			name:    "sub name too long",
+			buttons: []Button{{Name: "菜单", SubButton: []Button{view, {Type: ButtonTypeClick, Name: strings.Repeat("长", 21), Key: "KEY"}}}},
+			wantErr: `button[0].sub_button[1]`,
+		},
+		{
+			name:    "parent with action",
+			buttons: []Button{{Type: ButtonTypeClick, Name: "菜单", Key: "KEY", SubButton: []Button{view}}},
+			wantErr: `button[0] "菜单": button with sub_button must not set`,
+		},
+		{
+			name:    "nested sub buttons",
+			buttons: []Button{{Name: "菜单", SubButton: []Button{parent}}},
+			wantErr: `button[0].sub_button[0] "菜单": sub_button can not be nested`,
+		},
+		{
+			name:    "missing type",
+			buttons: []Button{{Name: "菜单", SubButton: []Button{{Name: "搜索", Url: "http://www.soso.com/"}}}},
+			wantErr: `button[0].sub_button[0] "搜索": type required`,
+		},
+		{
+			name:    "missing name",
+			buttons: []Button{{Type: ButtonTypeClick, Key: "KEY"}},
+			wantErr: `button[0] "": name required`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.buttons)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (Menu{Button: []Button{click}}).Validate(); err != nil {
+		t.Errorf("Menu.Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	MatchModeContain = "contain" // 消息中含有该关键词即可
+	MatchModeEqual   = "equal"   // 消息内容必须和关键词严格相同
+
+	ReplyModeAll       = "reply_all"  // 全部回复
+	ReplyModeRandomOne = "random_one" // 随机回复其中一条
+)
+
+// AutoreplyKeyword 关键词
+type AutoreplyKeyword struct {
+	Type      string `json:"type"`
+	MatchMode string `json:"match_mode"`
+	Content   string `json:"content"`
+}
+
+// AutoreplyReply 回复内容 文本为 content，图片、语音、视频为 media_id，图文消息 见 news_info
+type AutoreplyReply struct {
+	Type     string          `json:"type"`
+	Content  string          `json:"content,omitempty"`
+	NewsInfo json.RawMessage `json:"news_info,omitempty"`
+}
+
+// KeywordRule 关键词自动回复规则
+type KeywordRule struct {
+	RuleName        string             `json:"rule_name"`
+	CreateTime      int64              `json:"create_time"`
+	ReplyMode       string             `json:"reply_mode"`
+	KeywordListInfo []AutoreplyKeyword `json:"keyword_list_info"`
+	ReplyListInfo   []AutoreplyReply   `json:"reply_list_info"`
+}
+
+// Match 文本 是否命中 任一关键词
+func (rule KeywordRule) Match(text string) bool {
+	for _, keyword := range rule.KeywordListInfo {
+		if keyword.Content == "" {
+			continue
+		}
+		switch keyword.MatchMode {
+		case MatchModeEqual:
+			if text == keyword.Content {
+				return true
+			}
+		case MatchModeContain:
+			if strings.Contains(text, keyword.Content) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AutoreplyInfo 公众号的自动回复规则
+type AutoreplyInfo struct {
+	IsAddFriendReplyOpen        int            `json:"is_add_friend_reply_open"`
+	IsAutoreplyOpen             int            `json:"is_autoreply_open"`
+	AddFriendAutoreplyInfo      AutoreplyReply `json:"add_friend_autoreply_info"`
+	MessageDefaultAutoreplyInfo AutoreplyReply `json:"message_default_autoreply_info"`
+	KeywordAutoreplyInfo        struct {
+		List []KeywordRule `json:"list"`
+	} `json:"keyword_autoreply_info"`
+}
+
+// ParseAutoreplyInfo 解析 GetCurrentAutoreplyInfo 响应
+func ParseAutoreplyInfo(resp []byte) (info AutoreplyInfo, err error) {
+	err = json.Unmarshal(resp, &info)
+	return
+}
+
+/*
+BuildAutoreplyMatcher 按 GetCurrentAutoreplyInfo 响应中的 关键词自动回复规则 构造匹配函数
+
+规则按 返回顺序 依次匹配，命中后 reply 为该规则 第一条 content 非空的回复（文本内容 或 media_id）
+
+未开启自动回复（is_autoreply_open 为 0）时 不会命中任何规则
+
+	resp, err := message.GetCurrentAutoreplyInfo(ctx)
+	match, err := message.BuildAutoreplyMatcher(resp)
+	if reply, ok := match(msg.Content); ok {
+		// ...
+	}
+*/
+func BuildAutoreplyMatcher(resp []byte) (match func(text string) (reply string, matched bool), err error) {
+	info, err := ParseAutoreplyInfo(resp)
+	if err != nil {
+		return
+	}
+
+	var rules []KeywordRule
+	if info.IsAutoreplyOpen == 1 {
+		rules = info.KeywordAutoreplyInfo.List
+	}
+
+	match = func(text string) (reply string, matched bool) {
+		for _, rule := range rules {
+			if !rule.Match(text) {
+				continue
+			}
+			for _, r := range rule.ReplyListInfo {
+				if r.Content != "" {
+					return r.Content, true
+				}
+			}
+			return "", true
+		}
+		return "", false
+	}
+	return
+}
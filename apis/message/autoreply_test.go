@@ -0,0 +1,105 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"testing"
+)
+
+const autoreplyInfo = `{
+  "is_add_friend_reply_open": 1,
+  "is_autoreply_open": 1,
+  "add_friend_autoreply_info": {"type": "text", "content": "Thanks for your attention!"},
+  "message_default_autoreply_info": {"type": "text", "content": "Hello, this is autoreply!"},
+  "keyword_autoreply_info": {
+    "list": [
+      {
+        "rule_name": "autoreply-news",
+        "create_time": 1423028166,
+        "reply_mode": "reply_all",
+        "keyword_list_info": [
+          {"type": "text", "match_mode": "contain", "content": "news测试"}
+        ],
+        "reply_list_info": [
+          {"type": "news", "news_info": {"list": [{"title": "it's news"}]}},
+          {"type": "text", "content": "news reply"}
+        ]
+      },
+      {
+        "rule_name": "autoreply-voice",
+        "create_time": 1423027971,
+        "reply_mode": "random_one",
+        "keyword_list_info": [
+          {"type": "text", "match_mode": "equal", "content": "voice"},
+          {"type": "text", "match_mode": "equal", "content": "语音"}
+        ],
+        "reply_list_info": [
+          {"type": "voice", "content": "NESsxgHEvAcg3egJTtYj4uG1PTL6iPhratdWKDLAXYErhN6oEEfMdVyblWtBY5vp"}
+        ]
+      }
+    ]
+  }
+}`
+
+func TestParseAutoreplyInfo(t *testing.T) {
+	info, err := ParseAutoreplyInfo([]byte(autoreplyInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := info.KeywordAutoreplyInfo.List
+	if info.IsAutoreplyOpen != 1 || info.MessageDefaultAutoreplyInfo.Content != "Hello, this is autoreply!" || len(rules) != 2 {
+		t.Fatalf("ParseAutoreplyInfo() = %+v", info)
+	}
+	if rules[1].ReplyMode != ReplyModeRandomOne || len(rules[1].KeywordListInfo) != 2 || len(rules[0].ReplyListInfo[0].NewsInfo) == 0 {
+		t.Errorf("ParseAutoreplyInfo() rules = %+v", rules)
+	}
+}
+
+func TestBuildAutoreplyMatcher(t *testing.T) {
+	match, err := BuildAutoreplyMatcher([]byte(autoreplyInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text        string
+		wantReply   string
+		wantMatched bool
+	}{
+		{text: "看看news测试", wantReply: "news reply", wantMatched: true},
+		{text: "语音", wantReply: "NESsxgHEvAcg3egJTtYj4uG1PTL6iPhratdWKDLAXYErhN6oEEfMdVyblWtBY5vp", wantMatched: true},
+		{text: "voice please", wantMatched: false},
+		{text: "hello", wantMatched: false},
+	}
+	for _, tt := range tests {
+		reply, matched := match(tt.text)
+		if reply != tt.wantReply || matched != tt.wantMatched {
+			t.Errorf("match(%q) = %q, %v, want %q, %v", tt.text, reply, matched, tt.wantReply, tt.wantMatched)
+		}
+	}
+
+	// 未开启自动回复
+	match, err = BuildAutoreplyMatcher([]byte(`{"is_autoreply_open":0,"keyword_autoreply_info":{"list":[{"keyword_list_info":[{"match_mode":"equal","content":"hi"}]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, matched := match("hi"); matched {
+		t.Error("match() matched when autoreply closed")
+	}
+
+	if _, err = BuildAutoreplyMatcher([]byte("not json")); err == nil {
+		t.Error("BuildAutoreplyMatcher() want error")
+	}
+}
@@ -28,3 +28,24 @@ func ExampleGetCurrentAutoreplyInfo() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleBuildAutoreplyMatcher() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := message.GetCurrentAutoreplyInfo(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	match, err := message.BuildAutoreplyMatcher(resp)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// 自定义消息分发中 复用 公众平台配置的 关键词规则
+	reply, matched := match("news测试")
+
+	fmt.Println(reply, matched)
+}
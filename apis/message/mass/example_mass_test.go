@@ -101,3 +101,71 @@ func ExampleSpeedSet() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleSendToTag() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := mass.SendToTag(ctx, 2, mass.MassMessage{
+		Mpnews:            &mass.MediaContent{MediaId: "MEDIA_ID"},
+		SendIgnoreReprint: 0,
+	})
+
+	fmt.Println(resp, err)
+}
+
+func ExampleSendToAll() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := mass.SendToAll(ctx, mass.MassMessage{Text: &mass.TextContent{Content: "CONTENT"}})
+
+	fmt.Println(resp, err)
+}
+
+func ExampleUploadNews() {
+	var ctx *offiaccount.OffiAccount
+
+	mediaId, err := mass.UploadNews(ctx, []mass.Article{{
+		ThumbMediaId: "THUMB_MEDIA_ID",
+		Title:        "TITLE",
+		Content:      "CONTENT",
+		ShowCoverPic: 1,
+	}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resp, err := mass.SendToAll(ctx, mass.MassMessage{Mpnews: &mass.MediaContent{MediaId: mediaId}})
+
+	fmt.Println(resp, err)
+}
+
+func ExampleParseSendResult() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := mass.SendToAll(ctx, mass.MassMessage{Text: &mass.TextContent{Content: "CONTENT"}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := mass.ParseSendResult(resp)
+
+	fmt.Println(result.MsgId, result.MsgDataId, err)
+}
+
+func ExampleSendIdempotent() {
+	var ctx *offiaccount.OffiAccount
+
+	payload := []byte("{}")
+	// 业务重试时 使用同一个 key，不会重复群发
+	resp, err := mass.SendIdempotent(ctx, "campaign:2020-08", payload, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := mass.ParseSendResult(resp)
+
+	fmt.Println(result.MsgId, err)
+}
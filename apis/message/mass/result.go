@@ -0,0 +1,51 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+/*
+SendResult 群发接口（SendAll / Send / SendToTag / SendToAll）的结果
+
+msg_id 可能超出 int32 以及 float64 可精确表示的范围，按 json.Number 解析后 以字符串保存，避免精度丢失
+*/
+type SendResult struct {
+	MsgId     string // 消息发送任务的ID
+	MsgDataId string // 消息的数据ID 仅在群发图文消息时返回，可用于获取图文数据
+}
+
+// MsgIdInt64 以 int64 返回 MsgId
+func (r SendResult) MsgIdInt64() (int64, error) {
+	return strconv.ParseInt(r.MsgId, 10, 64)
+}
+
+// ParseSendResult 解析 群发接口 响应
+func ParseSendResult(resp []byte) (result SendResult, err error) {
+	data := struct {
+		MsgId     json.Number `json:"msg_id"`
+		MsgDataId json.Number `json:"msg_data_id"`
+	}{}
+	if err = json.Unmarshal(resp, &data); err != nil {
+		return
+	}
+	if data.MsgId == "" {
+		return result, fmt.Errorf("mass send: msg_id not found in %s", resp)
+	}
+	return SendResult{MsgId: data.MsgId.String(), MsgDataId: data.MsgDataId.String()}, nil
+}
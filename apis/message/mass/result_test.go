@@ -0,0 +1,42 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"testing"
+)
+
+func TestParseSendResult(t *testing.T) {
+	// msg_id 超出 float64 可精确表示的范围
+	result, err := ParseSendResult([]byte(`{"errcode":0,"errmsg":"send job submission success","msg_id":9223372036854775001,"msg_data_id":2247483684}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MsgId != "9223372036854775001" || result.MsgDataId != "2247483684" {
+		t.Errorf("ParseSendResult() = %+v", result)
+	}
+	if msgId, err := result.MsgIdInt64(); err != nil || msgId != 9223372036854775001 {
+		t.Errorf("MsgIdInt64() = %d, %v", msgId, err)
+	}
+
+	// 兼容 字符串形式
+	if result, err = ParseSendResult([]byte(`{"msg_id":"34182"}`)); err != nil || result.MsgId != "34182" || result.MsgDataId != "" {
+		t.Errorf("ParseSendResult() = %+v, %v", result, err)
+	}
+
+	if _, err = ParseSendResult([]byte(`{"errcode":0}`)); err == nil {
+		t.Error("ParseSendResult() want error for missing msg_id")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const (
+	MsgTypeMpnews  = "mpnews"
+	MsgTypeText    = "text"
+	MsgTypeVoice   = "voice"
+	MsgTypeImage   = "image"
+	MsgTypeMpvideo = "mpvideo"
+	MsgTypeWxcard  = "wxcard"
+)
+
+// MediaContent 以 media_id 指定内容的消息 (mpnews/voice/mpvideo)
+type MediaContent struct {
+	MediaId string `json:"media_id"`
+}
+
+// TextContent 文本消息
+type TextContent struct {
+	Content string `json:"content"`
+}
+
+// ImageContent 图片消息 可包含多张图片
+type ImageContent struct {
+	MediaIds           []string `json:"media_ids"`
+	Recommend          string   `json:"recommend,omitempty"`
+	NeedOpenComment    int      `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int      `json:"only_fans_can_comment,omitempty"`
+}
+
+// WxcardContent 卡券消息
+type WxcardContent struct {
+	CardId string `json:"card_id"`
+}
+
+/*
+MassMessage 群发消息内容 必须且只能设置一种内容
+
+	msg := mass.MassMessage{Text: &mass.TextContent{Content: "CONTENT"}}
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Batch_Sends_and_Originality_Checks.html
+*/
+type MassMessage struct {
+	Mpnews  *MediaContent  `json:"mpnews,omitempty"`
+	Text    *TextContent   `json:"text,omitempty"`
+	Voice   *MediaContent  `json:"voice,omitempty"`
+	Images  *ImageContent  `json:"images,omitempty"`
+	Mpvideo *MediaContent  `json:"mpvideo,omitempty"`
+	Wxcard  *WxcardContent `json:"wxcard,omitempty"`
+
+	// SendIgnoreReprint 图文消息被判定为转载时 1 继续群发 0 停止群发
+	SendIgnoreReprint int `json:"send_ignore_reprint,omitempty"`
+	// ClientMsgId 群发消息 ID 用于避免重复群发
+	ClientMsgId string `json:"clientmsgid,omitempty"`
+}
+
+// MsgType 根据设置的内容 返回 msgtype，内容未设置 或 设置了多种 时返回错误
+func (m MassMessage) MsgType() (msgType string, err error) {
+	set := map[string]bool{
+		MsgTypeMpnews:  m.Mpnews != nil,
+		MsgTypeText:    m.Text != nil,
+		MsgTypeVoice:   m.Voice != nil,
+		MsgTypeImage:   m.Images != nil,
+		MsgTypeMpvideo: m.Mpvideo != nil,
+		MsgTypeWxcard:  m.Wxcard != nil,
+	}
+
+	var count int
+	for t, ok := range set {
+		if ok {
+			msgType = t
+			count++
+		}
+	}
+	if count != 1 {
+		return "", fmt.Errorf("mass message: exactly one content required, got %d", count)
+	}
+	return
+}
+
+// Filter 群发对象 IsToAll 为 true 时 发送给全部用户，否则 发送给 TagId 标签的用户
+type Filter struct {
+	IsToAll bool `json:"is_to_all"`
+	TagId   int  `json:"tag_id,omitempty"`
+}
+
+// sendAllPayload 构造 sendall 请求
+func sendAllPayload(filter Filter, msg MassMessage) (payload []byte, err error) {
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(struct {
+		Filter  Filter `json:"filter"`
+		MsgType string `json:"msgtype"`
+		MassMessage
+	}{Filter: filter, MsgType: msgType, MassMessage: msg})
+}
+
+// SendToTag 根据标签 群发消息 (见 SendAll)
+func SendToTag(ctx *offiaccount.OffiAccount, tagId int, msg MassMessage) (resp []byte, err error) {
+	if tagId <= 0 {
+		return nil, fmt.Errorf("mass message: invalid tag_id %d", tagId)
+	}
+	payload, err := sendAllPayload(Filter{TagId: tagId}, msg)
+	if err != nil {
+		return
+	}
+	return SendAll(ctx, payload)
+}
+
+// SendToAll 群发消息 给全部用户 (见 SendAll)
+func SendToAll(ctx *offiaccount.OffiAccount, msg MassMessage) (resp []byte, err error) {
+	payload, err := sendAllPayload(Filter{IsToAll: true}, msg)
+	if err != nil {
+		return
+	}
+	return SendAll(ctx, payload)
+}
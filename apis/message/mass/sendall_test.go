@@ -0,0 +1,70 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"testing"
+)
+
+func TestSendAllPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		msg     MassMessage
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "tag text",
+			filter: Filter{TagId: 2},
+			msg:    MassMessage{Text: &TextContent{Content: "CONTENT"}},
+			want:   `{"filter":{"is_to_all":false,"tag_id":2},"msgtype":"text","text":{"content":"CONTENT"}}`,
+		},
+		{
+			name:   "all mpnews",
+			filter: Filter{IsToAll: true},
+			msg:    MassMessage{Mpnews: &MediaContent{MediaId: "MEDIA_ID"}, SendIgnoreReprint: 1},
+			want:   `{"filter":{"is_to_all":true},"msgtype":"mpnews","mpnews":{"media_id":"MEDIA_ID"},"send_ignore_reprint":1}`,
+		},
+		{
+			name:   "images",
+			filter: Filter{IsToAll: true},
+			msg:    MassMessage{Images: &ImageContent{MediaIds: []string{"A", "B"}}},
+			want:   `{"filter":{"is_to_all":true},"msgtype":"image","images":{"media_ids":["A","B"]}}`,
+		},
+		{name: "empty", filter: Filter{IsToAll: true}, msg: MassMessage{}, wantErr: true},
+		{
+			name:    "multiple",
+			filter:  Filter{IsToAll: true},
+			msg:     MassMessage{Text: &TextContent{Content: "CONTENT"}, Voice: &MediaContent{MediaId: "MEDIA_ID"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sendAllPayload(tt.filter, tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendAllPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("sendAllPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SendToTag(nil, 0, MassMessage{Text: &TextContent{Content: "CONTENT"}}); err == nil {
+		t.Errorf("SendToTag() want error for tag_id 0")
+	}
+}
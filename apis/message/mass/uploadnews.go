@@ -0,0 +1,75 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// Article 群发图文消息 中的一篇图文
+type Article struct {
+	ThumbMediaId       string `json:"thumb_media_id"`
+	Author             string `json:"author,omitempty"`
+	Title              string `json:"title"`
+	ContentSourceUrl   string `json:"content_source_url,omitempty"`
+	Content            string `json:"content"`
+	Digest             string `json:"digest,omitempty"`
+	ShowCoverPic       int    `json:"show_cover_pic"`
+	NeedOpenComment    int    `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment,omitempty"`
+}
+
+/*
+UploadNews 上传群发图文消息素材 返回 media_id，用于 群发 mpnews (见 MediaUploadNews)
+
+	mediaId, err := mass.UploadNews(ctx, articles)
+	resp, err := mass.SendToAll(ctx, mass.MassMessage{Mpnews: &mass.MediaContent{MediaId: mediaId}})
+*/
+func UploadNews(ctx *offiaccount.OffiAccount, articles []Article) (mediaId string, err error) {
+	if len(articles) == 0 || len(articles) > 8 {
+		return "", fmt.Errorf("mass uploadnews: 1-8 articles required, got %d", len(articles))
+	}
+	for i, article := range articles {
+		if article.ThumbMediaId == "" || article.Title == "" || article.Content == "" {
+			return "", fmt.Errorf("mass uploadnews: article %d: thumb_media_id, title and content required", i)
+		}
+	}
+
+	payload, err := json.Marshal(struct {
+		Articles []Article `json:"articles"`
+	}{Articles: articles})
+	if err != nil {
+		return
+	}
+
+	resp, err := MediaUploadNews(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		MediaId string `json:"media_id"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	if result.MediaId == "" {
+		return "", fmt.Errorf("mass uploadnews: media_id not found in %s", resp)
+	}
+	return result.MediaId, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mass
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestUploadNews(t *testing.T) {
+	var got string
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiMediaUploadNews, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		got = string(payload)
+		w.Write([]byte(`{"type":"news","media_id":"MEDIA_ID","created_at":1391857799}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestUploadNews", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	mediaId, err := UploadNews(ctx, []Article{{ThumbMediaId: "THUMB", Title: "TITLE", Content: "CONTENT", ShowCoverPic: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaId != "MEDIA_ID" {
+		t.Errorf("UploadNews() = %s, want MEDIA_ID", mediaId)
+	}
+	if want := `{"articles":[{"thumb_media_id":"THUMB","title":"TITLE","content":"CONTENT","show_cover_pic":1}]}`; got != want {
+		t.Errorf("UploadNews() payload = %s, want %s", got, want)
+	}
+
+	if _, err = UploadNews(ctx, nil); err == nil {
+		t.Error("UploadNews() want error for empty articles")
+	}
+	if _, err = UploadNews(ctx, []Article{{Title: "TITLE", Content: "CONTENT"}}); err == nil {
+		t.Error("UploadNews() want error for missing thumb_media_id")
+	}
+}
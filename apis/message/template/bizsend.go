@@ -0,0 +1,36 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"bytes"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const apiBizSend = "/cgi-bin/message/subscribe/bizsend"
+
+/*
+发送订阅通知
+
+用户在 订阅通知 弹窗中 同意订阅后（推送 subscribe_msg_popup_event 事件，见 type_event.EventSubscribeMsgPopup），可向其发送 对应模板的 订阅通知
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Subscription_Messages/api.html
+
+POST https://api.weixin.qq.com/cgi-bin/message/subscribe/bizsend?access_token=ACCESS_TOKEN
+*/
+func BizSend(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiBizSend, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
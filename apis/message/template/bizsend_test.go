@@ -0,0 +1,43 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestBizSend(t *testing.T) {
+	mockResp := []byte("{\"errcode\":0,\"errmsg\":\"ok\"}")
+	var gotPayload string
+	test.MockSvrHandler.HandleFunc(apiBizSend, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotPayload = string(body)
+		w.Write(mockResp)
+	})
+
+	payload := []byte(`{"touser":"OPENID","template_id":"TEMPLATE_ID","data":{"thing1":{"value":"订单已发货"}}}`)
+	gotResp, err := BizSend(test.MockOffiAccount, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotResp, mockResp) || gotPayload != string(payload) {
+		t.Errorf("BizSend() gotResp = %s, payload = %s", gotResp, gotPayload)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+Bootstrap 初始化模板消息：设置所属行业 并 依次添加模板
+
+返回 模板库编号(template_id_short) => 模板ID(template_id)
+
+某个模板添加失败时 返回已添加的模板 和 错误；注意 行业每月可修改一次，重复设置可能返回错误
+
+	templateIds, err := template.Bootstrap(ctx, "1", "4", []string{"TM00015", "OPENTM207498902"})
+*/
+func Bootstrap(ctx *offiaccount.OffiAccount, industryId1 string, industryId2 string, shortIds []string) (templateIds map[string]string, err error) {
+	if industryId1 == "" || industryId2 == "" {
+		return nil, errors.New("template bootstrap: industry_id1 and industry_id2 required")
+	}
+
+	payload, err := json.Marshal(map[string]string{"industry_id1": industryId1, "industry_id2": industryId2})
+	if err != nil {
+		return
+	}
+	if _, err = SetIndustry(ctx, payload); err != nil {
+		return nil, fmt.Errorf("template bootstrap: set industry: %w", err)
+	}
+
+	templateIds = make(map[string]string, len(shortIds))
+	for _, shortId := range shortIds {
+		payload, err = json.Marshal(map[string]string{"template_id_short": shortId})
+		if err != nil {
+			return
+		}
+
+		var resp []byte
+		resp, err = AddTemplate(ctx, payload)
+		if err != nil {
+			return templateIds, fmt.Errorf("template bootstrap: add template %s: %w", shortId, err)
+		}
+
+		result := struct {
+			TemplateId string `json:"template_id"`
+		}{}
+		if err = json.Unmarshal(resp, &result); err != nil {
+			return
+		}
+		if result.TemplateId == "" {
+			return templateIds, fmt.Errorf("template bootstrap: add template %s: empty template_id", shortId)
+		}
+		templateIds[shortId] = result.TemplateId
+	}
+
+	return
+}
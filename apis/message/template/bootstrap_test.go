@@ -0,0 +1,78 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestBootstrap(t *testing.T) {
+	var industryPayload string
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiSetIndustry, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		industryPayload = string(payload)
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	handler.HandleFunc(apiAddTemplate, func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		switch string(payload) {
+		case `{"template_id_short":"TM00015"}`:
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok","template_id":"Doclyl5uP7Aciu-qZ7mJNPtWkbkYnWBWVja26EGbNyk"}`))
+		case `{"template_id_short":"TM00016"}`:
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok","template_id":"TEMPLATE_ID_2"}`))
+		default:
+			w.Write([]byte(`{"errcode":45026,"errmsg":"template num exceeds limit"}`))
+		}
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestBootstrap", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	got, err := Bootstrap(ctx, "1", "4", []string{"TM00015", "TM00016"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if industryPayload != `{"industry_id1":"1","industry_id2":"4"}` {
+		t.Errorf("set industry payload = %s", industryPayload)
+	}
+	want := map[string]string{"TM00015": "Doclyl5uP7Aciu-qZ7mJNPtWkbkYnWBWVja26EGbNyk", "TM00016": "TEMPLATE_ID_2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bootstrap() = %v, want %v", got, want)
+	}
+
+	// 部分失败 返回已添加的模板
+	got, err = Bootstrap(ctx, "1", "4", []string{"TM00015", "TM99999"})
+	var wxErr *offiaccount.WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 45026 {
+		t.Errorf("Bootstrap() error = %v, want 45026", err)
+	}
+	if len(got) != 1 || got["TM00015"] == "" {
+		t.Errorf("Bootstrap() = %v, want TM00015 only", got)
+	}
+
+	if _, err = Bootstrap(ctx, "", "4", nil); err == nil {
+		t.Errorf("Bootstrap() want error for empty industry")
+	}
+}
@@ -16,6 +16,7 @@ package template_test
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/offiaccount/apis/message/template"
@@ -81,3 +82,90 @@ func ExampleSubscribe() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleSendSubscribe() {
+	var ctx *offiaccount.OffiAccount
+
+	authorizeUrl, err := template.GetSubscribeAuthorizeUrl(ctx.Config.Appid, 1000, "TEMPLATE_ID", "https://example.com/subscribe", "STATE")
+	fmt.Println(authorizeUrl, err)
+
+	// 在 redirect_url 的处理函数中
+	var query url.Values
+	callback, err := template.ParseSubscribeCallback(query)
+	if err != nil || !callback.Confirmed() {
+		return
+	}
+
+	resp, err := template.SendSubscribe(ctx, template.SubscribeMessage{
+		Touser:     callback.Openid,
+		TemplateId: callback.TemplateId,
+		Scene:      callback.Scene,
+		Title:      "订阅提醒",
+		Content:    template.SubscribeData{Value: "您订阅的内容已更新"},
+	})
+
+	fmt.Println(resp, err)
+}
+
+func ExampleBootstrap() {
+	var ctx *offiaccount.OffiAccount
+
+	templateIds, err := template.Bootstrap(ctx, "1", "4", []string{"TM00015", "OPENTM207498902"})
+
+	fmt.Println(templateIds, err)
+}
+
+func ExampleListTemplates() {
+	var ctx *offiaccount.OffiAccount
+
+	templates, err := template.ListTemplates(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, t := range templates {
+		fmt.Println(t.Id, t.Title, t.Keywords)
+	}
+}
+
+func ExampleParseSendResult() {
+	var ctx *offiaccount.OffiAccount
+
+	payload := []byte(`{"touser":"OPENID","template_id":"TEMPLATE_ID","data":{}}`)
+	resp, err := template.Send(ctx, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := template.ParseSendResult(resp)
+
+	fmt.Println(result.MsgId, err)
+}
+
+func ExampleBizSend() {
+	var ctx *offiaccount.OffiAccount
+
+	// 在 subscribe_msg_popup_event 事件处理中 记录 event.Accepted() 返回的模板ID，之后发送订阅通知
+	payload := []byte(`{"touser":"OPENID","template_id":"TEMPLATE_ID","page":"https://example.com","data":{"thing1":{"value":"订单已发货"}}}`)
+	resp, err := template.BizSend(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleSendIdempotent() {
+	var ctx *offiaccount.OffiAccount
+
+	payload := []byte("{}")
+	// 业务重试时 使用同一个 key，不会重复发送
+	resp, err := template.SendIdempotent(ctx, "order:123:paid", payload, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := template.ParseSendResult(resp)
+
+	fmt.Println(result.MsgId, err)
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+SendIdempotent 带 客户端幂等键 发送模板消息
+
+ttl 内 使用过的 key 直接返回 缓存的响应（含 msgid），避免 业务重试时 重复通知用户；ttl <= 0 时 使用 offiaccount.DefaultIdempotencyTTL
+
+微信 不提供 原生幂等，幂等记录 保存在 ctx.AccessToken.Cache 中，见 offiaccount.Idempotent
+
+	resp, err := template.SendIdempotent(ctx, "order:123:paid", payload, 0)
+	result, err := template.ParseSendResult(resp)
+*/
+func SendIdempotent(ctx *offiaccount.OffiAccount, key string, payload []byte, ttl time.Duration) (resp []byte, err error) {
+	return ctx.Idempotent("template_send:"+key, ttl, func() ([]byte, error) {
+		return Send(ctx, payload)
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestSendIdempotent(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","msgid":2916434826120888321}`))
+	}))
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestSendIdempotent", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	for i := 0; i < 2; i++ {
+		resp, err := SendIdempotent(ctx, "key", []byte("{}"), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := ParseSendResult(resp)
+		if err != nil || result.MsgId != "2916434826120888321" {
+			t.Errorf("ParseSendResult() = %+v, %v", result, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// keywordPattern 模板内容中的 关键词占位符 如 {{keyword1.DATA}}
+var keywordPattern = regexp.MustCompile(`\{\{\s*(\w+)\.DATA\s*\}\}`)
+
+// Template 模板 Keywords 为 模板内容中 按出现顺序 去重后的 关键词（如 first、keyword1、remark）
+type Template struct {
+	Id              string   `json:"template_id"`
+	Title           string   `json:"title"`
+	PrimaryIndustry string   `json:"primary_industry"`
+	DeputyIndustry  string   `json:"deputy_industry"`
+	Content         string   `json:"content"`
+	Example         string   `json:"example"`
+	Keywords        []string `json:"keywords"`
+}
+
+// ParseKeywords 提取 模板内容中的 关键词
+func ParseKeywords(content string) (keywords []string) {
+	seen := map[string]bool{}
+	for _, match := range keywordPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			keywords = append(keywords, match[1])
+		}
+	}
+	return
+}
+
+// ParseTemplateList 解析 GetAllPrivateTemplate 响应 并 提取 各模板的关键词
+func ParseTemplateList(resp []byte) (templates []Template, err error) {
+	result := struct {
+		TemplateList []Template `json:"template_list"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+
+	templates = result.TemplateList
+	for i := range templates {
+		templates[i].Keywords = ParseKeywords(templates[i].Content)
+	}
+	return
+}
+
+/*
+ListTemplates 获取 已添加至帐号下的所有模板 并 解析关键词，便于 按模板 渲染表单
+
+	templates, err := template.ListTemplates(ctx)
+	for _, t := range templates {
+		fmt.Println(t.Id, t.Title, t.Keywords)
+	}
+*/
+func ListTemplates(ctx *offiaccount.OffiAccount) (templates []Template, err error) {
+	resp, err := GetAllPrivateTemplate(ctx)
+	if err != nil {
+		return
+	}
+	return ParseTemplateList(resp)
+}
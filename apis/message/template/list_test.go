@@ -0,0 +1,66 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseKeywords(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{content: "{{first.DATA}}\n\n商品名称：{{keyword1.DATA}}\n购买时间：{{ keyword2.DATA }}\n{{keyword1.DATA}}\n{{remark.DATA}}", want: []string{"first", "keyword1", "keyword2", "remark"}},
+		{content: "无占位符", want: nil},
+	}
+	for _, tt := range tests {
+		if got := ParseKeywords(tt.content); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKeywords(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiGetAllPrivateTemplate, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"template_list":[{"template_id":"iPk5sOIt5X_flOVKn5GrTFpncEYTojx6ddbt8WYoV5s","title":"领取奖金提醒","primary_industry":"IT科技","deputy_industry":"互联网|电子商务","content":"{ {result.DATA} }\n\n领奖金额:{{withdrawMoney.DATA}}\n领奖  时间:    {{withdrawTime.DATA}}\n{{remark.DATA}}","example":"您已提交领奖申请\n\n领奖金额：xxxx元"}]}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestListTemplates", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	got, err := ListTemplates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Id != "iPk5sOIt5X_flOVKn5GrTFpncEYTojx6ddbt8WYoV5s" || got[0].Title != "领取奖金提醒" {
+		t.Fatalf("ListTemplates() = %#v", got)
+	}
+	if want := []string{"withdrawMoney", "withdrawTime", "remark"}; !reflect.DeepEqual(got[0].Keywords, want) {
+		t.Errorf("Keywords = %v, want %v", got[0].Keywords, want)
+	}
+
+	if _, err = ParseTemplateList([]byte("{")); err == nil {
+		t.Errorf("ParseTemplateList() want error for invalid json")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// SendResult 发送模板消息（Send）的结果，msgid 可能超出 int32 以及 float64 可精确表示的范围，以字符串保存
+type SendResult struct {
+	MsgId string
+}
+
+// MsgIdInt64 以 int64 返回 MsgId
+func (r SendResult) MsgIdInt64() (int64, error) {
+	return strconv.ParseInt(r.MsgId, 10, 64)
+}
+
+// ParseSendResult 解析 Send 响应
+func ParseSendResult(resp []byte) (result SendResult, err error) {
+	data := struct {
+		MsgId json.Number `json:"msgid"`
+	}{}
+	if err = json.Unmarshal(resp, &data); err != nil {
+		return
+	}
+	if data.MsgId == "" {
+		return result, fmt.Errorf("template send: msgid not found in %s", resp)
+	}
+	return SendResult{MsgId: data.MsgId.String()}, nil
+}
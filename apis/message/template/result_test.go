@@ -0,0 +1,37 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"testing"
+)
+
+func TestParseSendResult(t *testing.T) {
+	// msgid 超出 float64 可精确表示的范围
+	result, err := ParseSendResult([]byte(`{"errcode":0,"errmsg":"ok","msgid":2916434826120888321}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MsgId != "2916434826120888321" {
+		t.Errorf("ParseSendResult() = %+v", result)
+	}
+	if msgId, err := result.MsgIdInt64(); err != nil || msgId != 2916434826120888321 {
+		t.Errorf("MsgIdInt64() = %d, %v", msgId, err)
+	}
+
+	if _, err = ParseSendResult([]byte(`{"errcode":0,"errmsg":"ok"}`)); err == nil {
+		t.Error("ParseSendResult() want error for missing msgid")
+	}
+}
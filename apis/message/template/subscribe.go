@@ -0,0 +1,179 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const apiSubscribeMsg = "/mp/subscribemsg"
+
+// SubscribeMsgServerUrl 一次性订阅消息 授权页面 域名
+var SubscribeMsgServerUrl = "https://mp.weixin.qq.com"
+
+const (
+	SubscribeActionConfirm = "confirm" // 用户点击确认
+	SubscribeActionCancel  = "cancel"  // 用户点击取消
+)
+
+const (
+	SubscribeSceneMax = 10000 // scene 取值范围 0-10000
+	SubscribeTitleMax = 15    // title 最多 15 个字符
+)
+
+/*
+GetSubscribeAuthorizeUrl 一次性订阅消息 授权页面链接
+
+scene 为订阅场景值 0-10000，reserved 用于保持请求和回调的状态 可使用 oauth.GenerateState 生成
+
+用户操作后 页面将跳转至 redirect_url?openid=OPENID&template_id=TEMPLATE_ID&action=ACTION&scene=SCENE&reserved=RESERVED
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/One-time_subscription_info.html
+
+GET https://mp.weixin.qq.com/mp/subscribemsg?action=get_confirm&appid=wxaba38c7f163da69b&scene=1000&template_id=1uDxHNXwYQfBmXOfPJcjAS3FynHArD8aWMEFNRGSbCc&redirect_url=http%3a%2f%2fsupport.qq.com&reserved=test#wechat_redirect
+*/
+func GetSubscribeAuthorizeUrl(appid string, scene int, templateId string, redirectUrl string, reserved string) (authorizeUrl string, err error) {
+	if scene < 0 || scene > SubscribeSceneMax {
+		return "", fmt.Errorf("subscribe scene %d out of range [0, %d]", scene, SubscribeSceneMax)
+	}
+	params := url.Values{}
+	params.Add("action", "get_confirm")
+	params.Add("appid", appid)
+	params.Add("scene", strconv.Itoa(scene))
+	params.Add("template_id", templateId)
+	params.Add("redirect_url", redirectUrl)
+	params.Add("reserved", reserved)
+	return offiaccount.AppendQuery(offiaccount.JoinUrl(SubscribeMsgServerUrl, apiSubscribeMsg), params) + "#wechat_redirect", nil
+}
+
+// SubscribeCallback 一次性订阅消息 授权回调参数
+type SubscribeCallback struct {
+	Openid     string
+	TemplateId string
+	Action     string // confirm 或 cancel
+	Scene      int
+	Reserved   string
+}
+
+// Confirmed 用户是否同意订阅
+func (c SubscribeCallback) Confirmed() bool {
+	return c.Action == SubscribeActionConfirm
+}
+
+// ParseSubscribeCallback 解析 授权回调 redirect_url 上的参数
+func ParseSubscribeCallback(query url.Values) (callback SubscribeCallback, err error) {
+	callback = SubscribeCallback{
+		Openid:     query.Get("openid"),
+		TemplateId: query.Get("template_id"),
+		Action:     query.Get("action"),
+		Reserved:   query.Get("reserved"),
+	}
+	if callback.Action != SubscribeActionConfirm && callback.Action != SubscribeActionCancel {
+		return SubscribeCallback{}, fmt.Errorf("subscribe callback: unknown action %q", callback.Action)
+	}
+	if callback.Scene, err = strconv.Atoi(query.Get("scene")); err != nil {
+		return SubscribeCallback{}, fmt.Errorf("subscribe callback: invalid scene %q", query.Get("scene"))
+	}
+	return
+}
+
+// SubscribeData 一次性订阅消息 内容
+type SubscribeData struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// SubscribeMiniProgram 跳转小程序
+type SubscribeMiniProgram struct {
+	Appid    string `json:"appid"`
+	Pagepath string `json:"pagepath,omitempty"`
+}
+
+/*
+SubscribeMessage 一次性订阅消息
+
+	msg := template.SubscribeMessage{
+		Touser:     callback.Openid,
+		TemplateId: callback.TemplateId,
+		Scene:      callback.Scene,
+		Title:      "订阅提醒",
+		Content:    template.SubscribeData{Value: "您订阅的内容已更新"},
+	}
+	resp, err := template.SendSubscribe(ctx, msg)
+*/
+type SubscribeMessage struct {
+	Touser      string                `json:"touser"`
+	TemplateId  string                `json:"template_id"`
+	Url         string                `json:"url,omitempty"`
+	MiniProgram *SubscribeMiniProgram `json:"miniprogram,omitempty"`
+	Scene       int                   `json:"-"`
+	Title       string                `json:"title"`
+	Content     SubscribeData         `json:"-"`
+}
+
+// MarshalJSON scene 以字符串形式提交，content 包装在 data 中
+func (m SubscribeMessage) MarshalJSON() ([]byte, error) {
+	type message SubscribeMessage
+	return json.Marshal(struct {
+		message
+		Scene string `json:"scene"`
+		Data  struct {
+			Content SubscribeData `json:"content"`
+		} `json:"data"`
+	}{
+		message: message(m),
+		Scene:   strconv.Itoa(m.Scene),
+		Data: struct {
+			Content SubscribeData `json:"content"`
+		}{Content: m.Content},
+	})
+}
+
+// Validate 校验必填字段及 scene/title 取值
+func (m SubscribeMessage) Validate() error {
+	switch {
+	case m.Touser == "":
+		return fmt.Errorf("subscribe message: touser required")
+	case m.TemplateId == "":
+		return fmt.Errorf("subscribe message: template_id required")
+	case m.Scene < 0 || m.Scene > SubscribeSceneMax:
+		return fmt.Errorf("subscribe message: scene %d out of range [0, %d]", m.Scene, SubscribeSceneMax)
+	case m.Title == "":
+		return fmt.Errorf("subscribe message: title required")
+	case utf8.RuneCountInString(m.Title) > SubscribeTitleMax:
+		return fmt.Errorf("subscribe message: title exceeds %d characters", SubscribeTitleMax)
+	case m.Content.Value == "":
+		return fmt.Errorf("subscribe message: content required")
+	}
+	return nil
+}
+
+// SendSubscribe 校验后 推送一次性订阅消息 (见 Subscribe)
+func SendSubscribe(ctx *offiaccount.OffiAccount, msg SubscribeMessage) (resp []byte, err error) {
+	if err = msg.Validate(); err != nil {
+		return
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	return Subscribe(ctx, payload)
+}
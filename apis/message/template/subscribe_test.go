@@ -0,0 +1,99 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetSubscribeAuthorizeUrl(t *testing.T) {
+	got, err := GetSubscribeAuthorizeUrl("APPID", 1000, "TEMPLATE_ID", "http://support.qq.com", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://mp.weixin.qq.com/mp/subscribemsg?action=get_confirm&appid=APPID&redirect_url=http%3A%2F%2Fsupport.qq.com&reserved=test&scene=1000&template_id=TEMPLATE_ID#wechat_redirect"
+	if got != want {
+		t.Errorf("GetSubscribeAuthorizeUrl() = %v, want %v", got, want)
+	}
+
+	if _, err = GetSubscribeAuthorizeUrl("APPID", 10001, "TEMPLATE_ID", "http://support.qq.com", "test"); err == nil {
+		t.Error("GetSubscribeAuthorizeUrl() want error for scene out of range")
+	}
+}
+
+func TestParseSubscribeCallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		wantConfirmed bool
+		wantScene     int
+		wantErr       bool
+	}{
+		{name: "confirm", query: "openid=OPENID&template_id=TEMPLATE_ID&action=confirm&scene=1000&reserved=test", wantConfirmed: true, wantScene: 1000},
+		{name: "cancel", query: "openid=OPENID&template_id=TEMPLATE_ID&action=cancel&scene=1000&reserved=test", wantConfirmed: false, wantScene: 1000},
+		{name: "unknown action", query: "openid=OPENID&action=other&scene=1000", wantErr: true},
+		{name: "invalid scene", query: "openid=OPENID&action=confirm&scene=abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, err := ParseSubscribeCallback(query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSubscribeCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Confirmed() != tt.wantConfirmed || got.Scene != tt.wantScene || got.Openid != "OPENID" {
+				t.Errorf("ParseSubscribeCallback() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSubscribeMessage(t *testing.T) {
+	msg := SubscribeMessage{
+		Touser:      "OPENID",
+		TemplateId:  "TEMPLATE_ID",
+		Url:         "URL",
+		MiniProgram: &SubscribeMiniProgram{Appid: "xiaochengxuappid12345", Pagepath: "index?foo=bar"},
+		Scene:       1000,
+		Title:       "TITLE",
+		Content:     SubscribeData{Value: "VALUE", Color: "COLOR"},
+	}
+	if err := msg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"touser":"OPENID","template_id":"TEMPLATE_ID","url":"URL","miniprogram":{"appid":"xiaochengxuappid12345","pagepath":"index?foo=bar"},"title":"TITLE","scene":"1000","data":{"content":{"value":"VALUE","color":"COLOR"}}}`
+	if string(payload) != want {
+		t.Errorf("json.Marshal(SubscribeMessage) = %s, want %s", payload, want)
+	}
+
+	msg.Title = strings.Repeat("标", SubscribeTitleMax+1)
+	if err = msg.Validate(); err == nil {
+		t.Error("Validate() want error for long title")
+	}
+	msg.Title, msg.Scene = "TITLE", -1
+	if err = msg.Validate(); err == nil {
+		t.Error("Validate() want error for scene out of range")
+	}
+}
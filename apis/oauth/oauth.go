@@ -29,6 +29,7 @@ package oauth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -48,8 +49,8 @@ const (
 )
 
 const (
-	ScopeSnsapiBase     = "snsapi_base"
-	ScopeSnsapiUserinfo = "snsapi_userinfo"
+	ScopeSnsapiBase     = "snsapi_base"     // 静默授权 只能获取 openid 无需再调用 GetUserInfo 可直接使用 Login
+	ScopeSnsapiUserinfo = "snsapi_userinfo" // 需要用户手动同意 可通过 GetUserInfo 获取用户基本信息
 )
 
 /*
@@ -61,6 +62,8 @@ const (
 
 如果用户同意授权，页面将跳转至 redirect_uri/?code=CODE&state=STATE
 
+state 建议使用 GenerateState 生成并绑定到用户 session，回调时使用 VerifyState 校验，防止 CSRF 攻击
+
 See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage_authorization.html
 
 GET https://open.weixin.qq.com/connect/oauth2/authorize?appid=wxf0e81c3bee622d60&redirect_uri=http%3A%2F%2Fnba.bluewebgame.com%2Foauth_response.php&response_type=code&scope=snsapi_userinfo&state=STATE#wechat_redirect
@@ -72,7 +75,7 @@ func GetAuthorizeUrl(appid string, redirectUri string, scope string, state strin
 	params.Add("response_type", "code")
 	params.Add("scope", scope)
 	params.Add("state", state)
-	return OauthAuthorizeServerUrl + apiAuthorize + "?" + params.Encode()
+	return offiaccount.AppendQuery(offiaccount.JoinUrl(OauthAuthorizeServerUrl, apiAuthorize), params)
 }
 
 type OauthAccessToken struct {
@@ -81,6 +84,7 @@ type OauthAccessToken struct {
 	RefreshToken string `json:"refresh_token"`
 	Openid       string `json:"openid"`
 	Scope        string `json:"scope"`
+	Unionid      string `json:"unionid"` // 用户授权 snsapi_userinfo 且公众号已绑定到开放平台时 返回
 }
 
 /*
@@ -93,31 +97,58 @@ See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage
 GET https://api.weixin.qq.com/sns/oauth2/access_token?appid=APPID&secret=SECRET&code=CODE&grant_type=authorization_code
 */
 func GetAccessToken(appid string, secret string, code string) (oauthAccessToken OauthAccessToken, err error) {
+	return getAccessToken(http.DefaultClient, offiaccount.WXServerUrl, appid, secret, code)
+}
+
+/*
+GetAccessTokenFor 使用 公众号实例 的 Appid、AppSecret 通过code换取网页授权access_token (见 GetAccessToken)
+
+请求 经过 Config.HTTPClient，服务器地址 遵循 Config.BaseURL 和 Config.HostOverrides
+*/
+func GetAccessTokenFor(ctx *offiaccount.OffiAccount, code string) (oauthAccessToken OauthAccessToken, err error) {
+	return getAccessToken(ctx.Client.HTTPClient(), ctx.ServerUrlFor(apiAccessToken), ctx.Config.Appid, ctx.Secret(), code)
+}
+
+func getAccessToken(httpClient *http.Client, serverUrl string, appid string, secret string, code string) (oauthAccessToken OauthAccessToken, err error) {
 	params := url.Values{}
 	params.Add("appid", appid)
 	params.Add("secret", secret)
 	params.Add("code", code)
 	params.Add("grant_type", "authorization_code")
 
-	uri := offiaccount.WXServerUrl + apiAccessToken + "?" + params.Encode()
-	response, err := http.Get(uri)
+	err = getJSON(httpClient, offiaccount.AppendQuery(offiaccount.JoinUrl(serverUrl, apiAccessToken), params), &oauthAccessToken)
+	return
+}
+
+/*
+Login 静默授权登录 (snsapi_base)
+
+以 snsapi_base 发起的网页授权 只能获取用户 openid，通过 code 换取 openid 即完成登录，无需拉取用户信息
+*/
+func Login(appid string, secret string, code string) (openid string, err error) {
+	oauthAccessToken, err := GetAccessToken(appid, secret, code)
 	if err != nil {
 		return
 	}
+	return loginOpenid(oauthAccessToken)
+}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
+// LoginFor 使用 公众号实例 静默授权登录 (见 Login、GetAccessTokenFor)
+func LoginFor(ctx *offiaccount.OffiAccount, code string) (openid string, err error) {
+	oauthAccessToken, err := GetAccessTokenFor(ctx, code)
 	if err != nil {
 		return
 	}
+	return loginOpenid(oauthAccessToken)
+}
 
-	err = json.Unmarshal(body, &oauthAccessToken)
-	if err != nil {
-		err = fmt.Errorf("%s", string(body))
+func loginOpenid(oauthAccessToken OauthAccessToken) (openid string, err error) {
+	if oauthAccessToken.Openid == "" {
+		err = errors.New("empty openid, code may be invalid or used")
 		return
 	}
 
-	return
+	return oauthAccessToken.Openid, nil
 }
 
 /*
@@ -130,29 +161,21 @@ See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage
 POST https://api.weixin.qq.com/sns/oauth2/refresh_token?appid=APPID&grant_type=refresh_token&refresh_token=REFRESH_TOKEN
 */
 func RefreshToken(appid string, refresh_token string) (oauthAccessToken OauthAccessToken, err error) {
+	return refreshToken(http.DefaultClient, offiaccount.WXServerUrl, appid, refresh_token)
+}
+
+// RefreshTokenFor 使用 公众号实例 刷新access_token (见 RefreshToken、GetAccessTokenFor)
+func RefreshTokenFor(ctx *offiaccount.OffiAccount, refresh_token string) (oauthAccessToken OauthAccessToken, err error) {
+	return refreshToken(ctx.Client.HTTPClient(), ctx.ServerUrlFor(apiRefreshToken), ctx.Config.Appid, refresh_token)
+}
+
+func refreshToken(httpClient *http.Client, serverUrl string, appid string, refresh_token string) (oauthAccessToken OauthAccessToken, err error) {
 	params := url.Values{}
 	params.Add("appid", appid)
 	params.Add("refresh_token", refresh_token)
 	params.Add("grant_type", "refresh_token")
 
-	uri := offiaccount.WXServerUrl + apiRefreshToken + "?" + params.Encode()
-	response, err := http.Get(uri)
-	if err != nil {
-		return
-	}
-
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return
-	}
-
-	err = json.Unmarshal(body, &oauthAccessToken)
-	if err != nil {
-		err = fmt.Errorf("%s", string(body))
-		return
-	}
-
+	err = getJSON(httpClient, offiaccount.AppendQuery(offiaccount.JoinUrl(serverUrl, apiRefreshToken), params), &oauthAccessToken)
 	return
 }
 
@@ -184,29 +207,21 @@ See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage
 POST https://api.weixin.qq.com/sns/userinfo?access_token=ACCESS_TOKEN&openid=OPENID&lang=zh_CN
 */
 func GetUserInfo(access_token string, openid string, lang string) (oauthUserInfo OauthUserInfo, err error) {
+	return getUserInfo(http.DefaultClient, offiaccount.WXServerUrl, access_token, openid, lang)
+}
+
+// GetUserInfoFor 经过 公众号实例 的 http.Client 和 服务器地址 拉取用户信息 (见 GetUserInfo、GetAccessTokenFor)
+func GetUserInfoFor(ctx *offiaccount.OffiAccount, access_token string, openid string, lang string) (oauthUserInfo OauthUserInfo, err error) {
+	return getUserInfo(ctx.Client.HTTPClient(), ctx.ServerUrlFor(apiUserInfo), access_token, openid, lang)
+}
+
+func getUserInfo(httpClient *http.Client, serverUrl string, access_token string, openid string, lang string) (oauthUserInfo OauthUserInfo, err error) {
 	params := url.Values{}
 	params.Add("access_token", access_token)
 	params.Add("openid", openid)
 	params.Add("lang", lang)
 
-	uri := offiaccount.WXServerUrl + apiUserInfo + "?" + params.Encode()
-	response, err := http.Get(uri)
-	if err != nil {
-		return
-	}
-
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return
-	}
-
-	err = json.Unmarshal(body, &oauthUserInfo)
-	if err != nil {
-		err = fmt.Errorf("%s", string(body))
-		return
-	}
-
+	err = getJSON(httpClient, offiaccount.AppendQuery(offiaccount.JoinUrl(serverUrl, apiUserInfo), params), &oauthUserInfo)
 	return
 }
 
@@ -220,30 +235,26 @@ See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage
 GET https://api.weixin.qq.com/sns/auth?access_token=ACCESS_TOKEN&openid=OPENID
 */
 func Auth(access_token string, openid string) (isValid bool, err error) {
+	return auth(http.DefaultClient, offiaccount.WXServerUrl, access_token, openid)
+}
+
+// AuthFor 经过 公众号实例 的 http.Client 和 服务器地址 检验授权凭证 (见 Auth、GetAccessTokenFor)
+func AuthFor(ctx *offiaccount.OffiAccount, access_token string, openid string) (isValid bool, err error) {
+	return auth(ctx.Client.HTTPClient(), ctx.ServerUrlFor(apiAuth), access_token, openid)
+}
+
+func auth(httpClient *http.Client, serverUrl string, access_token string, openid string) (isValid bool, err error) {
 	params := url.Values{}
 	params.Add("access_token", access_token)
 	params.Add("openid", openid)
 
-	uri := offiaccount.WXServerUrl + apiAuth + "?" + params.Encode()
-	response, err := http.Get(uri)
-	if err != nil {
-		return
-	}
-
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return
-	}
-
 	s := struct {
 		Errcode int    `json:"errcode"`
 		Errmsg  string `json:"errmsg"`
 	}{}
 
-	err = json.Unmarshal(body, &s)
+	err = getJSON(httpClient, offiaccount.AppendQuery(offiaccount.JoinUrl(serverUrl, apiAuth), params), &s)
 	if err != nil {
-		err = fmt.Errorf("%s", string(body))
 		return
 	}
 
@@ -253,3 +264,23 @@ func Auth(access_token string, openid string) (isValid bool, err error) {
 
 	return
 }
+
+// getJSON 发送 GET 请求 并将响应 解析到 v，解析失败时 返回 响应内容
+func getJSON(httpClient *http.Client, uri string, v interface{}) (err error) {
+	response, err := httpClient.Get(uri)
+	if err != nil {
+		return
+	}
+
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		err = fmt.Errorf("%s", string(body))
+	}
+	return
+}
@@ -16,10 +16,12 @@ package oauth
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fastwego/offiaccount"
@@ -114,6 +116,46 @@ func TestGetAccessToken(t *testing.T) {
 	}
 }
 
+func TestLogin(t *testing.T) {
+	// 独立的 Mock Server 根据 code 返回不同结果
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code") == "CODE" {
+			_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_base"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	originUrl := offiaccount.WXServerUrl
+	offiaccount.WXServerUrl = svr.URL
+	defer func() { offiaccount.WXServerUrl = originUrl }()
+
+	tests := []struct {
+		name       string
+		code       string
+		wantOpenid string
+		wantErr    bool
+	}{
+		{name: "case1", code: "CODE", wantOpenid: "OPENID", wantErr: false},
+		{name: "case2", code: "INVALID", wantOpenid: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOpenid, err := Login("APPID", "SECRET", tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotOpenid != tt.wantOpenid {
+				t.Errorf("Login() gotOpenid = %v, want %v", gotOpenid, tt.wantOpenid)
+			}
+		})
+	}
+}
+
 func TestGetAuthorizeUrl(t *testing.T) {
 	type args struct {
 		appid       string
@@ -235,3 +277,66 @@ func TestRefreshToken(t *testing.T) {
 		})
 	}
 }
+
+// stubTransport 不发送请求 直接返回 body
+type stubTransport struct {
+	calls int
+	body  string
+}
+
+func (transport *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(transport.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestFor(t *testing.T) {
+	transport := &stubTransport{body: `{"access_token":"ACCESS_TOKEN","openid":"OPENID","errcode":0}`}
+	var hosts []string
+	app := offiaccount.New(offiaccount.Config{
+		Appid:         "APPID",
+		Secret:        "SECRET",
+		BaseURL:       "https://proxy.example.com",
+		HostOverrides: map[string]string{"/sns/userinfo": "https://userinfo.example.com"},
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			hosts = append(hosts, req.URL.Host+req.URL.Path)
+			return transport.RoundTrip(req)
+		})},
+	})
+
+	if token, err := GetAccessTokenFor(app, "CODE"); err != nil || token.Openid != "OPENID" {
+		t.Errorf("GetAccessTokenFor() = %v, %v", token, err)
+	}
+	if openid, err := LoginFor(app, "CODE"); err != nil || openid != "OPENID" {
+		t.Errorf("LoginFor() = %v, %v", openid, err)
+	}
+	if token, err := RefreshTokenFor(app, "REFRESH_TOKEN"); err != nil || token.Openid != "OPENID" {
+		t.Errorf("RefreshTokenFor() = %v, %v", token, err)
+	}
+	if info, err := GetUserInfoFor(app, "ACCESS_TOKEN", "OPENID", LANG_zh_CN); err != nil || info.Openid != "OPENID" {
+		t.Errorf("GetUserInfoFor() = %v, %v", info, err)
+	}
+	if valid, err := AuthFor(app, "ACCESS_TOKEN", "OPENID"); err != nil || !valid {
+		t.Errorf("AuthFor() = %v, %v", valid, err)
+	}
+
+	want := []string{
+		"proxy.example.com" + apiAccessToken,
+		"proxy.example.com" + apiAccessToken,
+		"proxy.example.com" + apiRefreshToken,
+		"userinfo.example.com" + apiUserInfo,
+		"proxy.example.com" + apiAuth,
+	}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("requests = %v, want %v", hosts, want)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
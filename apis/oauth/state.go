@@ -0,0 +1,44 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+/*
+GenerateState 生成 授权跳转链接 的 state 参数 (32 位 hex 随机串)
+
+state 用于防止 CSRF 攻击：生成后 绑定到用户的 cookie 或 session 中，再传给 GetAuthorizeUrl
+
+用户授权后 微信跳转到 redirect_uri/?code=CODE&state=STATE，此时使用 VerifyState 比对 session 中保存的值 与 回调中的 state，不一致则拒绝处理
+*/
+func GenerateState() (state string) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// VerifyState 校验回调的 state 是否与 session 中保存的一致 (常量时间比较)
+func VerifyState(expected string, got string) bool {
+	if expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(got)) == 1
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"testing"
+)
+
+func TestGenerateState(t *testing.T) {
+	state := GenerateState()
+	if len(state) != 32 {
+		t.Errorf("GenerateState() len = %d, want 32", len(state))
+	}
+	if state == GenerateState() {
+		t.Errorf("GenerateState() should be random")
+	}
+}
+
+func TestVerifyState(t *testing.T) {
+	state := GenerateState()
+	tests := []struct {
+		name     string
+		expected string
+		got      string
+		want     bool
+	}{
+		{name: "match", expected: state, got: state, want: true},
+		{name: "mismatch", expected: state, got: GenerateState(), want: false},
+		{name: "empty expected", expected: "", got: "", want: false},
+		{name: "empty got", expected: state, got: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyState(tt.expected, tt.got); got != tt.want {
+				t.Errorf("VerifyState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+SNSAccessToken 网页授权 access_token 及其获取时间
+
+网页授权 access_token 有效期较短（expires_in 一般为 7200 秒），过期后 需要使用 RefreshToken 刷新；记录获取时间 以便判断是否需要刷新
+
+	token := oauth.NewSNSAccessToken(oauthAccessToken)
+	// ... 保存到 session
+	if token.IsExpired() {
+		oauthAccessToken, err = oauth.RefreshToken(appid, token.RefreshToken)
+	}
+*/
+type SNSAccessToken struct {
+	OauthAccessToken
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// NewSNSAccessToken 记录 当前时间 为 access_token 的获取时间
+func NewSNSAccessToken(oauthAccessToken OauthAccessToken) SNSAccessToken {
+	return SNSAccessToken{OauthAccessToken: oauthAccessToken, FetchedAt: time.Now()}
+}
+
+/*
+ParseSNSAccessToken 解析 通过 code 换取 或 刷新 网页授权 access_token 的响应
+
+	{
+	  "access_token":"ACCESS_TOKEN",
+	  "expires_in":7200,
+	  "refresh_token":"REFRESH_TOKEN",
+	  "openid":"OPENID",
+	  "scope":"SCOPE",
+	  "unionid": "o6_bmasdasdsad6_2sgVt7hMZOPfL"
+	}
+*/
+func ParseSNSAccessToken(resp []byte) (token SNSAccessToken, err error) {
+	result := struct {
+		OauthAccessToken
+		offiaccount.WXError
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	if result.Errcode != 0 {
+		return token, &result.WXError
+	}
+	if result.AccessToken == "" {
+		return token, errors.New("empty access_token in response " + string(resp))
+	}
+	return NewSNSAccessToken(result.OauthAccessToken), nil
+}
+
+// ExpiresAt access_token 过期时间
+func (token SNSAccessToken) ExpiresAt() time.Time {
+	return token.FetchedAt.Add(time.Duration(token.ExpiresIn) * time.Second)
+}
+
+// IsExpired access_token 是否已过期
+func (token SNSAccessToken) IsExpired() bool {
+	return !time.Now().Before(token.ExpiresAt())
+}
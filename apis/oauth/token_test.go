@@ -0,0 +1,51 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseSNSAccessToken(t *testing.T) {
+	token, err := ParseSNSAccessToken([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_userinfo","unionid":"UNIONID"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "ACCESS_TOKEN" || token.Unionid != "UNIONID" || token.ExpiresIn != 7200 {
+		t.Errorf("ParseSNSAccessToken() = %+v", token)
+	}
+	if token.IsExpired() || time.Since(token.FetchedAt) > time.Second {
+		t.Errorf("IsExpired() = %v FetchedAt = %s, want fresh token", token.IsExpired(), token.FetchedAt)
+	}
+
+	token.FetchedAt = time.Now().Add(-7201 * time.Second)
+	if !token.IsExpired() {
+		t.Errorf("IsExpired() = false, want true after expires_in")
+	}
+
+	_, err = ParseSNSAccessToken([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+	var wxErr *offiaccount.WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 40029 {
+		t.Errorf("ParseSNSAccessToken() error = %v, want *WXError 40029", err)
+	}
+
+	if _, err = ParseSNSAccessToken([]byte(`{}`)); err == nil {
+		t.Errorf("ParseSNSAccessToken() want error for empty access_token")
+	}
+}
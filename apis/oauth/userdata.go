@@ -0,0 +1,79 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"crypto/aes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/offiaccount/util"
+)
+
+// ErrorWatermarkMismatch 解密后 watermark.appid 与当前 appid 不一致
+var ErrorWatermarkMismatch = errors.New("userdata: watermark appid mismatch")
+
+/*
+DecryptUserData 解密 小程序 encryptedData（AES-128-CBC PKCS#7 补位），并校验 watermark.appid
+
+公众号关联小程序 获取用户信息/手机号 等加密数据时使用，sessionKey、encryptedData、iv 均为 base64 编码
+
+See: https://developers.weixin.qq.com/miniprogram/dev/framework/open-ability/signature.html
+*/
+func DecryptUserData(appid string, sessionKey string, encryptedData string, iv string) (data []byte, err error) {
+	aesKey, err := base64.StdEncoding.DecodeString(sessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("userdata: invalid session_key: %w", err)
+	}
+	aesIv, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return nil, fmt.Errorf("userdata: invalid iv: %w", err)
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil {
+		return nil, fmt.Errorf("userdata: invalid encryptedData: %w", err)
+	}
+
+	if len(aesKey) != 16 {
+		return nil, fmt.Errorf("userdata: session_key length %d, want 16", len(aesKey))
+	}
+	if len(aesIv) != aes.BlockSize {
+		return nil, fmt.Errorf("userdata: iv length %d, want %d", len(aesIv), aes.BlockSize)
+	}
+	if len(cipherText) == 0 || len(cipherText)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("userdata: encryptedData length %d is not a multiple of %d", len(cipherText), aes.BlockSize)
+	}
+
+	data, err = util.AESDecryptData(cipherText, aesKey, aesIv)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Watermark struct {
+			Appid     string `json:"appid"`
+			Timestamp int64  `json:"timestamp"`
+		} `json:"watermark"`
+	}{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("userdata: %w", err)
+	}
+	if result.Watermark.Appid != appid {
+		return nil, ErrorWatermarkMismatch
+	}
+	return data, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecryptUserData(t *testing.T) {
+	// 小程序 官方示例数据
+	appid := "wx4f4bc4dec97d474b"
+	sessionKey := "tiihtNczf5v6AKRyjwEUhQ=="
+	encryptedData := "CiyLU1Aw2KjvrjMdj8YKliAjtP4gsMZMQmRzooG2xrDcvSnxIMXFufNstNGTyaGS9uT5geRa0W4oTOb1WT7fJlAC+oNPdbB+3hVbJSRgv+4lGOETKUQz6OYStslQ142dNCuabNPGBzlooOmB231qMM85d2/fV6ChevvXvQP8Hkue1poOFtnEtpyxVLW1zAo6/1Xx1COxFvrc2d7UL/lmHInNlxuacJXwu0fjpXfz/YqYzBIBzD6WUfTIF9GRHpOn/Hz7saL8xz+W//FRAUid1OksQaQx4CMs8LOddcQhULW4ucetDf96JcR3g0gfRK4PC7E/r7Z6xNrXd2UIeorGj5Ef7b1pJAYB6Y5anaHqZ9J6nKEBvB4DnNLIVWSgARns/8wR2SiRS7MNACwTyrGvt9ts8p12PKFdlqYTopNHR1Vf7XjfhQlVsAJdNiKdYmYVoKlaRv85IfVunYzO0IKXsyl7JCUjCpoG20f0a04COwfneQAGGwd5oa+T8yO5hzuyDb/XcxxmK01EpqOyuxINew=="
+	iv := "r7BXXKkLb8qrSNn05n0qiA=="
+
+	data, err := DecryptUserData(appid, sessionKey, encryptedData, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := struct {
+		OpenId   string `json:"openId"`
+		NickName string `json:"nickName"`
+	}{}
+	if err = json.Unmarshal(data, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.OpenId != "oGZUI0egBJY1zhBYw2KhdUfwVJJE" || user.NickName != "Band" {
+		t.Errorf("DecryptUserData() = %s", data)
+	}
+
+	if _, err = DecryptUserData("wxOTHER", sessionKey, encryptedData, iv); err != ErrorWatermarkMismatch {
+		t.Errorf("DecryptUserData() error = %v, want ErrorWatermarkMismatch", err)
+	}
+	if _, err = DecryptUserData(appid, "c2hvcnQ=", encryptedData, iv); err == nil {
+		t.Errorf("DecryptUserData() want error for short session_key")
+	}
+	if _, err = DecryptUserData(appid, sessionKey, "YWJj", iv); err == nil {
+		t.Errorf("DecryptUserData() want error for truncated encryptedData")
+	}
+}
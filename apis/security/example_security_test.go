@@ -0,0 +1,55 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/apis/security"
+)
+
+func ExampleMsgSecCheck() {
+	var ctx *offiaccount.OffiAccount
+
+	pass, err := security.MsgSecCheck(ctx, "CONTENT")
+
+	fmt.Println(pass, err)
+}
+
+func ExampleImgSecCheck() {
+	var ctx *offiaccount.OffiAccount
+
+	img, err := os.Open("image.jpg")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer img.Close()
+
+	pass, err := security.ImgSecCheck(ctx, img)
+
+	fmt.Println(pass, err)
+}
+
+func ExampleMediaCheckAsync() {
+	var ctx *offiaccount.OffiAccount
+
+	// 检测结果 通过 wxa_media_check 事件推送 (type_event.EventWxaMediaCheck)
+	traceId, err := security.MediaCheckAsync(ctx, []byte(`{"media_url":"https://example.com/a.jpg","media_type":2}`))
+
+	fmt.Println(traceId, err)
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package security 内容安全
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const (
+	apiMsgSecCheck     = "/wxa/msg_sec_check"
+	apiImgSecCheck     = "/wxa/img_sec_check"
+	apiMediaCheckAsync = "/wxa/media_check_async"
+)
+
+// ErrcodeRiskyContent 内容含有违法违规内容
+const ErrcodeRiskyContent = 87014
+
+/*
+文本内容安全检测
+
+检查一段文本是否含有违法违规内容；pass 为 false 表示含有违规内容 (errcode 87014)
+
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/sec-check/security.msgSecCheck.html
+
+POST https://api.weixin.qq.com/wxa/msg_sec_check?access_token=ACCESS_TOKEN
+*/
+func MsgSecCheck(ctx *offiaccount.OffiAccount, content string) (pass bool, err error) {
+	payload, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return
+	}
+	_, err = ctx.Client.HTTPPost(apiMsgSecCheck, bytes.NewReader(payload), "application/json;charset=utf-8")
+	return verdict(err)
+}
+
+/*
+图片内容安全检测
+
+校验一张图片是否含有违法违规内容，图片尺寸不超过 750px x 1334px；pass 为 false 表示含有违规内容 (errcode 87014)
+
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/sec-check/security.imgSecCheck.html
+
+POST(@media) https://api.weixin.qq.com/wxa/img_sec_check?access_token=ACCESS_TOKEN
+*/
+func ImgSecCheck(ctx *offiaccount.OffiAccount, img io.Reader) (pass bool, err error) {
+	// 缓存到内存 以便 access_token 过期时 重新发送
+	body := &bytes.Buffer{}
+	m := multipart.NewWriter(body)
+	part, err := m.CreateFormFile("media", "media")
+	if err != nil {
+		return
+	}
+	if _, err = io.Copy(part, img); err != nil {
+		return
+	}
+	if err = m.Close(); err != nil {
+		return
+	}
+
+	_, err = ctx.Client.HTTPPost(apiImgSecCheck, body, m.FormDataContentType())
+	return verdict(err)
+}
+
+/*
+异步校验图片/音频是否含有违法违规内容
+
+返回 trace_id，检测结果 通过 wxa_media_check 事件 推送 (见 type_event.EventWxaMediaCheck)，以 trace_id 对应
+
+	payload := []byte(`{"media_url":"https://example.com/a.jpg","media_type":2}`)
+
+See: https://developers.weixin.qq.com/miniprogram/dev/api-backend/open-api/sec-check/security.mediaCheckAsync.html
+
+POST https://api.weixin.qq.com/wxa/media_check_async?access_token=ACCESS_TOKEN
+*/
+func MediaCheckAsync(ctx *offiaccount.OffiAccount, payload []byte) (traceId string, err error) {
+	resp, err := ctx.Client.HTTPPost(apiMediaCheckAsync, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		TraceId string `json:"trace_id"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	return result.TraceId, nil
+}
+
+// verdict 将 违规内容错误 87014 转换为 检测不通过
+func verdict(err error) (pass bool, e error) {
+	if err == nil {
+		return true, nil
+	}
+	if wxErr, ok := offiaccount.AsWXError(err); ok && wxErr.Errcode == ErrcodeRiskyContent {
+		return false, nil
+	}
+	return false, err
+}
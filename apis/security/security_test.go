@@ -0,0 +1,90 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestMsgSecCheck(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiMsgSecCheck, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "RISKY"):
+			w.Write([]byte(`{"errcode":87014,"errmsg":"risky content"}`))
+		case strings.Contains(string(body), "BUSY"):
+			w.Write([]byte(`{"errcode":-1,"errmsg":"system error"}`))
+		default:
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		}
+	})
+
+	tests := []struct {
+		content  string
+		wantPass bool
+		wantErr  bool
+	}{
+		{content: "hello", wantPass: true},
+		{content: "RISKY", wantPass: false},
+		{content: "BUSY", wantPass: false, wantErr: true},
+	}
+	for _, tt := range tests {
+		pass, err := MsgSecCheck(test.MockOffiAccount, tt.content)
+		if pass != tt.wantPass || (err != nil) != tt.wantErr {
+			t.Errorf("MsgSecCheck(%s) = %v, %v, want %v, wantErr %v", tt.content, pass, err, tt.wantPass, tt.wantErr)
+		}
+	}
+}
+
+func TestImgSecCheck(t *testing.T) {
+	var image string
+	test.MockSvrHandler.HandleFunc(apiImgSecCheck, func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("media")
+		if err != nil {
+			w.Write([]byte(`{"errcode":41005,"errmsg":"media data missing"}`))
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		image = string(data)
+		w.Write([]byte(`{"errcode":87014,"errmsg":"risky content"}`))
+	})
+
+	pass, err := ImgSecCheck(test.MockOffiAccount, strings.NewReader("IMAGE"))
+	if pass || err != nil || image != "IMAGE" {
+		t.Errorf("ImgSecCheck() = %v, %v, image = %s", pass, err, image)
+	}
+}
+
+func TestMediaCheckAsync(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiMediaCheckAsync, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","trace_id":"967e945cd8a3e458f3c74dcb886068e9"}`))
+	})
+
+	traceId, err := MediaCheckAsync(test.MockOffiAccount, []byte(`{"media_url":"https://example.com/a.jpg","media_type":2}`))
+	if err != nil || traceId != "967e945cd8a3e458f3c74dcb886068e9" {
+		t.Errorf("MediaCheckAsync() = %s, %v", traceId, err)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// BatchGetLimit 批量获取用户基本信息 一次最多拉取 100 条
+const BatchGetLimit = 100
+
+// UserInfo 用户基本信息
+type UserInfo struct {
+	Subscribe      int    `json:"subscribe"` // 0 代表此用户没有关注该公众号，拉取不到其余信息
+	Openid         string `json:"openid"`
+	Nickname       string `json:"nickname"`
+	Sex            int    `json:"sex"`
+	Language       string `json:"language"`
+	City           string `json:"city"`
+	Province       string `json:"province"`
+	Country        string `json:"country"`
+	Headimgurl     string `json:"headimgurl"`
+	SubscribeTime  int64  `json:"subscribe_time"`
+	Unionid        string `json:"unionid"`
+	Remark         string `json:"remark"`
+	Groupid        int    `json:"groupid"`
+	TagidList      []int  `json:"tagid_list"`
+	SubscribeScene string `json:"subscribe_scene"`
+	QrScene        int    `json:"qr_scene"`
+	QrSceneStr     string `json:"qr_scene_str"`
+}
+
+/*
+PartialError 批量接口 整体调用成功(errcode 为 0) 但部分条目失败
+
+目前适用于：
+
+- BatchGetUsers: 请求的 openid 未出现在 user_info_list 中 记入 Missing；整批调用失败（如 40003 非法 openid）记入 Failed
+
+批量打标签 (tags.TagUsers) 微信只返回整体结果，失败以批次为单位 见 tags.BatchError
+*/
+type PartialError struct {
+	Missing []string         // 未返回结果的 openid
+	Failed  map[string]error // 所在批次调用失败的 openid
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("partial result: %d missing [%s], %d failed", len(e.Missing), strings.Join(e.Missing, ","), len(e.Failed))
+}
+
+/*
+BatchGetUsers 批量获取用户基本信息
+
+openids 超过 BatchGetLimit 时 自动分批调用 BatchGetUserInfo
+
+返回所有成功获取的用户信息，存在未返回或失败的 openid 时 err 为 *PartialError
+*/
+func BatchGetUsers(ctx *offiaccount.OffiAccount, openids []string, lang string) (users []UserInfo, err error) {
+	type item struct {
+		Openid string `json:"openid"`
+		Lang   string `json:"lang,omitempty"`
+	}
+
+	partial := &PartialError{Failed: map[string]error{}}
+	for start := 0; start < len(openids); start += BatchGetLimit {
+		end := start + BatchGetLimit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		chunk := openids[start:end]
+
+		list := make([]item, 0, len(chunk))
+		for _, openid := range chunk {
+			list = append(list, item{Openid: openid, Lang: lang})
+		}
+		payload, _ := json.Marshal(map[string][]item{"user_list": list})
+
+		resp, callErr := BatchGetUserInfo(ctx, payload)
+		if callErr == nil {
+			result := struct {
+				UserInfoList []UserInfo `json:"user_info_list"`
+			}{}
+			callErr = json.Unmarshal(resp, &result)
+			if callErr == nil {
+				returned := map[string]bool{}
+				for _, user := range result.UserInfoList {
+					returned[user.Openid] = true
+				}
+				users = append(users, result.UserInfoList...)
+				for _, openid := range chunk {
+					if !returned[openid] {
+						partial.Missing = append(partial.Missing, openid)
+					}
+				}
+				continue
+			}
+		}
+
+		for _, openid := range chunk {
+			partial.Failed[openid] = callErr
+		}
+	}
+
+	if len(partial.Missing) > 0 || len(partial.Failed) > 0 {
+		err = partial
+	}
+	return
+}
+
+/*
+SplitSubscribed 按 subscribe 拆分用户，subscribed 为 当前关注的用户，unsubscribed 为 已取消关注（subscribe 为 0）的用户
+
+两部分 均保持 users 中的顺序，可用于 BatchGetUsers 等 已解析的结果（原始响应 见 ParseSubscribedUsers）
+
+	subscribed, unsubscribed := user.SplitSubscribed(users)
+*/
+func SplitSubscribed(users []UserInfo) (subscribed []UserInfo, unsubscribed []UserInfo) {
+	for _, user := range users {
+		if user.Subscribe == 1 {
+			subscribed = append(subscribed, user)
+		} else {
+			unsubscribed = append(unsubscribed, user)
+		}
+	}
+	return
+}
+
+/*
+ParseSubscribedUsers 解析 BatchGetUserInfo 响应，subscribed 为 当前关注的用户，unsubscribed 为 已取消关注的用户
+
+取消关注的用户 只返回 openid 等少量信息，群发/通知 等场景 应排除
+
+响应中 errcode 不为 0 时 返回 *offiaccount.WXError
+*/
+func ParseSubscribedUsers(resp []byte) (subscribed []UserInfo, unsubscribed []UserInfo, err error) {
+	var users []UserInfo
+	err = DecodeUserInfoList(bytes.NewReader(resp), func(info UserInfo) error {
+		users = append(users, info)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	subscribed, unsubscribed = SplitSubscribed(users)
+	return
+}
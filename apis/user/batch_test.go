@@ -0,0 +1,111 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestBatchGetUsers(t *testing.T) {
+	openids := make([]string, 150)
+	for i := range openids {
+		openids[i] = "OPENID" + strconv.Itoa(i)
+	}
+
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiBatchGetUserInfo, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		payload, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			UserList []struct {
+				Openid string `json:"openid"`
+				Lang   string `json:"lang"`
+			} `json:"user_list"`
+		}{}
+		_ = json.Unmarshal(payload, &req)
+
+		if calls == 2 {
+			w.Write([]byte(`{"errcode":40003,"errmsg":"invalid openid"}`))
+			return
+		}
+
+		// 第一批 最后一个 openid 不返回
+		list := []UserInfo{}
+		users := req.UserList
+		if len(users) > 1 {
+			users = users[:len(users)-1]
+		}
+		for _, u := range users {
+			list = append(list, UserInfo{Subscribe: 1, Openid: u.Openid, Language: u.Lang})
+		}
+		data, _ := json.Marshal(map[string][]UserInfo{"user_info_list": list})
+		w.Write(data)
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestBatchGetUsers", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	users, err := BatchGetUsers(ctx, openids, "zh_CN")
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if len(users) != 99 || users[0].Language != "zh_CN" {
+		t.Errorf("users = %d, want 99 with lang zh_CN", len(users))
+	}
+
+	partial, ok := err.(*PartialError)
+	if !ok {
+		t.Fatalf("err = %v, want *PartialError", err)
+	}
+	if len(partial.Missing) != 1 || partial.Missing[0] != "OPENID99" {
+		t.Errorf("Missing = %v, want [OPENID99]", partial.Missing)
+	}
+	if wxErr, ok := offiaccount.AsWXError(partial.Failed["OPENID100"]); len(partial.Failed) != 50 || !ok || wxErr.Errcode != 40003 {
+		t.Errorf("Failed = %d %v, want 50 with 40003", len(partial.Failed), partial.Failed["OPENID100"])
+	}
+
+	if users, err = BatchGetUsers(ctx, openids[:1], ""); err != nil || len(users) != 1 {
+		t.Errorf("BatchGetUsers() = %v, %v, want one user", users, err)
+	}
+}
+
+func TestParseSubscribedUsers(t *testing.T) {
+	resp := []byte(`{"user_info_list":[{"subscribe":1,"openid":"OPENID1","nickname":"iWithery"},{"subscribe":0,"openid":"OPENID2"},{"subscribe":1,"openid":"OPENID3"}]}`)
+	subscribed, unsubscribed, err := ParseSubscribedUsers(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscribed) != 2 || subscribed[0].Openid != "OPENID1" || subscribed[1].Openid != "OPENID3" {
+		t.Errorf("ParseSubscribedUsers() subscribed = %+v", subscribed)
+	}
+	if len(unsubscribed) != 1 || unsubscribed[0].Openid != "OPENID2" {
+		t.Errorf("ParseSubscribedUsers() unsubscribed = %+v", unsubscribed)
+	}
+
+	_, _, err = ParseSubscribedUsers([]byte(`{"errcode":40003,"errmsg":"invalid openid"}`))
+	if wxErr, ok := err.(*offiaccount.WXError); !ok || wxErr.Errcode != 40003 {
+		t.Errorf("ParseSubscribedUsers() error = %v, want 40003", err)
+	}
+}
@@ -84,3 +84,40 @@ func ExampleBatchUnBlackList() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleBatchGetUsers() {
+	var ctx *offiaccount.OffiAccount
+
+	users, err := user.BatchGetUsers(ctx, []string{"OPENID1", "OPENID2"}, "zh_CN")
+	if partial, ok := err.(*user.PartialError); ok {
+		fmt.Println(partial.Missing, partial.Failed)
+	}
+
+	fmt.Println(users, err)
+}
+
+func ExampleStreamBatchGetUserInfo() {
+	var ctx *offiaccount.OffiAccount
+
+	payload := []byte(`{"user_list":[{"openid":"OPENID1","lang":"zh_CN"},{"openid":"OPENID2"}]}`)
+	err := user.StreamBatchGetUserInfo(ctx, payload, func(info user.UserInfo) error {
+		fmt.Println(info.Openid, info.Nickname)
+		return nil
+	})
+
+	fmt.Println(err)
+}
+
+func ExampleParseSubscribedUsers() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := user.BatchGetUserInfo(ctx, []byte(`{"user_list":[{"openid":"OPENID1"},{"openid":"OPENID2"}]}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	subscribed, unsubscribed, err := user.ParseSubscribedUsers(resp)
+
+	fmt.Println(subscribed, unsubscribed, err)
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+DecodeUserInfoList 流式解析 批量获取用户基本信息 的响应，每解析出一条 user_info_list 元素 即回调 fn
+
+不会一次性将整个列表 解析到内存中；fn 返回错误时 停止解析 并返回该错误
+
+响应中 errcode 不为 0 时 返回 *offiaccount.WXError
+*/
+func DecodeUserInfoList(r io.Reader, fn func(info UserInfo) error) (err error) {
+	decoder := json.NewDecoder(r)
+	if err = expectDelim(decoder, '{'); err != nil {
+		return
+	}
+
+	wxErr := offiaccount.WXError{}
+	for decoder.More() {
+		var token json.Token
+		token, err = decoder.Token()
+		if err != nil {
+			return
+		}
+
+		switch token {
+		case "user_info_list":
+			if err = expectDelim(decoder, '['); err != nil {
+				return
+			}
+			for decoder.More() {
+				info := UserInfo{}
+				if err = decoder.Decode(&info); err != nil {
+					return
+				}
+				if err = fn(info); err != nil {
+					return
+				}
+			}
+			if err = expectDelim(decoder, ']'); err != nil {
+				return
+			}
+		case "errcode":
+			err = decoder.Decode(&wxErr.Errcode)
+		case "errmsg":
+			err = decoder.Decode(&wxErr.Errmsg)
+		default:
+			var skip json.RawMessage
+			err = decoder.Decode(&skip)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if wxErr.Errcode != 0 {
+		return &wxErr
+	}
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("user_info_list: unexpected token %v, want %v", token, delim)
+	}
+	return nil
+}
+
+/*
+StreamBatchGetUserInfo 批量获取用户基本信息 并流式解析响应 (见 BatchGetUserInfo 和 DecodeUserInfoList)
+
+	payload := []byte(`{"user_list":[{"openid":"OPENID1","lang":"zh_CN"},{"openid":"OPENID2"}]}`)
+	err := user.StreamBatchGetUserInfo(ctx, payload, func(info user.UserInfo) error {
+		return db.Save(info)
+	})
+*/
+func StreamBatchGetUserInfo(ctx *offiaccount.OffiAccount, payload []byte, fn func(info UserInfo) error) (err error) {
+	response, err := ctx.Client.DoRaw(http.MethodPost, apiBatchGetUserInfo, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Status %s", response.Status)
+	}
+	return DecodeUserInfoList(response.Body, fn)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestDecodeUserInfoList(t *testing.T) {
+	resp := `{"user_info_list":[{"subscribe":1,"openid":"OPENID1","nickname":"iWithery","tagid_list":[2,3]},{"subscribe":0,"openid":"OPENID2"}],"extra":{"a":[1,2]}}`
+
+	var got []UserInfo
+	err := DecodeUserInfoList(strings.NewReader(resp), func(info UserInfo) error {
+		got = append(got, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Nickname != "iWithery" || len(got[0].TagidList) != 2 || got[1].Openid != "OPENID2" {
+		t.Errorf("DecodeUserInfoList() = %+v", got)
+	}
+
+	// fn 返回错误 停止解析
+	stop := errors.New("stop")
+	var count int
+	err = DecodeUserInfoList(strings.NewReader(resp), func(info UserInfo) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("DecodeUserInfoList() err = %v count = %d, want stop after 1", err, count)
+	}
+
+	err = DecodeUserInfoList(strings.NewReader(`{"errcode":40003,"errmsg":"invalid openid"}`), func(info UserInfo) error { return nil })
+	var wxErr *offiaccount.WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 40003 {
+		t.Errorf("DecodeUserInfoList() error = %v, want *WXError 40003", err)
+	}
+
+	if err = DecodeUserInfoList(strings.NewReader(`[]`), func(info UserInfo) error { return nil }); err == nil {
+		t.Errorf("DecodeUserInfoList() want error for non-object response")
+	}
+}
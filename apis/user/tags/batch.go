@@ -0,0 +1,98 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// BatchLimit 批量为用户打标签/取消标签 每次传入的 openid 列表个数不能超过 50 个
+const BatchLimit = 50
+
+// ChunkError 某一批次调用失败
+type ChunkError struct {
+	Index   int      // 批次序号 从 0 开始
+	Openids []string // 该批次的 openid 列表
+	Err     error
+}
+
+func (e ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d (%d openids): %s", e.Index, len(e.Openids), e.Err)
+}
+
+// BatchError 分批调用时 失败批次的集合 未出现在列表中的批次均已成功
+type BatchError []ChunkError
+
+func (e BatchError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, chunkErr := range e {
+		msgs = append(msgs, chunkErr.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// batchFunc 分批调用的接口 BatchTagging 或 BatchUnTagging
+type batchFunc func(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error)
+
+/*
+TagUsers 为一批用户打上标签
+
+openids 超过 BatchLimit 时 自动分批调用 BatchTagging，某些批次失败 返回 BatchError
+*/
+func TagUsers(ctx *offiaccount.OffiAccount, tagId int, openids []string) (err error) {
+	return batchMembers(ctx, BatchTagging, tagId, openids)
+}
+
+/*
+UntagUsers 为一批用户取消标签
+
+openids 超过 BatchLimit 时 自动分批调用 BatchUnTagging，某些批次失败 返回 BatchError
+*/
+func UntagUsers(ctx *offiaccount.OffiAccount, tagId int, openids []string) (err error) {
+	return batchMembers(ctx, BatchUnTagging, tagId, openids)
+}
+
+func batchMembers(ctx *offiaccount.OffiAccount, call batchFunc, tagId int, openids []string) (err error) {
+	if tagId <= 0 {
+		return fmt.Errorf("invalid tagid %d", tagId)
+	}
+
+	var batchErr BatchError
+	for index, start := 0, 0; start < len(openids); index, start = index+1, start+BatchLimit {
+		end := start + BatchLimit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		chunk := openids[start:end]
+
+		payload, _ := json.Marshal(struct {
+			OpenidList []string `json:"openid_list"`
+			Tagid      int      `json:"tagid"`
+		}{chunk, tagId})
+
+		if _, err := call(ctx, payload); err != nil {
+			batchErr = append(batchErr, ChunkError{Index: index, Openids: chunk, Err: err})
+		}
+	}
+
+	if len(batchErr) > 0 {
+		return batchErr
+	}
+	return nil
+}
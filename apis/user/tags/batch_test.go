@@ -0,0 +1,88 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestTagUsers(t *testing.T) {
+	openids := make([]string, 120)
+	for i := range openids {
+		openids[i] = "OPENID" + strconv.Itoa(i)
+	}
+
+	var chunkSizes []int
+	var paths []string
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			OpenidList []string `json:"openid_list"`
+			Tagid      int      `json:"tagid"`
+		}{}
+		_ = json.Unmarshal(payload, &req)
+		if req.Tagid != 134 {
+			t.Errorf("tagid = %d, want 134", req.Tagid)
+		}
+		paths = append(paths, r.URL.Path)
+		chunkSizes = append(chunkSizes, len(req.OpenidList))
+		if len(chunkSizes) == 2 {
+			w.Write([]byte(`{"errcode":40032,"errmsg":"invalid openid list size"}`))
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiBatchTagging, handle)
+	handler.HandleFunc(apiBatchUnTagging, handle)
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestTagUsers", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	err := TagUsers(ctx, 134, openids)
+	if len(chunkSizes) != 3 || chunkSizes[0] != 50 || chunkSizes[1] != 50 || chunkSizes[2] != 20 {
+		t.Errorf("chunkSizes = %v, want [50 50 20]", chunkSizes)
+	}
+
+	batchErr, ok := err.(BatchError)
+	if !ok || len(batchErr) != 1 {
+		t.Fatalf("err = %v, want BatchError with one chunk", err)
+	}
+	if batchErr[0].Index != 1 || batchErr[0].Openids[0] != "OPENID50" {
+		t.Errorf("ChunkError = %+v", batchErr[0])
+	}
+	if wxErr, ok := offiaccount.AsWXError(batchErr[0].Err); !ok || wxErr.Errcode != 40032 {
+		t.Errorf("ChunkError.Err = %v, want 40032", batchErr[0].Err)
+	}
+
+	paths = nil
+	if err = UntagUsers(ctx, 134, openids[:10]); err != nil || len(paths) != 1 || paths[0] != apiBatchUnTagging {
+		t.Errorf("UntagUsers() = %v, paths = %v", err, paths)
+	}
+
+	paths = nil
+	if err = TagUsers(ctx, 0, openids); err == nil || len(paths) != 0 {
+		t.Errorf("TagUsers() want error without request on tagid 0, paths = %v", paths)
+	}
+}
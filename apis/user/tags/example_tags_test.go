@@ -91,3 +91,35 @@ func ExampleGetTagIdList() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleTagUsers() {
+	var ctx *offiaccount.OffiAccount
+
+	err := tags.TagUsers(ctx, 134, []string{"OPENID1", "OPENID2"})
+
+	fmt.Println(err)
+}
+
+func ExampleParseTags() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := tags.Get(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	list, err := tags.ParseTags(resp)
+	for _, tag := range list {
+		fmt.Println(tag.Id, tag.Name, tag.Count)
+	}
+	fmt.Println(err)
+}
+
+func ExampleTotalTaggedUsers() {
+	var ctx *offiaccount.OffiAccount
+
+	total, err := tags.TotalTaggedUsers(ctx)
+
+	fmt.Println(total, err)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"encoding/json"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// Tag 用户标签 Count 为 此标签下的粉丝数
+type Tag struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+/*
+ParseTags 解析 Get 响应
+
+	{
+	  "tags": [
+	    {"id": 1, "name": "每天一罐可乐星人", "count": 0},
+	    {"id": 2, "name": "星标组", "count": 0},
+	    {"id": 127, "name": "广东", "count": 5}
+	  ]
+	}
+*/
+func ParseTags(resp []byte) (tags []Tag, err error) {
+	result := struct {
+		Tags []Tag `json:"tags"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	return result.Tags, nil
+}
+
+/*
+TotalTaggedUsers 所有标签的 粉丝数 之和
+
+一个用户 可以有多个标签，会被重复计数，因此结果 不等于 打了标签的用户数
+*/
+func TotalTaggedUsers(ctx *offiaccount.OffiAccount) (total int, err error) {
+	resp, err := Get(ctx)
+	if err != nil {
+		return
+	}
+	tags, err := ParseTags(resp)
+	if err != nil {
+		return
+	}
+	for _, tag := range tags {
+		total += tag.Count
+	}
+	return
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseTags(t *testing.T) {
+	got, err := ParseTags([]byte(`{"tags":[{"id":1,"name":"每天一罐可乐星人","count":0},{"id":2,"name":"星标组","count":3},{"id":127,"name":"广东","count":5}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Tag{{Id: 1, Name: "每天一罐可乐星人", Count: 0}, {Id: 2, Name: "星标组", Count: 3}, {Id: 127, Name: "广东", Count: 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags() = %+v, want %+v", got, want)
+	}
+
+	if _, err = ParseTags([]byte("{")); err == nil {
+		t.Error("ParseTags() want error for invalid json")
+	}
+}
+
+func TestTotalTaggedUsers(t *testing.T) {
+	var resp = `{"tags":[{"id":2,"name":"星标组","count":3},{"id":127,"name":"广东","count":5}]}`
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiGet, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(resp))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestTotalTaggedUsers", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	if total, err := TotalTaggedUsers(ctx); err != nil || total != 8 {
+		t.Errorf("TotalTaggedUsers() = %d, %v, want 8", total, err)
+	}
+
+	resp = `{"errcode":-1,"errmsg":"system error"}`
+	_, err := TotalTaggedUsers(ctx)
+	if wxErr, ok := offiaccount.AsWXError(err); !ok || wxErr.Errcode != -1 {
+		t.Errorf("TotalTaggedUsers() error = %v, want -1", err)
+	}
+}
@@ -55,7 +55,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/User_Management/Get_users_
 GET https://api.weixin.qq.com/cgi-bin/user/info?access_token=ACCESS_TOKEN&openid=OPENID&lang=zh_CN
 */
 func GetUserInfo(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiGetUserInfo + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiGetUserInfo, params)
 }
 
 /*
@@ -81,7 +81,7 @@ See: https://developers.weixin.qq.com/doc/offiaccount/User_Management/Getting_a_
 GET https://api.weixin.qq.com/cgi-bin/user/get?access_token=ACCESS_TOKEN&next_openid=NEXT_OPENID
 */
 func Get(ctx *offiaccount.OffiAccount, params url.Values) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(apiGet + "?" + params.Encode())
+	return ctx.Client.HTTPGetWithParams(apiGet, params)
 }
 
 /*
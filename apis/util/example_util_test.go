@@ -16,6 +16,7 @@ package util_test
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/offiaccount/apis/util"
@@ -54,3 +55,32 @@ func ExampleClearQuota() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleGetQuota() {
+	var ctx *offiaccount.OffiAccount
+
+	payload := []byte("{}")
+	resp, err := util.GetQuota(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleClearQuotaV2() {
+	var ctx *offiaccount.OffiAccount
+
+	resp, err := util.ClearQuotaV2(ctx)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleNetworkStatus() {
+	var ctx *offiaccount.OffiAccount
+
+	status, err := util.NetworkStatus(ctx, "/cgi-bin/message/custom/send")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(status.AllowsCallback(net.ParseIP("101.226.103.1")), status.Quota["/cgi-bin/message/custom/send"].Remain)
+}
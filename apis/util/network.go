@@ -0,0 +1,181 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// Quota 接口 每日调用额度
+type Quota struct {
+	DailyLimit int64 `json:"daily_limit"`
+	Used       int64 `json:"used"`
+	Remain     int64 `json:"remain"`
+}
+
+// ParseQuota 解析 GetQuota 响应
+func ParseQuota(resp []byte) (quota Quota, err error) {
+	result := struct {
+		Quota Quota `json:"quota"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	return result.Quota, err
+}
+
+/*
+ParseIpList 解析 GetCallbackIp / GetApiDomainIp 响应中的 ip_list
+
+列表项 可以是 IP 网段（如 101.226.103.0/25）或 单个 IP，单个 IP 解析为 /32（IPv6 为 /128）
+*/
+func ParseIpList(resp []byte) (ipList []string, nets []*net.IPNet, err error) {
+	result := struct {
+		IpList []string `json:"ip_list"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+
+	for _, item := range result.IpList {
+		item = strings.TrimSpace(item)
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, nil, fmt.Errorf("util: invalid ip %q", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			ipList = append(ipList, item)
+			continue
+		}
+
+		var ipNet *net.IPNet
+		_, ipNet, err = net.ParseCIDR(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		nets = append(nets, ipNet)
+		ipList = append(ipList, item)
+	}
+	return
+}
+
+// NetworkStatusResult 微信服务器 IP 地址 及 接口调用额度
+type NetworkStatusResult struct {
+	CallbackIpList  []string     // 微信推送消息 使用的 IP（网段）
+	CallbackNets    []*net.IPNet // CallbackIpList 解析后的网段
+	ApiDomainIpList []string     // api.weixin.qq.com 的解析地址
+	ApiDomainNets   []*net.IPNet // ApiDomainIpList 解析后的网段
+
+	Quota map[string]Quota // 按 cgi_path 索引的 每日调用额度
+}
+
+// AllowsCallback ip 是否为 微信推送消息 使用的 IP
+func (status NetworkStatusResult) AllowsCallback(ip net.IP) bool {
+	return containsIp(status.CallbackNets, ip)
+}
+
+// AllowsApiDomain ip 是否为 api.weixin.qq.com 的解析地址
+func (status NetworkStatusResult) AllowsApiDomain(ip net.IP) bool {
+	return containsIp(status.ApiDomainNets, ip)
+}
+
+func containsIp(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+NetworkStatus 并发查询 微信推送 IP、api 域名 IP 以及 cgiPaths 对应接口的 每日调用额度，合并为一个结果
+
+微信没有 全局 额度接口，额度需按接口路径查询（如 /cgi-bin/message/custom/send）；cgiPaths 为空时 不查询额度
+
+任一请求失败 返回遇到的第一个错误
+
+	status, err := util.NetworkStatus(ctx, "/cgi-bin/message/custom/send")
+	if status.AllowsCallback(net.ParseIP(remoteIp)) {
+		// ...
+	}
+*/
+func NetworkStatus(ctx *offiaccount.OffiAccount, cgiPaths ...string) (status NetworkStatusResult, err error) {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	setErr := func(e error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if err == nil {
+			err = e
+		}
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		resp, e := GetCallbackIp(ctx)
+		if e == nil {
+			status.CallbackIpList, status.CallbackNets, e = ParseIpList(resp)
+		}
+		if e != nil {
+			setErr(fmt.Errorf("getcallbackip: %w", e))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		resp, e := GetApiDomainIp(ctx)
+		if e == nil {
+			status.ApiDomainIpList, status.ApiDomainNets, e = ParseIpList(resp)
+		}
+		if e != nil {
+			setErr(fmt.Errorf("get_api_domain_ip: %w", e))
+		}
+	}()
+
+	status.Quota = make(map[string]Quota, len(cgiPaths))
+	for _, cgiPath := range cgiPaths {
+		wg.Add(1)
+		go func(cgiPath string) {
+			defer wg.Done()
+			payload, _ := json.Marshal(map[string]string{"cgi_path": cgiPath})
+			resp, e := GetQuota(ctx, payload)
+			var quota Quota
+			if e == nil {
+				quota, e = ParseQuota(resp)
+			}
+			if e != nil {
+				setErr(fmt.Errorf("quota %s: %w", cgiPath, e))
+				return
+			}
+			lock.Lock()
+			status.Quota[cgiPath] = quota
+			lock.Unlock()
+		}(cgiPath)
+	}
+
+	wg.Wait()
+	return
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestParseIpList(t *testing.T) {
+	ipList, nets, err := ParseIpList([]byte(`{"ip_list":["101.226.103.0/25","101.226.62.77","240e:e1:a900::/52"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ipList) != 3 || len(nets) != 3 {
+		t.Fatalf("ParseIpList() = %v, %v", ipList, nets)
+	}
+	if nets[0].String() != "101.226.103.0/25" || nets[1].String() != "101.226.62.77/32" || nets[2].String() != "240e:e1:a900::/52" {
+		t.Errorf("ParseIpList() nets = %v", nets)
+	}
+
+	if _, _, err = ParseIpList([]byte(`{"ip_list":["not ip"]}`)); err == nil {
+		t.Error("ParseIpList() want error")
+	}
+}
+
+func TestNetworkStatus(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiGetCallbackIp, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip_list":["101.226.103.0/25","101.226.62.77"]}`))
+	})
+	handler.HandleFunc(apiGetApiDomainIp, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip_list":["59.37.97.0/24"]}`))
+	})
+	handler.HandleFunc(apiGetQuota, func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["cgi_path"] == "/cgi-bin/invalid" {
+			_, _ = w.Write([]byte(`{"errcode":76021,"errmsg":"cgi_path not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","quota":{"daily_limit":500000,"used":1,"remain":499999}}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "TestNetworkStatus", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	ctx.SetLogger(nil)
+
+	status, err := NetworkStatus(ctx, "/cgi-bin/message/custom/send")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.AllowsCallback(net.ParseIP("101.226.103.1")) || !status.AllowsCallback(net.ParseIP("101.226.62.77")) || status.AllowsCallback(net.ParseIP("101.226.103.200")) {
+		t.Errorf("CallbackNets = %v", status.CallbackNets)
+	}
+	if !status.AllowsApiDomain(net.ParseIP("59.37.97.10")) || len(status.ApiDomainIpList) != 1 {
+		t.Errorf("ApiDomainNets = %v", status.ApiDomainNets)
+	}
+	if quota := status.Quota["/cgi-bin/message/custom/send"]; quota.DailyLimit != 500000 || quota.Remain != 499999 {
+		t.Errorf("Quota = %v", status.Quota)
+	}
+
+	if _, err = NetworkStatus(ctx, "/cgi-bin/invalid"); err == nil {
+		t.Error("NetworkStatus() want error")
+	}
+}
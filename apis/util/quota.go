@@ -0,0 +1,72 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/fastwego/offiaccount"
+)
+
+const apiClearQuotaV2 = "/cgi-bin/clear_quota/v2"
+
+/*
+使用AppSecret重置 API 调用次数
+
+与 ClearQuota 不同，不需要 access_token，直接使用 Config.Appid 和 当前的 AppSecret (见 OffiAccount.Secret) 调用；适用于 获取 access_token 本身 被限频的场景
+
+See: https://developers.weixin.qq.com/doc/offiaccount/openApi/clearQuotaByAppSecret.html
+
+POST https://api.weixin.qq.com/cgi-bin/clear_quota/v2
+*/
+func ClearQuotaV2(ctx *offiaccount.OffiAccount) (resp []byte, err error) {
+	payload, err := json.Marshal(map[string]string{
+		"appid":     ctx.Config.Appid,
+		"appsecret": ctx.Secret(),
+	})
+	if err != nil {
+		return
+	}
+
+	response, err := ctx.Client.HTTPClient().Post(offiaccount.JoinUrl(ctx.ServerUrlFor(apiClearQuotaV2), apiClearQuotaV2), "application/json;charset=utf-8", bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Status %s", response.Status)
+		return
+	}
+
+	resp, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	wxErr, err := offiaccount.ParseWXError(resp)
+	if err != nil {
+		return
+	}
+	if wxErr.Errcode != 0 {
+		wxErr.Raw = string(resp)
+		return resp, &wxErr
+	}
+	return
+}
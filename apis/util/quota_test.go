@@ -0,0 +1,77 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/test"
+)
+
+func TestClearQuotaV2(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiClearQuotaV2, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "" {
+			t.Errorf("clear_quota/v2 should not carry access_token")
+		}
+		req := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["appid"] == "APPID" && req["appsecret"] == "SECRET" {
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":41004,"errmsg":"appsecret missing"}`))
+	})
+
+	resp, err := ClearQuotaV2(test.MockOffiAccount)
+	if err != nil || string(resp) != `{"errcode":0,"errmsg":"ok"}` {
+		t.Errorf("ClearQuotaV2() = %s, %v", resp, err)
+	}
+
+	ctx := offiaccount.New(offiaccount.Config{Appid: "APPID"})
+	_, err = ClearQuotaV2(ctx)
+	var wxErr *offiaccount.WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 41004 {
+		t.Errorf("ClearQuotaV2() error = %v, want *WXError 41004", err)
+	}
+}
+
+func TestClearQuotaV2_HostOverrides(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s should be sent to the override host", r.URL.Path)
+	}))
+	defer api.Close()
+	var hit string
+	quota := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = r.URL.Path
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer quota.Close()
+
+	ctx := offiaccount.New(offiaccount.Config{
+		Appid:         "APPID",
+		Secret:        "SECRET",
+		BaseURL:       api.URL,
+		HostOverrides: map[string]string{apiClearQuotaV2: quota.URL},
+	})
+	ctx.SetLogger(nil)
+	if _, err := ClearQuotaV2(ctx); err != nil || hit != apiClearQuotaV2 {
+		t.Errorf("ClearQuotaV2() hit = %s, err = %v, want override host", hit, err)
+	}
+}
@@ -26,6 +26,7 @@ const (
 	apiGetApiDomainIp = "/cgi-bin/get_api_domain_ip"
 	apiCallbackCheck  = "/cgi-bin/callback/check"
 	apiClearQuota     = "/cgi-bin/clear_quota"
+	apiGetQuota       = "/cgi-bin/openapi/quota/get"
 )
 
 /*
@@ -79,3 +80,16 @@ POST https://api.weixin.qq.com/cgi-bin/clear_quota?access_token=ACCESS_TOKEN
 func ClearQuota(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error) {
 	return ctx.Client.HTTPPost(apiClearQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
 }
+
+/*
+查询 openAPI 调用quota
+
+本接口用于查询公众号/小程序/第三方平台等接口的每日调用接口的额度以及调用次数
+
+See: https://developers.weixin.qq.com/doc/offiaccount/openApi/get_api_quota.html
+
+POST https://api.weixin.qq.com/cgi-bin/openapi/quota/get?access_token=ACCESS_TOKEN
+*/
+func GetQuota(ctx *offiaccount.OffiAccount, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiGetQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
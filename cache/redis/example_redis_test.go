@@ -0,0 +1,34 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis_test
+
+import (
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/cache"
+	"github.com/fastwego/offiaccount/cache/redis"
+	redigo "github.com/garyburd/redigo/redis"
+)
+
+func ExampleNew() {
+	var app *offiaccount.OffiAccount
+
+	pool := redis.NewPool("127.0.0.1:6379", redigo.DialPassword("PASSWORD"))
+
+	// 多实例 共享 access_token
+	app.SetAccessTokenCacheDriver(redis.New(pool))
+
+	// Redis 短暂不可用时 使用 本地内存中 最近一次的值
+	app.SetAccessTokenCacheDriver(cache.NewTieredCache(redis.New(pool), nil))
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package redis 基于 Redis 的 access_token 缓存驱动，多实例部署时 共享 access_token
+
+实现 offiaccount.Cache（即 cachego.Cache）以及 offiaccount.TTLFetcher
+
+	pool := redis.NewPool("127.0.0.1:6379", redigo.DialPassword("PASSWORD"))
+	app.SetAccessTokenCacheDriver(redis.New(pool))
+*/
+package redis
+
+import (
+	"strconv"
+	"time"
+
+	redigo "github.com/garyburd/redigo/redis"
+)
+
+/*
+Cache Redis 缓存
+
+key 不存在 时 Fetch 返回 空字符串 和 nil（不视为错误）；Redis 暂时不可用时 Fetch 返回错误，
+GetAccessToken 会视为 缓存未命中 直接从微信服务器刷新，保存失败 不影响本次调用
+*/
+type Cache struct {
+	Pool *redigo.Pool
+}
+
+// New 创建 Redis 缓存
+func New(pool *redigo.Pool) *Cache {
+	return &Cache{Pool: pool}
+}
+
+// NewPool 创建 连接 address 的 连接池
+func NewPool(address string, options ...redigo.DialOption) *redigo.Pool {
+	return &redigo.Pool{
+		MaxIdle:     8,
+		IdleTimeout: 5 * time.Minute,
+		Dial: func() (redigo.Conn, error) {
+			return redigo.Dial("tcp", address, options...)
+		},
+	}
+}
+
+func (c *Cache) do(command string, args ...interface{}) (reply interface{}, err error) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return conn.Do(command, args...)
+}
+
+// Contains key 是否存在
+func (c *Cache) Contains(key string) bool {
+	exists, err := redigo.Bool(c.do("EXISTS", key))
+	return err == nil && exists
+}
+
+// Delete 删除 key
+func (c *Cache) Delete(key string) error {
+	_, err := c.do("DEL", key)
+	return err
+}
+
+// Fetch 读取 key，不存在时 返回 空字符串
+func (c *Cache) Fetch(key string) (string, error) {
+	value, err := redigo.String(c.do("GET", key))
+	if err == redigo.ErrNil {
+		return "", nil
+	}
+	return value, err
+}
+
+// FetchMulti 读取多个 key，结果中 不包含 不存在的 key
+func (c *Cache) FetchMulti(keys []string) map[string]string {
+	result := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return result
+	}
+
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+	values, err := redigo.Values(c.do("MGET", args...))
+	if err != nil {
+		return result
+	}
+	for i, value := range values {
+		if value == nil || i >= len(keys) {
+			continue
+		}
+		if s, err := redigo.String(value, nil); err == nil {
+			result[keys[i]] = s
+		}
+	}
+	return result
+}
+
+// Flush 清空 当前数据库（FLUSHDB），与其他业务共用 Redis 时 请谨慎调用
+func (c *Cache) Flush() error {
+	_, err := c.do("FLUSHDB")
+	return err
+}
+
+// Save 保存 key，lifeTime > 0 时 按毫秒设置过期时间，否则 永不过期
+func (c *Cache) Save(key string, value string, lifeTime time.Duration) error {
+	var err error
+	if ms := lifeTime.Milliseconds(); ms > 0 {
+		_, err = c.do("SET", key, value, "PX", strconv.FormatInt(ms, 10))
+	} else {
+		_, err = c.do("SET", key, value)
+	}
+	return err
+}
+
+// FetchWithTTL 读取 key 及 剩余有效期，永不过期时 ttl 为 0
+func (c *Cache) FetchWithTTL(key string) (value string, ttl time.Duration, err error) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	_ = conn.Send("MULTI")
+	_ = conn.Send("GET", key)
+	_ = conn.Send("PTTL", key)
+	replies, err := redigo.Values(conn.Do("EXEC"))
+	if err != nil {
+		return
+	}
+	if len(replies) != 2 || replies[0] == nil {
+		return "", 0, nil
+	}
+
+	if value, err = redigo.String(replies[0], nil); err != nil {
+		return
+	}
+	ms, err := redigo.Int64(replies[1], nil)
+	if err != nil {
+		return
+	}
+	if ms > 0 {
+		ttl = time.Duration(ms) * time.Millisecond
+	}
+	return
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/fastwego/offiaccount"
+)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	return New(NewPool(mr.Addr())), mr
+}
+
+func TestCache(t *testing.T) {
+	c, mr := newTestCache(t)
+
+	// 未命中 不返回错误
+	if value, err := c.Fetch("missing"); value != "" || err != nil {
+		t.Errorf("Fetch() = %q, %v, want empty without error", value, err)
+	}
+	if c.Contains("missing") {
+		t.Error("Contains() = true for missing key")
+	}
+
+	if err := c.Save("APPID", "ACCESS_TOKEN", 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save("forever", "VALUE", 0); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := c.Fetch("APPID"); value != "ACCESS_TOKEN" || err != nil {
+		t.Errorf("Fetch() = %q, %v", value, err)
+	}
+	if !c.Contains("APPID") {
+		t.Error("Contains() = false")
+	}
+
+	value, ttl, err := c.FetchWithTTL("APPID")
+	if err != nil || value != "ACCESS_TOKEN" || ttl <= 0 || ttl > 2*time.Second {
+		t.Errorf("FetchWithTTL() = %q, %s, %v", value, ttl, err)
+	}
+	if value, ttl, err = c.FetchWithTTL("forever"); err != nil || value != "VALUE" || ttl != 0 {
+		t.Errorf("FetchWithTTL() = %q, %s, %v, want no ttl", value, ttl, err)
+	}
+	if value, ttl, err = c.FetchWithTTL("missing"); err != nil || value != "" || ttl != 0 {
+		t.Errorf("FetchWithTTL() = %q, %s, %v, want empty", value, ttl, err)
+	}
+
+	multi := c.FetchMulti([]string{"APPID", "missing", "forever"})
+	if len(multi) != 2 || multi["APPID"] != "ACCESS_TOKEN" || multi["forever"] != "VALUE" {
+		t.Errorf("FetchMulti() = %v", multi)
+	}
+
+	// 按 Save 的有效期 过期
+	mr.FastForward(3 * time.Second)
+	if value, err := c.Fetch("APPID"); value != "" || err != nil {
+		t.Errorf("Fetch() after expiry = %q, %v", value, err)
+	}
+
+	if err := c.Delete("forever"); err != nil || c.Contains("forever") {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	_ = c.Save("a", "1", 0)
+	if err := c.Flush(); err != nil || c.Contains("a") {
+		t.Errorf("Flush() error = %v", err)
+	}
+}
+
+func TestCache_AccessToken(t *testing.T) {
+	var refreshes int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	c, mr := newTestCache(t)
+	newApp := func() *offiaccount.OffiAccount {
+		app := offiaccount.New(offiaccount.Config{Appid: "APPID", Secret: "SECRET", BaseURL: svr.URL})
+		app.SetAccessTokenCacheDriver(c)
+		app.SetLogger(nil)
+		return app
+	}
+
+	// 多个实例 共享 access_token
+	for i := 0; i < 2; i++ {
+		token, err := offiaccount.GetAccessToken(newApp())
+		if err != nil || token != "ACCESS_TOKEN" {
+			t.Fatalf("GetAccessToken() = %q, %v", token, err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes)
+	}
+	if ttl, err := newApp().AccessToken.RemainingTTL("APPID"); err != nil || ttl <= 0 {
+		t.Errorf("RemainingTTL() = %s, %v", ttl, err)
+	}
+
+	// Redis 暂时不可用 直接刷新
+	mr.Close()
+	token, err := offiaccount.GetAccessToken(newApp())
+	if err != nil || token != "ACCESS_TOKEN" || refreshes != 2 {
+		t.Errorf("GetAccessToken() = %q, %v, refreshes = %d", token, err, refreshes)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache 缓存驱动 扩展，均实现 cachego.Cache，可用于 SetAccessTokenCacheDriver
+package cache
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	cachesync "github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+TieredCache 两级缓存：主缓存（如 Redis） + 本地内存缓存
+
+写入时 同时写入两级缓存；主缓存 不可用（如 连接失败，见 IsUnavailable）时 使用本地缓存中 最近一次的值，
+避免 主缓存短暂故障 导致 所有接口调用失败 或 反复刷新 access_token
+
+主缓存 可用 但未命中（cachego.ErrCacheExpired、redis.Nil 等 key 不存在的错误，或 返回空值）时 视为 未命中，并删除 本地副本：
+多实例部署时 其他实例 NoticeAccessTokenExpire 删除 key 后，不会继续使用 本地缓存中 已失效的 access_token
+
+注意：Contains、FetchMulti 无法区分 未命中 与 不可用，主缓存 没有的 key 仍会读取 本地缓存
+
+主缓存 实现 offiaccount.TTLFetcher 时，读取成功的值 会按剩余有效期 同步到本地缓存
+
+	app.SetAccessTokenCacheDriver(cache.NewTieredCache(redisCache, nil))
+*/
+type TieredCache struct {
+	Primary cachego.Cache
+	Local   cachego.Cache
+	Logger  *log.Logger // 记录 主缓存 错误 默认为 nil 不记录
+
+	// IsUnavailable 判断 主缓存 读取错误 是否为 不可用（返回 true 时 使用本地缓存），默认为 nil 使用 IsUnavailable
+	IsUnavailable func(err error) bool
+}
+
+/*
+IsUnavailable 默认的 主缓存 不可用 判断：网络错误（net.Error，如 连接失败、超时）、连接被关闭（io.EOF）
+
+其他错误（如 key 不存在）视为 未命中
+*/
+func IsUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// NewTieredCache 创建 两级缓存 local 为 nil 时 使用 内存缓存
+func NewTieredCache(primary cachego.Cache, local cachego.Cache) *TieredCache {
+	if local == nil {
+		local = cachesync.New()
+	}
+	return &TieredCache{Primary: primary, Local: local}
+}
+
+// Contains 主缓存 或 本地缓存 存在 key
+func (c *TieredCache) Contains(key string) bool {
+	return c.Primary.Contains(key) || c.Local.Contains(key)
+}
+
+// Delete 从两级缓存中 删除 key，仅 本地缓存 删除失败时 返回错误
+func (c *TieredCache) Delete(key string) error {
+	c.primaryError("Delete", key, c.Primary.Delete(key))
+	return c.Local.Delete(key)
+}
+
+// Fetch 优先读取 主缓存，主缓存 出错时 读取本地缓存
+func (c *TieredCache) Fetch(key string) (string, error) {
+	if fetcher, ok := c.Primary.(offiaccount.TTLFetcher); ok {
+		value, ttl, err := fetcher.FetchWithTTL(key)
+		if err == nil {
+			if value == "" {
+				_ = c.Local.Delete(key)
+			} else if ttl > 0 {
+				_ = c.Local.Save(key, value, ttl)
+			}
+			return value, nil
+		}
+		return c.fallback(key, err)
+	}
+
+	value, err := c.Primary.Fetch(key)
+	if err == nil {
+		if value == "" {
+			_ = c.Local.Delete(key)
+		}
+		return value, nil
+	}
+	return c.fallback(key, err)
+}
+
+// fallback 主缓存 读取出错 时：不可用 读取本地缓存，否则 视为 未命中
+func (c *TieredCache) fallback(key string, primaryErr error) (string, error) {
+	isUnavailable := c.IsUnavailable
+	if isUnavailable == nil {
+		isUnavailable = IsUnavailable
+	}
+	if !isUnavailable(primaryErr) {
+		_ = c.Local.Delete(key)
+		return "", primaryErr
+	}
+
+	value, err := c.Local.Fetch(key)
+	if err != nil || value == "" {
+		return "", primaryErr
+	}
+	c.primaryError("Fetch", key, primaryErr)
+	return value, nil
+}
+
+// FetchMulti 主缓存 未返回的 key 从本地缓存读取
+func (c *TieredCache) FetchMulti(keys []string) map[string]string {
+	values := c.Primary.FetchMulti(keys)
+	if values == nil {
+		values = map[string]string{}
+	}
+
+	var missing []string
+	for _, key := range keys {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		for key, value := range c.Local.FetchMulti(missing) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// Flush 清空 两级缓存，仅 本地缓存 清空失败时 返回错误
+func (c *TieredCache) Flush() error {
+	c.primaryError("Flush", "", c.Primary.Flush())
+	return c.Local.Flush()
+}
+
+// Save 写入 两级缓存，仅 本地缓存 写入失败时 返回错误
+func (c *TieredCache) Save(key string, value string, lifeTime time.Duration) error {
+	c.primaryError("Save", key, c.Primary.Save(key, value, lifeTime))
+	return c.Local.Save(key, value, lifeTime)
+}
+
+func (c *TieredCache) primaryError(op string, key string, err error) {
+	if err != nil && c.Logger != nil {
+		c.Logger.Printf("TieredCache: primary %s %q: %s, using local cache", op, key, err)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	cachesync "github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/offiaccount"
+)
+
+var errDown = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+
+// flakyCache 模拟 可能不可用的 主缓存
+type flakyCache struct {
+	cachego.Cache
+	down bool
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{Cache: cachesync.New()}
+}
+
+func (c *flakyCache) Contains(key string) bool {
+	return !c.down && c.Cache.Contains(key)
+}
+
+func (c *flakyCache) Delete(key string) error {
+	if c.down {
+		return errDown
+	}
+	return c.Cache.Delete(key)
+}
+
+func (c *flakyCache) Fetch(key string) (string, error) {
+	if c.down {
+		return "", errDown
+	}
+	return c.Cache.Fetch(key)
+}
+
+func (c *flakyCache) FetchMulti(keys []string) map[string]string {
+	if c.down {
+		return map[string]string{}
+	}
+	return c.Cache.FetchMulti(keys)
+}
+
+func (c *flakyCache) Save(key string, value string, lifeTime time.Duration) error {
+	if c.down {
+		return errDown
+	}
+	return c.Cache.Save(key, value, lifeTime)
+}
+
+func TestTieredCache(t *testing.T) {
+	primary := newFlakyCache()
+	c := NewTieredCache(primary, nil)
+
+	if err := c.Save("key", "v1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// 主缓存 故障 使用本地缓存
+	primary.down = true
+	if value, err := c.Fetch("key"); err != nil || value != "v1" {
+		t.Errorf("Fetch() = %q, %v, want v1 from local", value, err)
+	}
+	if !c.Contains("key") {
+		t.Error("Contains() = false, want true from local")
+	}
+	if values := c.FetchMulti([]string{"key"}); values["key"] != "v1" {
+		t.Errorf("FetchMulti() = %v, want v1 from local", values)
+	}
+
+	// 故障期间 写入 本地缓存
+	if err := c.Save("key", "v2", time.Minute); err != nil {
+		t.Errorf("Save() error = %v, want nil while primary down", err)
+	}
+	if value, _ := c.Fetch("key"); value != "v2" {
+		t.Errorf("Fetch() = %q, want v2", value)
+	}
+
+	// 本地缓存 也没有时 返回 主缓存的错误
+	if _, err := c.Fetch("missing"); err != errDown {
+		t.Errorf("Fetch() error = %v, want %v", err, errDown)
+	}
+
+	// 主缓存 恢复 优先使用
+	primary.down = false
+	if value, _ := c.Fetch("key"); value != "v1" {
+		t.Errorf("Fetch() = %q, want v1 from primary", value)
+	}
+
+	// 删除 两级缓存
+	_ = c.Delete("key")
+	primary.down = true
+	if _, err := c.Fetch("key"); err == nil {
+		t.Error("Fetch() want error after Delete")
+	}
+}
+
+func TestTieredCache_Expired(t *testing.T) {
+	primary := cachesync.New()
+	c := NewTieredCache(primary, nil)
+
+	_ = c.Local.Save("key", "stale", time.Minute)
+	_ = primary.Save("key", "v1", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	// 主缓存 明确过期 不使用 本地缓存
+	if value, err := c.Fetch("key"); !errors.Is(err, cachego.ErrCacheExpired) || value != "" {
+		t.Errorf("Fetch() = %q, %v, want ErrCacheExpired", value, err)
+	}
+}
+
+func TestTieredCache_DeletedByOtherInstance(t *testing.T) {
+	primary := cachesync.New()
+	a := NewTieredCache(primary, nil)
+	b := NewTieredCache(primary, nil)
+
+	if err := a.Save("key", "revoked", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他实例 删除 key（如 NoticeAccessTokenExpire），主缓存 返回 key 不存在 的错误
+	if err := b.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := a.Fetch("key"); err == nil || value != "" {
+		t.Errorf("Fetch() = %q, %v, want miss", value, err)
+	}
+	if a.Local.Contains("key") {
+		t.Error("local copy should be deleted on primary miss")
+	}
+}
+
+func TestTieredCache_IsUnavailable(t *testing.T) {
+	primary := newFlakyCache()
+	c := NewTieredCache(primary, nil)
+	c.IsUnavailable = func(err error) bool { return false }
+
+	_ = c.Save("key", "v1", time.Minute)
+	primary.down = true
+	if value, err := c.Fetch("key"); err != errDown || value != "" {
+		t.Errorf("Fetch() = %q, %v, want %v", value, err, errDown)
+	}
+
+	if IsUnavailable(errors.New("key not found")) || !IsUnavailable(errDown) {
+		t.Error("IsUnavailable() should only match network errors")
+	}
+}
+
+// ttlCache 实现 offiaccount.TTLFetcher 的 主缓存
+type ttlCache struct {
+	*flakyCache
+	ttl time.Duration
+}
+
+func (c *ttlCache) FetchWithTTL(key string) (string, time.Duration, error) {
+	value, err := c.Fetch(key)
+	return value, c.ttl, err
+}
+
+func TestTieredCache_TTLFetcher(t *testing.T) {
+	primary := &ttlCache{flakyCache: newFlakyCache(), ttl: time.Minute}
+	c := NewTieredCache(primary, nil)
+
+	// 其他实例 写入 主缓存
+	_ = primary.Cache.Save("key", "v1", time.Minute)
+	if value, _ := c.Fetch("key"); value != "v1" {
+		t.Fatalf("Fetch() = %q, want v1", value)
+	}
+
+	// 读取成功的值 已同步到本地缓存
+	primary.down = true
+	if value, err := c.Fetch("key"); err != nil || value != "v1" {
+		t.Errorf("Fetch() = %q, %v, want v1 from local", value, err)
+	}
+}
+
+func TestTieredCache_AccessToken(t *testing.T) {
+	var tokens int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	primary := newFlakyCache()
+	app := offiaccount.New(offiaccount.Config{Appid: "TestTieredCache_AccessToken", Secret: "SECRET", BaseURL: svr.URL})
+	app.SetAccessTokenCacheDriver(NewTieredCache(primary, nil))
+	app.SetLogger(nil)
+
+	if accessToken, err := offiaccount.GetAccessToken(app); err != nil || accessToken != "ACCESS_TOKEN_1" {
+		t.Fatalf("GetAccessToken() = %s, %v", accessToken, err)
+	}
+
+	// 主缓存 故障 不需要 重新获取 access_token
+	primary.down = true
+	if accessToken, err := offiaccount.GetAccessToken(app); err != nil || accessToken != "ACCESS_TOKEN_1" {
+		t.Errorf("GetAccessToken() = %s, %v, want ACCESS_TOKEN_1 from local", accessToken, err)
+	}
+	if tokens != 1 {
+		t.Errorf("token refreshed %d times, want 1", tokens)
+	}
+}
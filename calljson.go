@@ -0,0 +1,88 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+/*
+CallJSON 调用接口 并将响应 解析为 T 类型
+
+GET 请求：req 为 url.Values 时 作为查询参数，为 nil 时 不附加参数
+
+POST 请求：req 为 []byte 时 原样发送，否则 序列化为 json 发送
+
+与 HTTPGet/HTTPPost 一样 自动附加 access_token，errcode 不为 0 时 返回 *WXError
+
+	type Tags struct {
+		Tags []struct {
+			Id   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	tags, err := offiaccount.CallJSON[Tags](ctx, http.MethodGet, "/cgi-bin/tags/get", nil)
+*/
+func CallJSON[T any](ctx *OffiAccount, method string, uri string, req any) (result T, err error) {
+	var resp []byte
+	switch method {
+	case http.MethodGet:
+		switch params := req.(type) {
+		case nil:
+		case url.Values:
+			uri = AppendQuery(uri, params)
+		default:
+			return result, fmt.Errorf("CallJSON GET %s: unsupported req type %T", uri, req)
+		}
+		resp, err = ctx.Client.HTTPGet(uri)
+	case http.MethodPost:
+		var payload []byte
+		payload, err = marshalPayload(req)
+		if err != nil {
+			return
+		}
+		resp, err = ctx.Client.HTTPPost(uri, bytes.NewReader(payload), "application/json;charset=utf-8")
+	default:
+		return result, fmt.Errorf("CallJSON %s %s: unsupported method", method, uri)
+	}
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &result)
+	return
+}
+
+// marshalPayload 序列化 POST 请求体 不转义 html 字符（微信接口的 url 等参数 需保持原样）
+func marshalPayload(req any) (payload []byte, err error) {
+	switch body := req.(type) {
+	case nil:
+		return []byte("{}"), nil
+	case []byte:
+		return body, nil
+	}
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err = encoder.Encode(req); err != nil {
+		return
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCallJSON(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid: "TestCallJSON",
+	}, WithStaticToken("ACCESS_TOKEN"))
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	MockSvrHandler.HandleFunc("/cgi-bin/user/info", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("openid") != "OPENID" || r.URL.Query().Get("access_token") != "ACCESS_TOKEN" {
+			_, _ = w.Write([]byte(`{"errcode":40003,"errmsg":"invalid openid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"subscribe":1,"openid":"OPENID","nickname":"Band"}`))
+	})
+	MockSvrHandler.HandleFunc("/cgi-bin/tags/create", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"tag":{"name":"广东&深圳"}}` {
+			t.Errorf("payload = %s", body)
+		}
+		_, _ = w.Write([]byte(`{"tag":{"id":134,"name":"广东&深圳"}}`))
+	})
+
+	type UserInfo struct {
+		Openid   string `json:"openid"`
+		Nickname string `json:"nickname"`
+	}
+	params := url.Values{}
+	params.Add("openid", "OPENID")
+	user, err := CallJSON[UserInfo](MockOffiAccount, http.MethodGet, "/cgi-bin/user/info", params)
+	if err != nil || user.Nickname != "Band" {
+		t.Errorf("CallJSON() = %+v, %v", user, err)
+	}
+
+	params.Set("openid", "INVALID")
+	_, err = CallJSON[UserInfo](MockOffiAccount, http.MethodGet, "/cgi-bin/user/info", params)
+	var wxErr *WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 40003 {
+		t.Errorf("CallJSON() error = %v, want *WXError 40003", err)
+	}
+
+	type Tag struct {
+		Id   int    `json:"id,omitempty"`
+		Name string `json:"name"`
+	}
+	type TagPayload struct {
+		Tag Tag `json:"tag"`
+	}
+	tag, err := CallJSON[TagPayload](MockOffiAccount, http.MethodPost, "/cgi-bin/tags/create", TagPayload{Tag: Tag{Name: "广东&深圳"}})
+	if err != nil || tag.Tag.Id != 134 {
+		t.Errorf("CallJSON() = %+v, %v", tag, err)
+	}
+
+	if _, err = CallJSON[Tag](MockOffiAccount, http.MethodPut, "/cgi-bin/tags/create", nil); err == nil {
+		t.Errorf("CallJSON() want error for unsupported method")
+	}
+}
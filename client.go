@@ -15,22 +15,29 @@
 package offiaccount
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 var (
 	WXServerUrl            = "https://api.weixin.qq.com" // 微信 api 服务器地址
 	UserAgent              = "fastwego/offiaccount"
 	ErrorAccessTokenExpire = errors.New("access token expire")
+	ErrorNotInitialized    = errors.New("OffiAccount not initialized; use New()")
 )
 
 /*
@@ -38,6 +45,70 @@ HttpClient 用于向公众号接口发送请求
 */
 type Client struct {
 	Ctx *OffiAccount
+
+	httpClient *http.Client
+	stdctx     context.Context
+
+	externalToken bool // DoWithToken 使用外部 access_token 过期时 不刷新重试
+}
+
+/*
+WithContext 返回 使用 stdctx 发送请求 的 Client 副本
+
+stdctx 取消时 请求随之终止；配合 WithRequestId 可以通过 ContextWithRequestId 传递 请求ID
+
+	resp, err := app.Client.WithContext(r.Context()).HTTPGet("/cgi-bin/getcallbackip")
+*/
+func (client *Client) WithContext(stdctx context.Context) *Client {
+	scoped := *client
+	scoped.stdctx = stdctx
+	return &scoped
+}
+
+// context 发送请求使用的 context.Context 默认 context.Background()
+func (client *Client) context() context.Context {
+	if client.stdctx == nil {
+		return context.Background()
+	}
+	return client.stdctx
+}
+
+/*
+根据配置 创建 http.Client
+
+设置了 HTTPClient 时 直接使用；未设置 Timeout/DialTimeout 时 直接使用 http.DefaultClient
+*/
+func newHTTPClient(config Config) *http.Client {
+	if config.HTTPClient != nil {
+		return config.HTTPClient
+	}
+	if config.Timeout == 0 && config.DialTimeout == 0 {
+		return http.DefaultClient
+	}
+
+	httpClient := &http.Client{Timeout: config.Timeout}
+	if config.DialTimeout > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = (&net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+		httpClient.Transport = transport
+	}
+	return httpClient
+}
+
+// getHTTPClient 获取发送请求的 http.Client
+func (client *Client) getHTTPClient() *http.Client {
+	if client.httpClient == nil {
+		return http.DefaultClient
+	}
+	return client.httpClient
+}
+
+// HTTPClient 发送请求使用的 http.Client（见 Config.HTTPClient），供 不需要 access_token 的接口 复用
+func (client *Client) HTTPClient() *http.Client {
+	return client.getHTTPClient()
 }
 
 // HTTPGet GET 请求
@@ -47,7 +118,7 @@ func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodGet, WXServerUrl+newUrl, nil)
+	req, err := http.NewRequestWithContext(client.context(), http.MethodGet, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), nil)
 	if err != nil {
 		return
 	}
@@ -55,6 +126,15 @@ func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
 	return client.httpDo(req)
 }
 
+/*
+HTTPGetWithParams GET 请求 params 编码后作为查询参数 附加到 uri 上（uri 可已带有查询参数）
+
+	resp, err := app.Client.HTTPGetWithParams("/cgi-bin/ticket/getticket", url.Values{"type": []string{"jsapi"}})
+*/
+func (client *Client) HTTPGetWithParams(uri string, params url.Values) (resp []byte, err error) {
+	return client.HTTPGet(AppendQuery(uri, params))
+}
+
 //HTTPPost POST 请求
 func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string) (resp []byte, err error) {
 	newUrl, err := client.applyAccessToken(uri)
@@ -62,7 +142,7 @@ func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodPost, WXServerUrl+newUrl, payload)
+	req, err := http.NewRequestWithContext(client.context(), http.MethodPost, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), payload)
 	if err != nil {
 		return
 	}
@@ -72,117 +152,409 @@ func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string
 	return client.httpDo(req)
 }
 
-//httpDo 执行 请求
-func (client *Client) httpDo(req *http.Request) (resp []byte, err error) {
-	req.Header.Add("User-Agent", UserAgent)
+/*
+HTTPPostWithParams POST 请求 params 编码后作为查询参数 附加到 uri 上（uri 可已带有查询参数）
 
-	if client.Ctx.Logger != nil {
-		client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, req.URL.String(), req.Header)
+	resp, err := app.Client.HTTPPostWithParams("/cgi-bin/draft/switch", url.Values{"checkonly": []string{"1"}}, nil, "application/json;charset=utf-8")
+*/
+func (client *Client) HTTPPostWithParams(uri string, params url.Values, payload io.Reader, contentType string) (resp []byte, err error) {
+	return client.HTTPPost(AppendQuery(uri, params), payload, contentType)
+}
+
+/*
+DoWithHeaders 发送请求 并附加 请求头 headers，仅对本次调用生效
+
+与 HTTPGet/HTTPPost 一样 自动附加 access_token、检查 errcode、按 Config.RetryClassifier 重试
+
+	resp, err := app.Client.DoWithHeaders(http.MethodGet, "/cgi-bin/getcallbackip", nil, "", http.Header{"X-Debug": []string{"1"}})
+*/
+func (client *Client) DoWithHeaders(method string, uri string, body io.Reader, contentType string, headers http.Header) (resp []byte, err error) {
+	// 读出 body 以便重试时重新发送
+	var payload io.Reader
+	if body != nil {
+		var data []byte
+		data, err = ioutil.ReadAll(body)
+		if err != nil {
+			return
+		}
+		payload = bytes.NewReader(data)
 	}
 
-	response, err := http.DefaultClient.Do(req)
+	newUrl, err := client.applyAccessToken(uri)
 	if err != nil {
 		return
 	}
-	defer response.Body.Close()
 
-	resp, err = responseFilter(response)
+	req, err := http.NewRequestWithContext(client.context(), method, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), payload)
+	if err != nil {
+		return
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
-	// 发现 access_token 过期
-	if err == ErrorAccessTokenExpire {
+	return client.httpDo(req)
+}
+
+/*
+DoWithToken 使用指定的 access_token 发送请求，仅对本次调用生效，不读取、不修改 access_token 缓存
+
+与 HTTPGet/HTTPPost 一样 检查 errcode、按 Config.RetryClassifier 重试；token 由外部管理，过期时 不会刷新重试，直接返回 ErrorAccessTokenExpire
+
+	resp, err := app.Client.DoWithToken(http.MethodGet, "/cgi-bin/getcallbackip", "ACCESS_TOKEN", nil, "")
+*/
+func (client *Client) DoWithToken(method string, uri string, token string, body io.Reader, contentType string) (resp []byte, err error) {
+	if client.Ctx == nil {
+		err = ErrorNotInitialized
+		return
+	}
 
-		// 主动 通知 access_token 过期
-		err = client.Ctx.AccessToken.NoticeAccessTokenExpireHandler(client.Ctx)
+	// 读出 body 以便重试时重新发送
+	var payload io.Reader
+	if body != nil {
+		var data []byte
+		data, err = ioutil.ReadAll(body)
 		if err != nil {
 			return
 		}
+		payload = bytes.NewReader(data)
+	}
 
-		// 通知到位后 access_token 会被刷新，那么可以 retry 了
-		var accessToken string
-		accessToken, err = client.Ctx.AccessToken.GetAccessTokenHandler(client.Ctx)
-		if err != nil {
+	newUrl := appendAccessToken(uri, token)
+	req, err := http.NewRequestWithContext(client.context(), method, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), payload)
+	if err != nil {
+		return
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	scoped := *client
+	scoped.externalToken = true
+	return scoped.httpDo(req)
+}
+
+//httpDo 执行 请求 按 Config.RetryClassifier 判断是否重试
+func (client *Client) httpDo(req *http.Request) (resp []byte, err error) {
+	req.Header.Add("User-Agent", UserAgent)
+
+	classify := client.Ctx.retryClassifier()
+	var attempt, transient int // 按 RetryClassifier 重试的次数，按 Config.MaxRetries 重试的次数
+	for {
+		// 每次请求（包括 重试）都需要 限流器 放行
+		if err = client.Ctx.waitRateLimit(req.Context()); err != nil {
 			return
 		}
 
-		// 换新
-		q := req.URL.Query()
-		q.Set("access_token", accessToken)
-		req.URL.RawQuery = q.Encode()
-
 		if client.Ctx.Logger != nil {
-			client.Ctx.Logger.Printf("retry %s %s Headers %v", req.Method, req.URL.String(), req.Header)
+			if attempt+transient > 0 {
+				client.Ctx.Logger.Printf("retry %s %s Headers %v", req.Method, req.URL.String(), req.Header)
+			} else {
+				client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, req.URL.String(), req.Header)
+			}
 		}
 
-		response, err = http.DefaultClient.Do(req)
+		client.Ctx.dumpRequest(req)
+
+		// 不能沿用 上一次请求的 响应
+		resp = nil
+		var response *http.Response
+		var status int
+		response, err = client.getHTTPClient().Do(req)
+		if err == nil {
+			status = response.StatusCode
+			client.Ctx.observeServerTime(response)
+			resp, err = responseFilter(response)
+			response.Body.Close()
+			if err == nil {
+				err = client.Ctx.validateResponse(req.URL.Path, resp)
+			}
+		}
+		client.Ctx.dumpResponse(req, status, resp, err)
+
+		decision := classify(resp, status, err)
+		if decision.Retry {
+			maxRetries := decision.MaxRetries
+			if maxRetries <= 0 {
+				maxRetries = defaultMaxRetries
+			}
+			if attempt >= maxRetries {
+				return
+			}
+			attempt++
+		} else if transient < client.Ctx.Config.MaxRetries && isTransient(req, status, err) {
+			// 5xx 或 网络错误 退避后重试
+			decision = RetryDecision{Retry: true, Delay: client.Ctx.retryBackoff(transient)}
+			transient++
+		} else {
+			return
+		}
+
+		// body 无法重新读取 不能重试
+		if req.Body != nil && req.GetBody == nil {
+			return
+		}
+
+		if decision.RefreshToken {
+			// 外部 access_token 无法刷新
+			if client.externalToken {
+				return
+			}
+
+			// 主动 通知 access_token 过期
+			err = client.Ctx.AccessToken.NoticeAccessTokenExpireHandler(client.Ctx)
+			if err != nil {
+				return
+			}
+
+			// 通知到位后 access_token 会被刷新，那么可以 retry 了
+			var accessToken string
+			accessToken, err = client.Ctx.AccessToken.GetAccessTokenHandler(client.Ctx)
+			if err != nil {
+				return
+			}
+
+			// 换新
+			q := req.URL.Query()
+			q.Set("access_token", accessToken)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		// 重试沿用 首次请求的 请求ID (见 WithRequestId)
+		if requestId := sentRequestId(response); requestId != "" {
+			req.Header.Set(HeaderRequestId, requestId)
+		}
+
+		if decision.Delay > 0 {
+			timer := time.NewTimer(decision.Delay)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return resp, req.Context().Err()
+			case <-timer.C:
+			}
+		}
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+/*
+DoRaw 发送请求 并返回原始的 *http.Response，用于读取响应头 或 流式读取响应体
+
+与 HTTPGet/HTTPPost 一样 会自动附加 access_token，并在 access_token 过期时 刷新后重试一次（判断过期 只预读 响应体 前 512 字节，不会读完 整个响应）
+
+不检查 http 状态码 和 errcode，由调用方自行处理
+
+注意：调用方必须关闭 response.Body
+*/
+func (client *Client) DoRaw(method string, uri string, body io.Reader, contentType string) (response *http.Response, err error) {
+	// 读出 body 以便重试时重新发送
+	var payload []byte
+	if body != nil {
+		payload, err = ioutil.ReadAll(body)
 		if err != nil {
 			return
 		}
-		defer response.Body.Close()
+	}
+
+	newUrl, err := client.applyAccessToken(uri)
+	if err != nil {
+		return
+	}
+
+	response, err = client.doRaw(method, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), payload, contentType, nil)
+	if err != nil || !isAccessTokenExpired(response) {
+		return
+	}
+	response.Body.Close()
+
+	// 重试沿用 首次请求的 请求ID (见 WithRequestId)
+	var header http.Header
+	if requestId := sentRequestId(response); requestId != "" {
+		header = http.Header{HeaderRequestId: []string{requestId}}
+	}
+
+	// 发现 access_token 过期
+	err = client.Ctx.AccessToken.NoticeAccessTokenExpireHandler(client.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newUrl, err = client.applyAccessToken(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.doRaw(method, JoinUrl(client.Ctx.ServerUrlFor(newUrl), newUrl), payload, contentType, header)
+}
+
+// doRaw 执行 请求 不读取响应 header 为附加的请求头
+func (client *Client) doRaw(method string, url string, payload []byte, contentType string, header http.Header) (response *http.Response, err error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(client.context(), method, url, body)
+	if err != nil {
+		return
+	}
+	if err = client.Ctx.waitRateLimit(req.Context()); err != nil {
+		return
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
+	req.Header.Add("User-Agent", UserAgent)
 
-		resp, err = responseFilter(response)
+	if client.Ctx.Logger != nil {
+		client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, req.URL.String(), req.Header)
 	}
 
+	response, err = client.getHTTPClient().Do(req)
+	if err != nil {
+		return
+	}
+	client.Ctx.observeServerTime(response)
 	return
 }
 
+// expiredPeekSize 判断 access_token 过期时 预读的响应长度，错误响应 远小于该长度
+const expiredPeekSize = 512
+
+// peekedBody 预读后的响应体：读取 走 bufio.Reader，关闭 走 原始 Body
+type peekedBody struct {
+	*bufio.Reader
+	io.Closer
+}
+
+/*
+判断响应是否为 access_token 过期错误
+
+只检查 json/text 类型的响应，且只预读 前 expiredPeekSize 字节：响应体 超过该长度 不会是 错误响应；
+预读后 将 Body 替换为 包含预读内容的 bufio.Reader，其余部分 仍由调用方 流式读取
+*/
+func isAccessTokenExpired(response *http.Response) bool {
+	if response.StatusCode != http.StatusOK {
+		return false
+	}
+	contentType := response.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/plain") {
+		return false
+	}
+
+	reader := bufio.NewReaderSize(response.Body, expiredPeekSize)
+	response.Body = peekedBody{Reader: reader, Closer: response.Body}
+
+	// 读满 expiredPeekSize（err 为 nil）说明 响应体 更长，不是 错误响应；其他错误 交由调用方 读取时处理
+	prefix, err := reader.Peek(expiredPeekSize)
+	if err != io.EOF {
+		return false
+	}
+
+	errorResponse, err := ParseWXError(prefix)
+	if err != nil {
+		return false
+	}
+	return isAccessTokenExpiredErrcode(errorResponse.Errcode)
+}
+
+// isAccessTokenExpiredErrcode access_token 无效或过期 的 errcode（40001/40014/42001）
+func isAccessTokenExpiredErrcode(errcode int64) bool {
+	switch errcode {
+	case 40001, 40014, 42001:
+		return true
+	}
+	return false
+}
+
 /*
 在请求地址上附加上 access_token
 */
 func (client *Client) applyAccessToken(oldUrl string) (newUrl string, err error) {
+	// 手动构造 OffiAccount 时 Client 未关联实例 或 未设置 AccessToken 管理器
+	if client.Ctx == nil || client.Ctx.AccessToken.GetAccessTokenHandler == nil || client.Ctx.AccessToken.NoticeAccessTokenExpireHandler == nil {
+		err = ErrorNotInitialized
+		return
+	}
+
 	accessToken, err := client.Ctx.AccessToken.GetAccessTokenHandler(client.Ctx)
 	if err != nil {
 		return
 	}
-	if strings.Contains(oldUrl, "?") {
-		newUrl = oldUrl + "&access_token=" + accessToken
-	} else {
-		newUrl = oldUrl + "?access_token=" + accessToken
-	}
+	newUrl = appendAccessToken(oldUrl, accessToken)
 	return
 }
 
+// appendAccessToken 在请求地址上附加 access_token 参数
+func appendAccessToken(uri string, accessToken string) string {
+	return uri + querySeparator(uri) + "access_token=" + accessToken
+}
+
 /*
 筛查微信 api 服务器响应，判断以下错误：
 
 - http 状态码 不为 200
 
-- 接口响应错误码 errcode 不为 0
+- 接口响应错误码 errcode 不为 0，返回 *WXError
 */
 func responseFilter(response *http.Response) (resp []byte, err error) {
-	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Status %s", response.Status)
+	resp, err = ioutil.ReadAll(response.Body)
+	if err != nil {
 		return
 	}
 
-	resp, err = ioutil.ReadAll(response.Body)
-	if err != nil {
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Status %s", response.Status)
 		return
 	}
 
-	errorResponse := struct {
-		Errcode int64  `json:"errcode"`
-		Errmsg  string `json:"errmsg"`
-	}{}
-	err = json.Unmarshal(resp, &errorResponse)
+	errorResponse, err := ParseWXError(resp)
 	if err != nil {
 		return
 	}
 
 	// 40001(覆盖刷新超过5min后，使用旧 access_token 报错) 获取 access_token 时 AppSecret 错误，或者 access_token 无效。请开发者认真比对 AppSecret 的正确性，或查看是否正在为恰当的公众号调用接口
+	// 40014(不合法的 access_token) - 请开发者认真比对 access_token 的有效性（如是否过期），或查看是否正在为恰当的公众号调用接口
 	// 42001(超过 7200s 后 报错) - access_token 超时，请检查 access_token 的有效期，请参考基础支持 - 获取 access_token 中，对 access_token 的详细机制说明
-	if errorResponse.Errcode == 42001 || errorResponse.Errcode == 40001 {
+	if isAccessTokenExpiredErrcode(errorResponse.Errcode) {
 		err = ErrorAccessTokenExpire
 		return
 	}
 	if errorResponse.Errcode != 0 {
-		err = errors.New(string(resp))
+		errorResponse.Raw = string(resp)
+		err = &errorResponse
 		return
 	}
 	return
 }
 
-// 防止多个 goroutine 并发刷新冲突
-var refreshAccessTokenLock sync.Mutex
+// GetAccessToken 的并发刷新 按 appid 合并为一次请求，等待中的 goroutine 共享同一结果
+var refreshAccessTokenGroup singleflight.Group
+
+// 同一 appid 的 所有刷新（包括 主动刷新）互斥，避免 较慢的请求 用旧 token 覆盖缓存；不同 appid 互不阻塞
+var refreshAccessTokenLocks sync.Map
+
+// refreshAccessTokenLock appid 对应的 刷新锁
+func refreshAccessTokenLock(ctx *OffiAccount) *sync.Mutex {
+	lock, _ := refreshAccessTokenLocks.LoadOrStore(ctx.CacheKey(ctx.Config.Appid), &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
 
 /*
 从 公众号实例 的 AccessToken 管理器 获取 access_token
@@ -192,82 +564,229 @@ var refreshAccessTokenLock sync.Mutex
 获得新的 access_token 后 过期时间设置为 0.9 * expiresIn 提供一定冗余
 */
 func GetAccessToken(ctx *OffiAccount) (accessToken string, err error) {
-	accessToken, err = ctx.AccessToken.Cache.Fetch(ctx.Config.Appid)
+	accessToken, _, err = GetAccessTokenDetailed(ctx)
+	return
+}
+
+/*
+GetAccessTokenDetailed 同 GetAccessToken，fromCache 表示 access_token 是否来自缓存
+
+fromCache 为 false 时 本次调用 从微信服务器刷新了 access_token，可用于统计刷新次数（每日调用次数有限）
+*/
+func GetAccessTokenDetailed(ctx *OffiAccount) (accessToken string, fromCache bool, err error) {
+	accessToken, err = ctx.AccessToken.Cache.Fetch(ctx.CacheKey(ctx.Config.Appid))
 	if accessToken != "" {
+		return accessToken, true, nil
+	}
+
+	// 只有真正发起刷新的 goroutine 会执行 fn，其余等待者 共享其结果
+	refreshed := false
+	v, err, _ := refreshAccessTokenGroup.Do(ctx.CacheKey(ctx.Config.Appid), func() (interface{}, error) {
+		lock := refreshAccessTokenLock(ctx)
+		lock.Lock()
+		defer lock.Unlock()
+
+		// 等待锁期间 其他 goroutine 可能已经刷新
+		accessToken, _ := ctx.AccessToken.Cache.Fetch(ctx.CacheKey(ctx.Config.Appid))
+		if accessToken != "" {
+			return accessToken, nil
+		}
+
+		result, err := refreshAccessToken(ctx)
+		if err != nil {
+			return "", err
+		}
+		refreshed = true
+
+		// 本地缓存 access_token
+		_ = ctx.saveAccessToken(result.AccessToken, result.ExpiresIn)
+
+		if ctx.Logger != nil {
+			ctx.Logger.Printf("%s %s %d\n", "refreshAccessTokenFromWXServer", result.AccessToken, result.ExpiresIn)
+		}
+		return result.AccessToken, nil
+	})
+	if err != nil {
 		return
 	}
 
-	refreshAccessTokenLock.Lock()
-	defer refreshAccessTokenLock.Unlock()
+	return v.(string), !refreshed, nil
+}
+
+/*
+NoticeAccessTokenExpire 只需将本地存储的 access_token 删除，即完成了 access_token 已过期的 主动通知
 
-	accessToken, err = ctx.AccessToken.Cache.Fetch(ctx.Config.Appid)
-	if accessToken != "" {
+retry 请求的时候，会发现本地没有 access_token ，从而触发refresh
+*/
+func NoticeAccessTokenExpire(ctx *OffiAccount) (err error) {
+	if ctx.Logger != nil {
+		ctx.Logger.Println("NoticeAccessTokenExpire")
+	}
+
+	err = ctx.AccessToken.Cache.Delete(ctx.CacheKey(ctx.Config.Appid))
+	return
+}
+
+/*
+NoticeRefreshAccessTokenWithExpiry 立即从微信服务器刷新 access_token 并更新缓存，返回新 token 及其过期时间
+
+适用于中控服务器：收到业务服务器的过期通知后 刷新，并将 token 和 有效期 一起下发，业务服务器 可据此设置本地缓存时间
+
+expiresAt 为微信返回的实际过期时间（获取时间 + expires_in），本地缓存时间 仍为 0.9 * expires_in
+*/
+func NoticeRefreshAccessTokenWithExpiry(ctx *OffiAccount) (accessToken string, expiresAt time.Time, err error) {
+	result, err := NoticeRefreshAccessTokenWithResult(ctx)
+	if err != nil {
 		return
 	}
+	return result.AccessToken, result.ExpiresAt(), nil
+}
 
-	accessToken, expiresIn, err := refreshAccessTokenFromWXServer(ctx.Config.Appid, ctx.Config.Secret)
+/*
+NoticeRefreshAccessTokenWithResult 立即从微信服务器刷新 access_token 并更新缓存，返回完整的刷新结果
+
+刷新失败时 如果收到了微信服务器的响应，result.ServerTime 仍然有效，可用于排查 本地时钟 问题
+*/
+func NoticeRefreshAccessTokenWithResult(ctx *OffiAccount) (result TokenRefreshResult, err error) {
+	lock := refreshAccessTokenLock(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+
+	result, err = refreshAccessToken(ctx)
 	if err != nil {
 		return
 	}
 
-	// 本地缓存 access_token
-	d := time.Duration(expiresIn) * time.Second
-	_ = ctx.AccessToken.Cache.Save(ctx.Config.Appid, accessToken, d)
+	err = ctx.saveAccessToken(result.AccessToken, result.ExpiresIn)
+	if err != nil {
+		return
+	}
 
 	if ctx.Logger != nil {
-		ctx.Logger.Printf("%s %s %d\n", "refreshAccessTokenFromWXServer", accessToken, expiresIn)
+		ctx.Logger.Printf("%s %s %s skew %s\n", "NoticeRefreshAccessTokenWithResult", result.AccessToken, result.ExpiresAt(), result.Skew())
 	}
-
 	return
 }
 
 /*
-NoticeAccessTokenExpire 只需将本地存储的 access_token 删除，即完成了 access_token 已过期的 主动通知
+TokenRefreshResult 从微信服务器刷新 access_token 的结果
+*/
+type TokenRefreshResult struct {
+	AccessToken string
+	ExpiresIn   int
+	FetchedAt   time.Time // 发起请求时的 本地时间
+	ServerTime  time.Time // 响应 Date 头 表示的 微信服务器时间，没有 Date 头时为零值
+}
 
-retry 请求的时候，会发现本地没有 access_token ，从而触发refresh
+// ExpiresAt access_token 的实际过期时间（获取时间 + expires_in）
+func (result TokenRefreshResult) ExpiresAt() time.Time {
+	return result.FetchedAt.Add(time.Duration(result.ExpiresIn) * time.Second)
+}
+
+// Skew 微信服务器时间 与 本地时间 的偏差（服务器时间 - 本地时间），精度为 秒；没有服务器时间时 返回 0
+func (result TokenRefreshResult) Skew() time.Duration {
+	if result.ServerTime.IsZero() {
+		return 0
+	}
+	return result.ServerTime.Sub(result.FetchedAt).Truncate(time.Second)
+}
+
+// accessTokenTTL access_token 缓存时间 0.9 * expiresIn
+func accessTokenTTL(expiresIn int) time.Duration {
+	return time.Duration(expiresIn) * time.Second * 9 / 10
+}
+
+const defaultTokenRefreshAttempts = 3
+
+// 刷新 access_token 首次重试前的等待时间
+var tokenRefreshBackoff = 200 * time.Millisecond
+
+/*
+从微信服务器刷新 AccessToken 遇到临时错误时 按 Config.TokenRefreshAttempts 重试
+
+access_token 刷新失败 会导致所有接口调用失败，所以单独重试，避免网络抖动的影响
+
+每次收到响应 都会根据 Date 头 更新 ServerTimeSkew
 */
-func NoticeAccessTokenExpire(ctx *OffiAccount) (err error) {
-	if ctx.Logger != nil {
-		ctx.Logger.Println("NoticeAccessTokenExpire")
+func refreshAccessToken(ctx *OffiAccount) (result TokenRefreshResult, err error) {
+	attempts := ctx.Config.TokenRefreshAttempts
+	if attempts <= 0 {
+		attempts = defaultTokenRefreshAttempts
 	}
 
-	err = ctx.AccessToken.Cache.Delete(ctx.Config.Appid)
+	var retryable bool
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			backoff := tokenRefreshBackoff << uint(i-1)
+			if ctx.Logger != nil {
+				ctx.Logger.Printf("refreshAccessTokenFromWXServer retry %d after %s: %s (server time skew %s)", i, backoff, err, result.Skew())
+			}
+			time.Sleep(backoff)
+		}
+
+		result, retryable, err = ctx.refreshWithSecrets(func(secret string) (TokenRefreshResult, bool, error) {
+			if ctx.Config.UseStableToken {
+				return refreshStableTokenFromWXServer(ctx.Client.getHTTPClient(), ctx.ServerUrlFor(apiStableToken), ctx.Config.Appid, secret, false)
+			}
+			return refreshAccessTokenFromWXServer(ctx.Client.getHTTPClient(), ctx.ServerUrlFor(apiToken), ctx.Config.Appid, secret)
+		})
+		if !result.ServerTime.IsZero() {
+			ctx.setServerTimeSkew(result.Skew())
+		}
+		if err == nil || !retryable {
+			return
+		}
+	}
 	return
 }
 
+// apiToken 获取 access_token 接口
+const apiToken = "/cgi-bin/token"
+
 /*
 从微信服务器获取新的 AccessToken
 
+retryable 表示错误是否为临时错误（网络错误、http 状态码非 200、系统繁忙）
+
 See: https://developers.weixin.qq.com/doc/offiaccount/Basic_Information/Get_access_token.html
 */
-func refreshAccessTokenFromWXServer(appid string, secret string) (accessToken string, expiresIn int, err error) {
+func refreshAccessTokenFromWXServer(httpClient *http.Client, serverUrl string, appid string, secret string) (result TokenRefreshResult, retryable bool, err error) {
 	params := url.Values{}
 	params.Add("appid", appid)
 	params.Add("secret", secret)
 	params.Add("grant_type", "client_credential")
-	url := WXServerUrl + "/cgi-bin/token?" + params.Encode()
+	url := JoinUrl(serverUrl, AppendQuery(apiToken, params))
 
-	response, err := http.Get(url)
+	result.FetchedAt = time.Now()
+	response, err := httpClient.Get(url)
 	if err != nil {
+		retryable = true
 		return
 	}
 
 	defer response.Body.Close()
+	result.ServerTime = serverTimeOf(response)
 	if response.StatusCode != http.StatusOK {
 		err = fmt.Errorf("GET %s RETURN %s", url, response.Status)
+		retryable = true
 		return
 	}
 
+	result.AccessToken, result.ExpiresIn, retryable, err = parseAccessTokenResponse(response)
+	return
+}
+
+// parseAccessTokenResponse 解析 获取 access_token 接口 的响应
+func parseAccessTokenResponse(response *http.Response) (accessToken string, expiresIn int, retryable bool, err error) {
 	resp, err := ioutil.ReadAll(response.Body)
 	if err != nil {
+		retryable = true
 		return
 	}
 
 	var result = struct {
-		AccessToken string  `json:"access_token"`
-		ExpiresIn   int     `json:"expires_in"`
-		Errcode     float64 `json:"errcode"`
-		Errmsg      string  `json:"errmsg"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
 	}{}
 
 	err = json.Unmarshal(resp, &result)
@@ -278,8 +797,10 @@ func refreshAccessTokenFromWXServer(appid string, secret string) (accessToken st
 
 	if result.AccessToken == "" {
 		err = fmt.Errorf("%s", string(resp))
+		wxErr, _ := ParseWXError(resp)
+		retryable = wxErr.Errcode == -1 // 系统繁忙
 		return
 	}
 
-	return result.AccessToken, result.ExpiresIn, nil
+	return result.AccessToken, result.ExpiresIn, false, nil
 }
@@ -16,9 +16,17 @@ package offiaccount
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
 )
 
 func TestClient_getAccessToken(t *testing.T) {
@@ -71,3 +79,673 @@ func TestClient_getAccessToken(t *testing.T) {
 		})
 	}
 }
+
+func TestNewHTTPClient(t *testing.T) {
+	if newHTTPClient(Config{}) != http.DefaultClient {
+		t.Errorf("newHTTPClient() without timeout should use http.DefaultClient")
+	}
+
+	httpClient := newHTTPClient(Config{Timeout: time.Minute})
+	if httpClient.Timeout != time.Minute || httpClient.Transport != nil {
+		t.Errorf("newHTTPClient() Timeout = %v Transport = %v", httpClient.Timeout, httpClient.Transport)
+	}
+
+	httpClient = newHTTPClient(Config{Timeout: time.Minute, DialTimeout: 2 * time.Second})
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok || transport.DialContext == nil {
+		t.Fatalf("newHTTPClient() with DialTimeout should build a custom transport")
+	}
+	if transport == http.DefaultTransport {
+		t.Errorf("newHTTPClient() must not modify http.DefaultTransport")
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("newHTTPClient() Timeout = %v, want %v", httpClient.Timeout, time.Minute)
+	}
+}
+
+// recordingTransport 记录 经过的请求路径
+type recordingTransport struct {
+	paths []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.paths = append(rt.paths, req.URL.Path)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestConfig_HTTPClient(t *testing.T) {
+	// 默认 http.DefaultClient
+	app := New(Config{Appid: "TestConfig_HTTPClient"})
+	if app.Client.HTTPClient() != http.DefaultClient {
+		t.Errorf("HTTPClient() = %v, want http.DefaultClient", app.Client.HTTPClient())
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/getcallbackip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip_list":["127.0.0.1"]}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	transport := &recordingTransport{}
+	httpClient := &http.Client{Transport: transport}
+	app = New(Config{Appid: "TestConfig_HTTPClient", Secret: "SECRET", BaseURL: svr.URL, HTTPClient: httpClient, Timeout: time.Minute})
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	app.SetLogger(nil)
+	if app.Client.HTTPClient() != httpClient {
+		t.Fatalf("HTTPClient() = %v, want custom client", app.Client.HTTPClient())
+	}
+
+	if _, err := app.Client.HTTPGet("/cgi-bin/getcallbackip"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 刷新 access_token 同样使用 自定义 http.Client
+	if len(transport.paths) != 2 || transport.paths[0] != "/cgi-bin/token" || transport.paths[1] != "/cgi-bin/getcallbackip" {
+		t.Errorf("custom client requests = %v", transport.paths)
+	}
+}
+
+func TestWithStaticToken(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid:  "TestWithStaticToken",
+		Secret: "SECRET",
+	}, WithStaticToken("TEST_TOKEN"))
+
+	// Mock Server 不应收到 token 请求
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	MockSvrHandler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("token endpoint should not be called")
+	})
+	MockSvrHandler.HandleFunc("/cgi-bin/menu/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "TEST_TOKEN" {
+			t.Errorf("access_token = %s, want TEST_TOKEN", r.URL.Query().Get("access_token"))
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	_, err := MockOffiAccount.Client.HTTPGet("/cgi-bin/menu/get")
+	if err != nil {
+		t.Errorf("HTTPGet() error = %v", err)
+	}
+}
+
+func TestRefreshAccessTokenRetry(t *testing.T) {
+	tokenRefreshBackoff = time.Millisecond
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	var calls int
+	MockSvrHandler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Query().Get("appid") == "INVALID":
+			_, _ = w.Write([]byte(`{"errcode":40013,"errmsg":"invalid appid"}`))
+		case calls < 3:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+		}
+	})
+
+	tests := []struct {
+		name            string
+		config          Config
+		wantAccessToken string
+		wantCalls       int
+		wantErr         bool
+	}{
+		{name: "retry until success", config: Config{Appid: "APPID"}, wantAccessToken: "ACCESS_TOKEN", wantCalls: 3, wantErr: false},
+		{name: "attempts exhausted", config: Config{Appid: "APPID", TokenRefreshAttempts: 2}, wantAccessToken: "", wantCalls: 2, wantErr: true},
+		{name: "no retry on errcode", config: Config{Appid: "INVALID"}, wantAccessToken: "", wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			ctx := New(tt.config)
+			ctx.SetLogger(nil)
+			result, err := refreshAccessToken(ctx)
+			gotAccessToken := result.AccessToken
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotAccessToken != tt.wantAccessToken || calls != tt.wantCalls {
+				t.Errorf("refreshAccessToken() gotAccessToken = %v calls = %d, want %v and %d", gotAccessToken, calls, tt.wantAccessToken, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_DoRaw(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid:  "TestClient_DoRaw",
+		Secret: "SECRET",
+	})
+	MockOffiAccount.SetAccessTokenCacheDriver(cachesync.New())
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	var tokens int
+	MockSvrHandler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	})
+	MockSvrHandler.HandleFunc("/cgi-bin/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+		if r.URL.Query().Get("access_token") == "ACCESS_TOKEN_1" {
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Request-Id", "REQUEST_ID")
+		_, _ = w.Write(body)
+	})
+
+	response, err := MockOffiAccount.Client.DoRaw(http.MethodPost, "/cgi-bin/raw", strings.NewReader(`{"errcode":0}`), "application/json")
+	if err != nil {
+		t.Fatalf("DoRaw() error = %v", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if string(body) != `{"errcode":0}` {
+		t.Errorf("DoRaw() body = %s, want payload echoed after retry", body)
+	}
+	if response.Header.Get("Request-Id") != "REQUEST_ID" {
+		t.Errorf("DoRaw() Request-Id = %s, want REQUEST_ID", response.Header.Get("Request-Id"))
+	}
+	if tokens != 2 {
+		t.Errorf("DoRaw() token refreshed %d times, want 2", tokens)
+	}
+}
+
+func TestClient_DoRaw_Streaming(t *testing.T) {
+	release := make(chan struct{})
+	head := `{"user_info_list":[` + strings.Repeat(`{"openid":"OPENID"},`, 50)
+	tail := `{"openid":"LAST"}]}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cgi-bin/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(head))
+		w.(http.Flusher).Flush()
+		// 调用方 开始读取 之前 不写出 剩余部分
+		<-release
+		_, _ = w.Write([]byte(tail))
+	})
+	svr := httptest.NewServer(mux)
+	defer svr.Close()
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	app := New(Config{Appid: "TestClient_DoRaw_Streaming", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	done := make(chan *http.Response)
+	go func() {
+		response, err := app.Client.DoRaw(http.MethodPost, "/cgi-bin/stream", nil, "application/json")
+		if err != nil {
+			t.Error(err)
+		}
+		done <- response
+	}()
+
+	var response *http.Response
+	select {
+	case response = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("DoRaw() read the whole body before returning")
+	}
+	if response == nil {
+		return
+	}
+	defer response.Body.Close()
+
+	close(release)
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != head+tail {
+		t.Errorf("DoRaw() body = %s, want %s", body, head+tail)
+	}
+}
+
+func TestCacheKeyPrefix(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid:          "TestCacheKeyPrefix",
+		Secret:         "SECRET",
+		CacheKeyPrefix: "prod:",
+	})
+	cache := cachesync.New()
+	MockOffiAccount.SetAccessTokenCacheDriver(cache)
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	MockSvrHandler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	})
+
+	if _, err := GetAccessToken(MockOffiAccount); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Contains("TestCacheKeyPrefix") || !cache.Contains("prod:TestCacheKeyPrefix") {
+		t.Errorf("access_token should be cached under prod:TestCacheKeyPrefix")
+	}
+
+	if err := NoticeAccessTokenExpire(MockOffiAccount); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Contains("prod:TestCacheKeyPrefix") {
+		t.Errorf("NoticeAccessTokenExpire should delete prod:TestCacheKeyPrefix")
+	}
+}
+
+func TestClient_NotInitialized(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  *OffiAccount
+	}{
+		{name: "zero value", ctx: &OffiAccount{Config: Config{Appid: "APPID"}}},
+		{name: "no handler", ctx: &OffiAccount{Client: Client{Ctx: &OffiAccount{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.ctx.Client.HTTPGet("/cgi-bin/menu/get"); err != ErrorNotInitialized {
+				t.Errorf("HTTPGet() error = %v, want ErrorNotInitialized", err)
+			}
+			if _, err := tt.ctx.Client.HTTPPost("/cgi-bin/menu/create", strings.NewReader("{}"), "application/json"); err != ErrorNotInitialized {
+				t.Errorf("HTTPPost() error = %v, want ErrorNotInitialized", err)
+			}
+			if _, err := tt.ctx.Client.DoRaw(http.MethodGet, "/cgi-bin/menu/get", nil, ""); err != ErrorNotInitialized {
+				t.Errorf("DoRaw() error = %v, want ErrorNotInitialized", err)
+			}
+		})
+	}
+}
+
+func TestNoticeRefreshAccessTokenWithExpiry(t *testing.T) {
+	var tokens int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestNoticeRefreshAccessTokenWithExpiry", Secret: "SECRET", BaseURL: svr.URL})
+	cache := cachesync.New()
+	app.SetAccessTokenCacheDriver(cache)
+	_ = cache.Save(app.CacheKey(app.Config.Appid), "OLD_TOKEN", 0)
+
+	before := time.Now()
+	accessToken, expiresAt, err := NoticeRefreshAccessTokenWithExpiry(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accessToken != "ACCESS_TOKEN_1" {
+		t.Errorf("NoticeRefreshAccessTokenWithExpiry() token = %s, want ACCESS_TOKEN_1", accessToken)
+	}
+	if expiresAt.Before(before.Add(7200*time.Second)) || expiresAt.After(time.Now().Add(7200*time.Second)) {
+		t.Errorf("NoticeRefreshAccessTokenWithExpiry() expiresAt = %s, want now + 7200s", expiresAt)
+	}
+	if cached, _ := cache.Fetch(app.CacheKey(app.Config.Appid)); cached != "ACCESS_TOKEN_1" {
+		t.Errorf("cached token = %s, want ACCESS_TOKEN_1", cached)
+	}
+}
+
+func TestClient_DoWithHeaders(t *testing.T) {
+	var header http.Header
+	var body string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestClient_DoWithHeaders", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"))
+	resp, err := app.Client.DoWithHeaders(http.MethodPost, "/cgi-bin/debug", strings.NewReader(`{"a":1}`), "application/json", http.Header{"X-Debug": []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != `{"errcode":0,"errmsg":"ok"}` || body != `{"a":1}` {
+		t.Errorf("DoWithHeaders() resp = %s body = %s", resp, body)
+	}
+	if header.Get("X-Debug") != "1" || header.Get("Content-Type") != "application/json" || header.Get("User-Agent") != UserAgent {
+		t.Errorf("DoWithHeaders() headers = %v", header)
+	}
+
+	// 只对本次调用生效
+	if _, err = app.Client.HTTPGet("/cgi-bin/debug"); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Debug") != "" {
+		t.Errorf("HTTPGet() headers = %v, want no X-Debug", header)
+	}
+}
+
+func TestClient_DoWithToken(t *testing.T) {
+	var tokens []string
+	var body string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.URL.Query().Get("access_token"))
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Query().Get("access_token") == "EXPIRED" {
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestClient_DoWithToken", BaseURL: svr.URL}, WithStaticToken("CACHED"))
+	app.SetLogger(nil)
+	var notices int
+	app.SetNoticeAccessTokenExpireHandler(func(ctx *OffiAccount) error {
+		notices++
+		return nil
+	})
+
+	resp, err := app.Client.DoWithToken(http.MethodPost, "/cgi-bin/debug?x=1", "OTHER", strings.NewReader(`{"a":1}`), "application/json")
+	if err != nil || string(resp) != `{"errcode":0,"errmsg":"ok"}` || body != `{"a":1}` {
+		t.Fatalf("DoWithToken() = %s, %v body = %s", resp, err, body)
+	}
+	if len(tokens) != 1 || tokens[0] != "OTHER" {
+		t.Errorf("DoWithToken() tokens = %v", tokens)
+	}
+
+	// 过期时 不刷新 不重试
+	tokens = nil
+	_, err = app.Client.DoWithToken(http.MethodGet, "/cgi-bin/debug", "EXPIRED", nil, "")
+	if err != ErrorAccessTokenExpire || len(tokens) != 1 || notices != 0 {
+		t.Errorf("DoWithToken() error = %v, tokens = %v, notices = %d", err, tokens, notices)
+	}
+
+	// 不影响 缓存的 access_token
+	tokens = nil
+	if _, err = app.Client.HTTPGet("/cgi-bin/debug"); err != nil || len(tokens) != 1 || tokens[0] != "CACHED" {
+		t.Errorf("HTTPGet() error = %v, tokens = %v", err, tokens)
+	}
+}
+
+func TestClient_AccessTokenExpiredErrcodes(t *testing.T) {
+	for _, errcode := range []string{"40001", "40014", "42001"} {
+		t.Run(errcode, func(t *testing.T) {
+			var tokens int
+			handler := http.NewServeMux()
+			handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+				tokens++
+				_, _ = w.Write([]byte(`{"access_token":"FRESH","expires_in":7200}`))
+			})
+			handler.HandleFunc("/cgi-bin/debug", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+				if r.URL.Query().Get("access_token") != "FRESH" {
+					_, _ = w.Write([]byte(`{"errcode":` + errcode + `,"errmsg":"invalid access_token"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+			})
+			svr := httptest.NewServer(handler)
+			defer svr.Close()
+
+			app := New(Config{Appid: "TestClient_AccessTokenExpiredErrcodes" + errcode, Secret: "SECRET", BaseURL: svr.URL}, WithStaticToken("STALE"))
+			app.SetLogger(nil)
+
+			// DoWithToken 外部 token 过期 返回 ErrorAccessTokenExpire
+			if _, err := app.Client.DoWithToken(http.MethodGet, "/cgi-bin/debug", "EXTERNAL", nil, ""); err != ErrorAccessTokenExpire {
+				t.Errorf("DoWithToken() error = %v, want ErrorAccessTokenExpire", err)
+			}
+
+			// DoRaw 刷新 access_token 后重试
+			response, err := app.Client.DoRaw(http.MethodGet, "/cgi-bin/debug", nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+			if string(body) != `{"errcode":0,"errmsg":"ok"}` || tokens != 1 {
+				t.Errorf("DoRaw() body = %s, tokens = %d, want retried with refreshed token", body, tokens)
+			}
+		})
+	}
+}
+
+func TestClient_HTTPGetWithParams(t *testing.T) {
+	var query url.Values
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestClient_HTTPGetWithParams", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"))
+
+	if _, err := app.Client.HTTPGetWithParams("/cgi-bin/debug", url.Values{"type": []string{"a b&c"}}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("type") != "a b&c" || query.Get("access_token") != "ACCESS_TOKEN" {
+		t.Errorf("HTTPGetWithParams() query = %v", query)
+	}
+
+	// uri 已带有查询参数
+	if _, err := app.Client.HTTPGetWithParams("/cgi-bin/debug?x=1", url.Values{"y": []string{"2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("x") != "1" || query.Get("y") != "2" || query.Get("access_token") != "ACCESS_TOKEN" {
+		t.Errorf("HTTPGetWithParams() query = %v", query)
+	}
+}
+
+func TestClient_HTTPPostWithParams(t *testing.T) {
+	var query url.Values
+	var body string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s should be sent to the override host", r.URL.Path)
+	}))
+	defer api.Close()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{
+		Appid:         "TestClient_HTTPPostWithParams",
+		BaseURL:       api.URL,
+		HostOverrides: map[string]string{"/cgi-bin/draft": svr.URL},
+	}, WithStaticToken("ACCESS_TOKEN"))
+
+	// uri 已带有查询参数，且 按前缀 匹配 HostOverrides
+	if _, err := app.Client.HTTPPostWithParams("/cgi-bin/draft/switch?x=1", url.Values{"checkonly": []string{"1"}}, strings.NewReader(`{}`), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("x") != "1" || query.Get("checkonly") != "1" || query.Get("access_token") != "ACCESS_TOKEN" || body != `{}` {
+		t.Errorf("HTTPPostWithParams() query = %v, body = %s", query, body)
+	}
+}
+
+func TestGetAccessTokenDetailed(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestGetAccessTokenDetailed", BaseURL: svr.URL})
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	accessToken, fromCache, err := GetAccessTokenDetailed(app)
+	if err != nil || accessToken != "ACCESS_TOKEN" || fromCache {
+		t.Errorf("GetAccessTokenDetailed() = %s, %v, %v, want fresh ACCESS_TOKEN", accessToken, fromCache, err)
+	}
+	accessToken, fromCache, err = GetAccessTokenDetailed(app)
+	if err != nil || accessToken != "ACCESS_TOKEN" || !fromCache {
+		t.Errorf("GetAccessTokenDetailed() = %s, %v, %v, want cached ACCESS_TOKEN", accessToken, fromCache, err)
+	}
+	if calls != 1 {
+		t.Errorf("token server calls = %d, want 1", calls)
+	}
+}
+
+func TestGetAccessTokenDetailed_Singleflight(t *testing.T) {
+	appids := []string{"TestSingleflightA", "TestSingleflightB"}
+
+	var lock sync.Mutex
+	calls := map[string]int{}
+	arrived := map[string]chan struct{}{}
+	for _, appid := range appids {
+		arrived[appid] = make(chan struct{})
+	}
+	parallel := true
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appid := r.URL.Query().Get("appid")
+		lock.Lock()
+		calls[appid]++
+		if calls[appid] == 1 {
+			close(arrived[appid])
+		}
+		lock.Unlock()
+
+		// 两个 appid 的刷新 应当同时进行
+		for _, other := range appids {
+			select {
+			case <-arrived[other]:
+			case <-time.After(2 * time.Second):
+				lock.Lock()
+				parallel = false
+				lock.Unlock()
+			}
+		}
+		_, _ = w.Write([]byte(`{"access_token":"` + appid + `","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	var apps []*OffiAccount
+	for _, appid := range appids {
+		app := New(Config{Appid: appid, BaseURL: svr.URL})
+		app.SetLogger(nil)
+		app.SetAccessTokenCacheDriver(cachesync.New())
+		apps = append(apps, app)
+	}
+
+	var wg sync.WaitGroup
+	var refreshed [2]int
+	start := make(chan struct{})
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			app := apps[i%2]
+			accessToken, fromCache, err := GetAccessTokenDetailed(app)
+			if err != nil || accessToken != app.Config.Appid {
+				t.Errorf("GetAccessTokenDetailed(%s) = %s, %v", app.Config.Appid, accessToken, err)
+			}
+			if !fromCache {
+				lock.Lock()
+				refreshed[i%2]++
+				lock.Unlock()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i, appid := range appids {
+		if calls[appid] != 1 {
+			t.Errorf("token server calls for %s = %d, want 1", appid, calls[appid])
+		}
+		if refreshed[i] != 1 {
+			t.Errorf("fromCache == false for %s = %d, want 1", appid, refreshed[i])
+		}
+	}
+	if !parallel {
+		t.Error("refreshes for different appids did not run in parallel")
+	}
+}
+
+func TestRefreshAccessToken_Serialized(t *testing.T) {
+	var lock sync.Mutex
+	var issued, inFlight, maxInFlight int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		lock.Lock()
+		inFlight--
+		issued++
+		accessToken := "TOKEN_" + strconv.Itoa(issued)
+		lock.Unlock()
+		_, _ = w.Write([]byte(`{"access_token":"` + accessToken + `","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestRefreshAccessToken_Serialized", BaseURL: svr.URL})
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	// 缓存为空时 GetAccessToken 与 主动刷新 同时进行
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			var err error
+			if i%2 == 0 {
+				_, err = GetAccessToken(app)
+			} else {
+				_, err = NoticeRefreshAccessTokenWithResult(app)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Errorf("concurrent token requests = %d, want 1", maxInFlight)
+	}
+	accessToken, _ := app.AccessToken.Cache.Fetch(app.CacheKey(app.Config.Appid))
+	if want := "TOKEN_" + strconv.Itoa(issued); accessToken != want {
+		t.Errorf("cached access_token = %s, want latest %s", accessToken, want)
+	}
+}
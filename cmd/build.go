@@ -74,7 +74,8 @@ func build(group ApiGroup) {
 		tpl := postFuncTpl
 		_FUNC_NAME_ := ""
 		_GET_PARAMS_ := ""
-		_GET_SUFFIX_PARAMS_ := ""
+		_WITH_PARAMS_ := ""
+		_PARAMS_ARG_ := ""
 		_UPLOAD_ := "media"
 		_FIELD_NAME_ := ""
 		_FIELDS_ := ""
@@ -101,7 +102,8 @@ func build(group ApiGroup) {
 			//if strings.Contains(api.Request, "POST") {
 			//	_GET_PARAMS_ = `, ` + _GET_PARAMS_
 			//}
-			_GET_SUFFIX_PARAMS_ = `+ "?" + params.Encode()`
+			_WITH_PARAMS_ = "WithParams"
+			_PARAMS_ARG_ = ", params"
 		}
 
 		split := strings.Split(api.Request, " ")
@@ -120,7 +122,8 @@ func build(group ApiGroup) {
 		tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", _FUNC_NAME_)
 		tpl = strings.ReplaceAll(tpl, "_UPLOAD_", _UPLOAD_)
 		tpl = strings.ReplaceAll(tpl, "_GET_PARAMS_", _GET_PARAMS_)
-		tpl = strings.ReplaceAll(tpl, "_GET_SUFFIX_PARAMS_", _GET_SUFFIX_PARAMS_)
+		tpl = strings.ReplaceAll(tpl, "_WITH_PARAMS_", _WITH_PARAMS_)
+		tpl = strings.ReplaceAll(tpl, "_PARAMS_ARG_", _PARAMS_ARG_)
 		if _FIELD_NAME_ != "" {
 			_FIELDS_ = strings.ReplaceAll(fieldTpl, "_FIELD_NAME_", _FIELD_NAME_)
 		}
@@ -221,12 +224,12 @@ _REQUEST_
 */`
 var postFuncTpl = commentTpl + `
 func _FUNC_NAME_(ctx *offiaccount.OffiAccount, payload []byte_GET_PARAMS_) (resp []byte, err error) {
-	return ctx.Client.HTTPPost(api_FUNC_NAME__GET_SUFFIX_PARAMS_, bytes.NewReader(payload), "application/json;charset=utf-8")
+	return ctx.Client.HTTPPost_WITH_PARAMS_(api_FUNC_NAME__PARAMS_ARG_, bytes.NewReader(payload), "application/json;charset=utf-8")
 }
 `
 var getFuncTpl = commentTpl + `
 func _FUNC_NAME_(ctx *offiaccount.OffiAccount_GET_PARAMS_) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(api_FUNC_NAME__GET_SUFFIX_PARAMS_)
+	return ctx.Client.HTTPGet_WITH_PARAMS_(api_FUNC_NAME__PARAMS_ARG_)
 }
 `
 var postUploadFuncTpl = commentTpl + `
@@ -252,7 +255,7 @@ func _FUNC_NAME_(ctx *offiaccount.OffiAccount, _UPLOAD_ string_PAYLOAD__GET_PARA
 
 		_FIELDS_
 	}()
-	return ctx.Client.HTTPPost(api_FUNC_NAME__GET_SUFFIX_PARAMS_, r, m.FormDataContentType())
+	return ctx.Client.HTTPPost_WITH_PARAMS_(api_FUNC_NAME__PARAMS_ARG_, r, m.FormDataContentType())
 }
 `
 
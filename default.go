@@ -0,0 +1,86 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrorNoDefault 未通过 SetDefault 设置 默认公众号实例
+var ErrorNoDefault = errors.New("default OffiAccount not set; use SetDefault()")
+
+var (
+	defaultLock        sync.RWMutex
+	defaultOffiAccount *OffiAccount
+)
+
+/*
+SetDefault 设置 默认公众号实例，供 DefaultXxx 系列方法使用，可并发调用
+
+适用于 只有一个公众号的 脚本/小工具；多公众号 请直接使用各自的实例
+
+	offiaccount.SetDefault(offiaccount.New(config))
+	resp, err := offiaccount.DefaultHTTPGet("/cgi-bin/getcallbackip")
+*/
+func SetDefault(offiAccount *OffiAccount) {
+	defaultLock.Lock()
+	defaultOffiAccount = offiAccount
+	defaultLock.Unlock()
+}
+
+// Default 默认公众号实例 未设置时 返回 nil
+func Default() *OffiAccount {
+	defaultLock.RLock()
+	defer defaultLock.RUnlock()
+	return defaultOffiAccount
+}
+
+// getDefault 默认公众号实例 未设置时 返回 ErrorNoDefault
+func getDefault() (*OffiAccount, error) {
+	offiAccount := Default()
+	if offiAccount == nil {
+		return nil, ErrorNoDefault
+	}
+	return offiAccount, nil
+}
+
+// DefaultAccessToken 获取 默认公众号实例 的 access_token
+func DefaultAccessToken() (accessToken string, err error) {
+	offiAccount, err := getDefault()
+	if err != nil {
+		return
+	}
+	return offiAccount.AccessToken.GetAccessTokenHandler(offiAccount)
+}
+
+// DefaultHTTPGet 使用 默认公众号实例 发送 GET 请求
+func DefaultHTTPGet(uri string) (resp []byte, err error) {
+	offiAccount, err := getDefault()
+	if err != nil {
+		return
+	}
+	return offiAccount.Client.HTTPGet(uri)
+}
+
+// DefaultHTTPPost 使用 默认公众号实例 发送 POST 请求
+func DefaultHTTPPost(uri string, payload io.Reader, contentType string) (resp []byte, err error) {
+	offiAccount, err := getDefault()
+	if err != nil {
+		return
+	}
+	return offiAccount.Client.HTTPPost(uri, payload, contentType)
+}
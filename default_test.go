@@ -0,0 +1,68 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	defer SetDefault(nil)
+
+	SetDefault(nil)
+	if _, err := DefaultAccessToken(); err != ErrorNoDefault {
+		t.Errorf("DefaultAccessToken() error = %v, want ErrorNoDefault", err)
+	}
+	if _, err := DefaultHTTPGet("/cgi-bin/getcallbackip"); err != ErrorNoDefault {
+		t.Errorf("DefaultHTTPGet() error = %v, want ErrorNoDefault", err)
+	}
+
+	var method string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestDefault", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			SetDefault(app)
+			_ = Default()
+		}()
+	}
+	wg.Wait()
+
+	if Default() != app {
+		t.Fatal("Default() != app")
+	}
+	if accessToken, err := DefaultAccessToken(); err != nil || accessToken != "ACCESS_TOKEN" {
+		t.Errorf("DefaultAccessToken() = %s, %v", accessToken, err)
+	}
+	if _, err := DefaultHTTPGet("/cgi-bin/getcallbackip"); err != nil || method != http.MethodGet {
+		t.Errorf("DefaultHTTPGet() method = %s, err = %v", method, err)
+	}
+	if _, err := DefaultHTTPPost("/cgi-bin/menu/create", strings.NewReader("{}"), "application/json"); err != nil || method != http.MethodPost {
+		t.Errorf("DefaultHTTPPost() method = %s, err = %v", method, err)
+	}
+}
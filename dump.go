@@ -0,0 +1,93 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// dumpRedactedParams dump 请求时 隐藏的 查询参数
+var dumpRedactedParams = []string{"access_token", "secret"}
+
+/*
+WithLogger 创建实例时 指定日志记录器，传入 nil 关闭日志
+
+开启 Config.DumpHTTP 时 日志不能关闭，nil 会回退为 log.Default()
+*/
+func WithLogger(logger *log.Logger) Option {
+	return func(offiAccount *OffiAccount) {
+		offiAccount.Logger = logger
+	}
+}
+
+// ensureDumpLogger 开启 DumpHTTP 但未设置 Logger 时 回退为 log.Default()，避免 dump 内容无处输出
+func (offiAccount *OffiAccount) ensureDumpLogger() {
+	if offiAccount.Config.DumpHTTP && offiAccount.Logger == nil {
+		offiAccount.Logger = log.Default()
+		offiAccount.Logger.Println("[fastwego/offiaccount] Config.DumpHTTP is on but Logger is nil, fallback to log.Default()")
+	}
+}
+
+// dumpRequest 开启 DumpHTTP 时 记录完整请求（含 body），access_token 等 查询参数 替换为 REDACTED
+func (offiAccount *OffiAccount) dumpRequest(req *http.Request) {
+	if !offiAccount.Config.DumpHTTP || offiAccount.Logger == nil {
+		return
+	}
+
+	// DumpRequestOut 会读取并还原 req.Body，因此 临时替换 URL 而不是 复制请求
+	origin := req.URL
+	req.URL = redactURL(origin)
+	dump, err := httputil.DumpRequestOut(req, true)
+	req.URL = origin
+	if err != nil {
+		offiAccount.Logger.Printf("DumpHTTP request %s %s: %s", req.Method, req.URL.Path, err)
+		return
+	}
+	offiAccount.Logger.Printf("DumpHTTP request:\n%s", dump)
+}
+
+// redactURL 返回 隐藏 dumpRedactedParams 后的 URL 副本
+func redactURL(u *url.URL) *url.URL {
+	query := u.Query()
+	redacted := false
+	for _, name := range dumpRedactedParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u
+	}
+
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return &copied
+}
+
+// dumpResponse 开启 DumpHTTP 时 记录响应状态码 和 响应体
+func (offiAccount *OffiAccount) dumpResponse(req *http.Request, status int, body []byte, err error) {
+	if !offiAccount.Config.DumpHTTP || offiAccount.Logger == nil {
+		return
+	}
+	if err != nil && status == 0 {
+		offiAccount.Logger.Printf("DumpHTTP response %s %s: %s", req.Method, req.URL.Path, err)
+		return
+	}
+	offiAccount.Logger.Printf("DumpHTTP response %s %s: %d\n%s", req.Method, req.URL.Path, status, body)
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConfig_DumpHTTP(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/menu/create", func(w http.ResponseWriter, r *http.Request) {
+		// 只在日志中隐藏 实际请求 仍携带 access_token
+		if r.URL.Query().Get("access_token") != "ACCESS_TOKEN" {
+			t.Errorf("request access_token = %q", r.URL.Query().Get("access_token"))
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	var buf bytes.Buffer
+	app := New(Config{
+		Appid:    "TestConfig_DumpHTTP",
+		BaseURL:  svr.URL,
+		DumpHTTP: true,
+	}, WithStaticToken("ACCESS_TOKEN"), WithLogger(log.New(&buf, "", 0)))
+
+	_, err := app.Client.HTTPPost("/cgi-bin/menu/create", strings.NewReader(`{"button":[]}`), "application/json;charset=utf-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"POST /cgi-bin/menu/create", "access_token=REDACTED", `{"button":[]}`, "200", `{"errcode":0,"errmsg":"ok"}`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("dump missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "access_token=ACCESS_TOKEN HTTP/1.1") {
+		t.Errorf("dump leaks access_token:\n%s", buf.String())
+	}
+}
+
+func TestConfig_DumpHTTP_NilLogger(t *testing.T) {
+	app := New(Config{Appid: "TestConfig_DumpHTTP_NilLogger", DumpHTTP: true}, WithLogger(nil))
+	if app.Logger != log.Default() {
+		t.Errorf("New() Logger = %v, want log.Default()", app.Logger)
+	}
+
+	app.SetLogger(nil)
+	if app.Logger != log.Default() {
+		t.Errorf("SetLogger(nil) Logger = %v, want log.Default()", app.Logger)
+	}
+
+	// 未开启 DumpHTTP 时 允许关闭日志
+	app = New(Config{Appid: "TestConfig_DumpHTTP_NilLogger"}, WithLogger(nil))
+	if app.Logger != nil {
+		t.Errorf("New() Logger = %v, want nil", app.Logger)
+	}
+}
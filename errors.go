@@ -0,0 +1,117 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// 常见 errcode
+const (
+	ErrcodeInvalidIP       = 40164 // 调用接口的 IP 不在白名单中
+	ErrcodeAPIQuotaLimit   = 45009 // 接口调用 超过每日限额
+	ErrcodeAPIUnauthorized = 48001 // api 功能未授权
+)
+
+/*
+WXError 微信接口 返回的错误 (errcode 不为 0)
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Getting_Started/Global_Return_Code.html
+*/
+type WXError struct {
+	Errcode int64  `json:"errcode"`
+	Errmsg  string `json:"errmsg"`
+
+	Raw string `json:"-"` // 原始响应体 仅 HTTPGet/HTTPPost 等 返回的错误 会设置，便于排查
+}
+
+func (e *WXError) Error() string {
+	return fmt.Sprintf("errcode %d: %s", e.Errcode, e.Errmsg)
+}
+
+/*
+AsWXError 从 err（可以是被包装的错误）中 取出 *WXError
+
+	if wxErr, ok := offiaccount.AsWXError(err); ok && wxErr.Errcode == offiaccount.ErrcodeAPIQuotaLimit {
+		// ...
+	}
+
+注意：access_token 无效或过期（40001/40014/42001）返回的是 ErrorAccessTokenExpire
+*/
+func AsWXError(err error) (*WXError, bool) {
+	var wxErr *WXError
+	if errors.As(err, &wxErr) {
+		return wxErr, true
+	}
+	return nil, false
+}
+
+/*
+ParseWXError 解析 微信接口响应中的 errcode 和 errmsg，响应中没有 errcode 时 Errcode 为 0
+
+errcode 按 json.Number 读取 再转换为 int64，避免 float64 的精度问题；兼容 字符串形式的 errcode
+*/
+func ParseWXError(resp []byte) (wxErr WXError, err error) {
+	result := struct {
+		Errcode json.RawMessage `json:"errcode"`
+		Errmsg  string          `json:"errmsg"`
+	}{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	wxErr.Errmsg = result.Errmsg
+
+	raw := bytes.Trim(result.Errcode, `"`)
+	if len(raw) == 0 || string(raw) == "null" {
+		return
+	}
+	wxErr.Errcode, err = json.Number(raw).Int64()
+	if err != nil {
+		err = fmt.Errorf("invalid errcode %s: %w", result.Errcode, err)
+	}
+	return
+}
+
+/*
+OffendingIP 40164 错误中 被拒绝的 IP，需要添加到 公众号 IP 白名单；其他错误 返回空
+
+errmsg 形如 "invalid ip 1.2.3.4 ipv6 ::ffff:1.2.3.4, not in whitelist rid: ..."
+*/
+func (e *WXError) OffendingIP() string {
+	if e.Errcode != ErrcodeInvalidIP {
+		return ""
+	}
+
+	const prefix = "invalid ip "
+	i := strings.Index(e.Errmsg, prefix)
+	if i < 0 {
+		return ""
+	}
+	fields := strings.Fields(e.Errmsg[i+len(prefix):])
+	if len(fields) == 0 {
+		return ""
+	}
+
+	ip := strings.TrimRight(fields[0], ",")
+	if net.ParseIP(ip) == nil {
+		return ""
+	}
+	return ip
+}
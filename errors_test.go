@@ -0,0 +1,109 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWXError_OffendingIP(t *testing.T) {
+	tests := []struct {
+		name string
+		err  WXError
+		want string
+	}{
+		{name: "ipv4", err: WXError{Errcode: 40164, Errmsg: "invalid ip 47.104.13.88 ipv6 ::ffff:47.104.13.88, not in whitelist rid: 6123a4b5-0c1d2e3f-4a5b6c7d"}, want: "47.104.13.88"},
+		{name: "ipv6", err: WXError{Errcode: 40164, Errmsg: "invalid ip 2408:8207:1850::1, not in whitelist"}, want: "2408:8207:1850::1"},
+		{name: "malformed", err: WXError{Errcode: 40164, Errmsg: "invalid ip, not in whitelist"}, want: ""},
+		{name: "other errcode", err: WXError{Errcode: 40013, Errmsg: "invalid ip 1.2.3.4"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.OffendingIP(); got != tt.want {
+				t.Errorf("OffendingIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWXError(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    string
+		want    WXError
+		wantErr bool
+	}{
+		{name: "ok", resp: `{"errcode":0,"errmsg":"ok"}`, want: WXError{Errcode: 0, Errmsg: "ok"}},
+		{name: "no errcode", resp: `{"access_token":"ACCESS_TOKEN","expires_in":7200}`, want: WXError{}},
+		{name: "numeric", resp: `{"errcode":40013,"errmsg":"invalid appid"}`, want: WXError{Errcode: 40013, Errmsg: "invalid appid"}},
+		{name: "negative", resp: `{"errcode":-1,"errmsg":"system error"}`, want: WXError{Errcode: -1, Errmsg: "system error"}},
+		{name: "large", resp: `{"errcode":9007199254740993,"errmsg":"large"}`, want: WXError{Errcode: 9007199254740993, Errmsg: "large"}},
+		{name: "string", resp: `{"errcode":"45009","errmsg":"quota"}`, want: WXError{Errcode: 45009, Errmsg: "quota"}},
+		{name: "invalid", resp: `{"errcode":"abc"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWXError([]byte(tt.resp))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWXError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseWXError() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWXError_Consistent(t *testing.T) {
+	resp := `{"errcode":-1,"errmsg":"system error"}`
+	newResponse := func() *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(resp))}
+	}
+
+	_, err := responseFilter(newResponse())
+	var wxErr *WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != -1 {
+		t.Errorf("responseFilter() error = %v, want errcode -1", err)
+	}
+
+	_, _, retryable, err := parseAccessTokenResponse(newResponse())
+	if err == nil || !retryable {
+		t.Errorf("parseAccessTokenResponse() retryable = %v error = %v, want retryable errcode -1", retryable, err)
+	}
+}
+
+func TestAsWXError(t *testing.T) {
+	resp := `{"errcode":45009,"errmsg":"reach max api daily quota limit"}`
+	_, err := responseFilter(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(resp))})
+
+	wxErr, ok := AsWXError(fmt.Errorf("send: %w", err))
+	if !ok || wxErr.Errcode != ErrcodeAPIQuotaLimit || wxErr.Errmsg != "reach max api daily quota limit" {
+		t.Fatalf("AsWXError() = %+v, %v", wxErr, ok)
+	}
+	if wxErr.Raw != resp {
+		t.Errorf("AsWXError() Raw = %q, want %q", wxErr.Raw, resp)
+	}
+
+	for _, err := range []error{nil, errors.New("other"), ErrorAccessTokenExpire} {
+		if wxErr, ok := AsWXError(err); ok || wxErr != nil {
+			t.Errorf("AsWXError(%v) = %v, %v, want nil, false", err, wxErr, ok)
+		}
+	}
+}
@@ -63,3 +63,13 @@ func ExampleOffiAccount_SetGetAccessTokenHandler() {
 		return
 	})
 }
+
+func ExampleWithStaticToken() {
+	// 单元测试中使用固定 access_token，不会请求 /cgi-bin/token
+	App := offiaccount.New(offiaccount.Config{
+		Appid:  "APPID",
+		Secret: "SECRET",
+	}, offiaccount.WithStaticToken("TEST_TOKEN"))
+
+	_ = App
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"strings"
+	"time"
+)
+
+// ghIdCacheTTL 记录的 公众号原始ID 在 AccessToken.Cache 中的 有效期，过期后 从 之后的推送消息 重新记录
+const ghIdCacheTTL = 30 * 24 * time.Hour
+
+/*
+GhId 公众号原始ID（gh_ 开头）
+
+公众号没有 查询自身基本信息 的接口（开放平台的 api_get_authorizer_info 需要第三方平台授权），所以：
+
+- 优先返回 Config.GhId
+- 否则返回 收到的第一条 可信推送消息（通过 ServeHTTP 校验签名，或 解密成功的 加密消息）的 ToUserName，
+它同时保存在 AccessToken.Cache 中（有效期 30 天），重启后 或 多实例间 仍可读取；记录有误时 可调用 ResetGhId 清除
+
+尚未收到推送消息时 返回空
+*/
+func (offiAccount *OffiAccount) GhId() string {
+	if offiAccount.Config.GhId != "" {
+		return offiAccount.Config.GhId
+	}
+
+	offiAccount.ghIdLock.RLock()
+	ghId := offiAccount.ghId
+	offiAccount.ghIdLock.RUnlock()
+	if ghId != "" {
+		return ghId
+	}
+
+	if offiAccount.AccessToken.Cache == nil {
+		return ""
+	}
+	ghId, _ = offiAccount.AccessToken.Cache.Fetch(offiAccount.ghIdCacheKey())
+	if ghId != "" {
+		offiAccount.setGhId(ghId)
+	}
+	return ghId
+}
+
+// ResetGhId 清除 记录的 公众号原始ID（内存 和 AccessToken.Cache），之后 从 下一条可信推送消息 重新记录
+func (offiAccount *OffiAccount) ResetGhId() error {
+	offiAccount.setGhId("")
+	if offiAccount.AccessToken.Cache == nil {
+		return nil
+	}
+	return offiAccount.AccessToken.Cache.Delete(offiAccount.ghIdCacheKey())
+}
+
+// observeGhId 记录 可信推送消息的 ToUserName 作为 公众号原始ID，调用方 需确保 消息 已校验签名 或 已解密
+func (offiAccount *OffiAccount) observeGhId(toUserName string) {
+	if offiAccount.Config.GhId != "" || !strings.HasPrefix(toUserName, "gh_") {
+		return
+	}
+
+	offiAccount.ghIdLock.RLock()
+	known := offiAccount.ghId != ""
+	offiAccount.ghIdLock.RUnlock()
+	if known {
+		return
+	}
+
+	offiAccount.setGhId(toUserName)
+	if offiAccount.AccessToken.Cache != nil {
+		_ = offiAccount.AccessToken.Cache.Save(offiAccount.ghIdCacheKey(), toUserName, ghIdCacheTTL)
+	}
+}
+
+func (offiAccount *OffiAccount) setGhId(ghId string) {
+	offiAccount.ghIdLock.Lock()
+	offiAccount.ghId = ghId
+	offiAccount.ghIdLock.Unlock()
+}
+
+func (offiAccount *OffiAccount) ghIdCacheKey() string {
+	return offiAccount.CacheKey("gh_id:" + offiAccount.Config.Appid)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/offiaccount/util"
+)
+
+func TestOffiAccount_GhId(t *testing.T) {
+	cache := cachesync.New()
+	app := New(Config{Appid: "TestOffiAccount_GhId"})
+	app.SetAccessTokenCacheDriver(cache)
+	app.SetLogger(nil)
+
+	if got := app.GhId(); got != "" {
+		t.Errorf("GhId() = %q, want empty before any message", got)
+	}
+
+	xml := `<xml><ToUserName><![CDATA[gh_7f083739789a]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hi]]></Content><MsgId>1</MsgId></xml>`
+
+	// 未校验签名的 明文消息 不记录
+	spoofed := strings.Replace(xml, "gh_7f083739789a", "gh_spoofed", 1)
+	if _, err := app.Server.ParseXML([]byte(spoofed)); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.GhId(); got != "" {
+		t.Errorf("GhId() = %q, want empty for unverified plaintext message", got)
+	}
+
+	server := NewServer(app, "TOKEN", "")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(xml)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() code = %d", w.Code)
+	}
+	if got := app.GhId(); got != "gh_7f083739789a" {
+		t.Errorf("GhId() = %q, want gh_7f083739789a", got)
+	}
+
+	// 新实例 从缓存读取
+	other := New(Config{Appid: "TestOffiAccount_GhId"})
+	other.SetAccessTokenCacheDriver(cache)
+	if got := other.GhId(); got != "gh_7f083739789a" {
+		t.Errorf("GhId() from cache = %q, want gh_7f083739789a", got)
+	}
+
+	// Config.GhId 优先
+	configured := New(Config{Appid: "TestOffiAccount_GhId", GhId: "gh_configured"})
+	configured.SetAccessTokenCacheDriver(cache)
+	if got := configured.GhId(); got != "gh_configured" {
+		t.Errorf("GhId() = %q, want gh_configured", got)
+	}
+}
+
+func TestOffiAccount_ResetGhId(t *testing.T) {
+	cache := cachesync.New()
+	app := New(Config{Appid: "TestOffiAccount_ResetGhId", EncodingAESKey: "AdiqDDDvUNCeE1ZW5XJmjf9fqNBJpGBs4vL4cHKmHBS"})
+	app.SetAccessTokenCacheDriver(cache)
+	app.SetLogger(nil)
+
+	// 解密成功的 加密消息 可信
+	encrypt := func(ghId string) []byte {
+		plain := `<xml><ToUserName><![CDATA[` + ghId + `]]></ToUserName><MsgType><![CDATA[text]]></MsgType></xml>`
+		cipherText := util.AESEncryptMsg(util.AESRandomPrefix(), []byte(plain), app.Config.Appid, app.Config.EncodingAESKey)
+		return []byte(`<xml><Encrypt><![CDATA[` + cipherText + `]]></Encrypt></xml>`)
+	}
+	if _, err := app.Server.ParseXML(encrypt("gh_wrong")); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.GhId(); got != "gh_wrong" {
+		t.Fatalf("GhId() = %q, want gh_wrong", got)
+	}
+
+	if err := app.ResetGhId(); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.GhId(); got != "" || cache.Contains(app.ghIdCacheKey()) {
+		t.Errorf("GhId() = %q after ResetGhId, want empty", got)
+	}
+
+	if _, err := app.Server.ParseXML(encrypt("gh_right")); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.GhId(); got != "gh_right" {
+		t.Errorf("GhId() = %q, want gh_right", got)
+	}
+}
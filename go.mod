@@ -1,11 +1,13 @@
 module github.com/fastwego/offiaccount
 
-go 1.14
+go 1.18
 
 require (
+	github.com/alicebob/miniredis/v2 v2.39.0
 	github.com/faabiosr/cachego v0.15.0
 	github.com/garyburd/redigo v1.6.0
-	github.com/gomodule/redigo v1.8.2 // indirect
 	github.com/iancoleman/strcase v0.0.0-20191112232945-16388991a334
-	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208 // indirect
+	golang.org/x/sync v0.2.0
 )
+
+require github.com/yuin/gopher-lua v1.1.1 // indirect
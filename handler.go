@@ -0,0 +1,179 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/fastwego/offiaccount/sign"
+	messagetype "github.com/fastwego/offiaccount/type/type_message"
+)
+
+/*
+HandlerFunc 消息处理函数
+
+message 为 ParseXML 解析后的 消息/事件 类型（如 messagetype.MessageText），返回的 reply 将被回复给用户，nil 或 messagetype.NoReply() 时 回复 success
+*/
+type HandlerFunc func(request *http.Request, message interface{}) (reply interface{})
+
+/*
+NewServer 创建 接收微信推送的 http.Handler
+
+统一处理 签名校验、timestamp 校验（见 VerifyTimestamp）、消息解密（encrypt_type=aes）、消息路由 以及 加密回复
+
+token / encodingAESKey 为空时 使用 oa.Config 中的配置
+
+	server := offiaccount.NewServer(app, "TOKEN", "EncodingAESKey")
+	server.HandleFunc(messagetype.MsgTypeText, func(r *http.Request, message interface{}) interface{} {
+		msg := message.(messagetype.MessageText)
+		return messagetype.ReplyMessageText{...}
+	})
+	http.ListenAndServe(":80", server)
+*/
+func NewServer(oa *OffiAccount, token, encodingAESKey string) *Server {
+	return &Server{
+		Ctx:            oa,
+		token:          token,
+		encodingAESKey: encodingAESKey,
+	}
+}
+
+/*
+HandleFunc 注册 消息处理函数
+
+key 为 消息类型 MsgType（如 text、image）或 事件类型 Event（如 subscribe、CLICK），事件优先按 Event 匹配；key 为空 表示 默认处理函数
+
+应在 开始处理请求前 完成注册
+*/
+func (s *Server) HandleFunc(key string, handler HandlerFunc) {
+	if s.handlers == nil {
+		s.handlers = map[string]HandlerFunc{}
+	}
+	s.handlers[key] = handler
+}
+
+// ServeHTTP 实现 http.Handler GET 请求 为 服务器地址验证，POST 请求 为 消息推送
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodGet {
+		s.EchoStr(writer, request)
+		return
+	}
+
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := request.URL.Query()
+	if query.Get("signature") != sign.ServerURLSign(s.getToken(), query.Get("timestamp"), query.Get("nonce")) {
+		s.serveError(writer, request, http.StatusForbidden, fmt.Errorf("invalid signature"))
+		return
+	}
+
+	if !s.VerifyTimestamp(writer, request) {
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		s.serveError(writer, request, http.StatusBadRequest, err)
+		return
+	}
+
+	// 加密消息 校验 msg_signature
+	if query.Get("encrypt_type") == messagetype.EncryptTypeAES {
+		encryptMsg := messagetype.EncryptMessage{}
+		err = xml.Unmarshal(body, &encryptMsg)
+		if err != nil {
+			s.serveError(writer, request, http.StatusBadRequest, err)
+			return
+		}
+		if encryptMsg.Encrypt == "" || query.Get("msg_signature") != sign.MsgSign(s.getToken(), query.Get("timestamp"), query.Get("nonce"), encryptMsg.Encrypt) {
+			s.serveError(writer, request, http.StatusForbidden, fmt.Errorf("invalid msg_signature"))
+			return
+		}
+	}
+
+	plain, encrypted, err := s.decryptXML(body)
+	if err != nil {
+		s.serveError(writer, request, http.StatusBadRequest, err)
+		return
+	}
+
+	// 已校验 signature（加密消息 还校验了 msg_signature）
+	message, err := s.parseMessage(plain, encrypted, true)
+	if err != nil {
+		s.serveError(writer, request, http.StatusBadRequest, err)
+		return
+	}
+
+	// 开启 Messages() 后 推送到 channel，统一回复 success
+	if s.streaming() {
+		if err = s.push(request, plain, message); err != nil {
+			s.serveError(writer, request, http.StatusServiceUnavailable, err)
+			return
+		}
+		err = s.Response(writer, request, nil)
+		if err != nil && s.Ctx.Logger != nil {
+			s.Ctx.Logger.Println("Response error: ", err)
+		}
+		return
+	}
+
+	var reply interface{}
+	if handler := s.route(plain); handler != nil {
+		reply = handler(request, message)
+	}
+
+	err = s.Response(writer, request, reply)
+	if err != nil && s.Ctx.Logger != nil {
+		s.Ctx.Logger.Println("Response error: ", err)
+	}
+}
+
+// route 按 Event、MsgType、默认 的顺序 查找 处理函数
+func (s *Server) route(plain []byte) HandlerFunc {
+	msgType, event := messageHeader(plain)
+
+	if event != "" {
+		if handler, ok := s.handlers[event]; ok {
+			return handler
+		}
+	}
+	if handler, ok := s.handlers[msgType]; ok {
+		return handler
+	}
+	return s.handlers[""]
+}
+
+// messageHeader 读取 消息类型 和 事件类型
+func messageHeader(plain []byte) (msgType string, event string) {
+	header := struct {
+		MsgType string `xml:"MsgType"`
+		Event   string `xml:"Event"`
+	}{}
+	_ = xml.Unmarshal(plain, &header)
+	return header.MsgType, header.Event
+}
+
+func (s *Server) serveError(writer http.ResponseWriter, request *http.Request, code int, err error) {
+	if s.Ctx.Logger != nil {
+		s.Ctx.Logger.Printf("ServeHTTP %s: %d %s", request.URL.String(), code, err)
+	}
+	writer.WriteHeader(code)
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount/sign"
+	"github.com/fastwego/offiaccount/type/type_event"
+	"github.com/fastwego/offiaccount/type/type_message"
+	"github.com/fastwego/offiaccount/util"
+)
+
+const handlerTestTextXML = `<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content><MsgId>1234567890123456</MsgId></xml>`
+
+func newHandlerTestServer() *Server {
+	server := NewServer(New(Config{Appid: "wx45f133bf6fce646e"}), "TOKEN", "AdiqDDDvUNCeE1ZW5XJmjf9fqNBJpGBs4vL4cHKmHBS")
+	server.Ctx.SetLogger(nil)
+	server.HandleFunc(type_message.MsgTypeText, func(request *http.Request, message interface{}) interface{} {
+		msg := message.(type_message.MessageText)
+		return type_message.ReplyMessageText{
+			ReplyMessage: type_message.ReplyFor(msg.Message, type_message.ReplyMsgTypeText),
+			Content:      type_message.CDATA("echo " + msg.Content),
+		}
+	})
+	return server
+}
+
+func signedQuery(token string) url.Values {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return url.Values{
+		"timestamp": []string{timestamp},
+		"nonce":     []string{"nonce"},
+		"signature": []string{sign.ServerURLSign(token, timestamp, "nonce")},
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	server := newHandlerTestServer()
+
+	t.Run("plain", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(handlerTestTextXML))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("code = %d", w.Code)
+		}
+		reply := type_message.ReplyMessageText{}
+		if err := xml.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+			t.Fatal(err)
+		}
+		if reply.Content != "echo hello" || reply.ToUserName != "fromUser" {
+			t.Errorf("reply = %#v", reply)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		query := signedQuery("TOKEN")
+		query.Set("signature", "123")
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+query.Encode(), strings.NewReader(handlerTestTextXML))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("code = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("invalid xml", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader("<xml>"))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("code = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("default success", func(t *testing.T) {
+		body := `<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>123456789</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[subscribe]]></Event></xml>`
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(body))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || w.Body.String() != "success" {
+			t.Errorf("code = %d, body = %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("echostr", func(t *testing.T) {
+		query := signedQuery("TOKEN")
+		query.Set("echostr", "echostr")
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+		server.ServeHTTP(w, r)
+
+		if w.Body.String() != "echostr" {
+			t.Errorf("body = %q, want echostr", w.Body.String())
+		}
+	})
+}
+
+func TestServer_ServeHTTP_AES(t *testing.T) {
+	server := newHandlerTestServer()
+
+	cipherText := util.AESEncryptMsg(util.AESRandomPrefix(), []byte(handlerTestTextXML), server.Ctx.Config.Appid, server.getEncodingAESKey())
+	body := "<xml><ToUserName><![CDATA[toUser]]></ToUserName><Encrypt><![CDATA[" + cipherText + "]]></Encrypt></xml>"
+
+	query := signedQuery("TOKEN")
+	query.Set("encrypt_type", type_message.EncryptTypeAES)
+	query.Set("msg_signature", sign.MsgSign("TOKEN", query.Get("timestamp"), query.Get("nonce"), cipherText))
+
+	t.Run("ok", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+query.Encode(), strings.NewReader(body))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("code = %d", w.Code)
+		}
+		m, err := server.ParseXML(w.Body.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := m.(type_message.MessageText); !ok || got.Content != "echo hello" {
+			t.Errorf("reply = %#v", m)
+		}
+	})
+
+	t.Run("invalid msg_signature", func(t *testing.T) {
+		invalid := url.Values{}
+		for k, v := range query {
+			invalid[k] = v
+		}
+		invalid.Set("msg_signature", "123")
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?"+invalid.Encode(), strings.NewReader(body))
+		server.ServeHTTP(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("code = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+}
+
+func TestServer_route(t *testing.T) {
+	server := NewServer(New(Config{}), "", "")
+	called := ""
+	for _, key := range []string{type_event.EventTypeSubscribe, type_message.MsgTypeEvent, ""} {
+		key := key
+		server.HandleFunc(key, func(request *http.Request, message interface{}) interface{} {
+			called = key
+			return nil
+		})
+	}
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: "<xml><MsgType>event</MsgType><Event>subscribe</Event></xml>", want: type_event.EventTypeSubscribe},
+		{body: "<xml><MsgType>event</MsgType><Event>CLICK</Event></xml>", want: type_message.MsgTypeEvent},
+		{body: "<xml><MsgType>text</MsgType></xml>", want: ""},
+	}
+	for _, tt := range tests {
+		called = "-"
+		server.route([]byte(tt.body))(nil, nil)
+		if called != tt.want {
+			t.Errorf("route(%s) = %q, want %q", tt.body, called, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultIdempotencyTTL Idempotent 缓存结果的 默认保留时间
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// 同一个 幂等键 的 并发调用 合并为一次
+var idempotentGroup singleflight.Group
+
+/*
+Idempotent 以 key 作为 客户端幂等键 调用 call，成功的响应 缓存在 ctx.AccessToken.Cache 中
+
+ttl 内 再次使用同一个 key 时 直接返回缓存的响应，不再调用 call；ttl <= 0 时 使用 DefaultIdempotencyTTL；key 为空时 不做幂等处理
+
+同一个 key 的 并发调用 只执行一次 call，其余调用 等待并共享其结果（仅限本进程，多实例之间 不互斥）
+
+调用失败 不缓存，可以使用同一个 key 重试；调用成功 但缓存失败时 仍返回响应，并记录日志
+*/
+func (offiAccount *OffiAccount) Idempotent(key string, ttl time.Duration, call func() ([]byte, error)) (resp []byte, err error) {
+	if key == "" {
+		return call()
+	}
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	cacheKey := offiAccount.CacheKey("idempotency:" + offiAccount.Config.Appid + ":" + key)
+	v, err, _ := idempotentGroup.Do(cacheKey, func() (interface{}, error) {
+		if cached, _ := offiAccount.AccessToken.Cache.Fetch(cacheKey); cached != "" {
+			return []byte(cached), nil
+		}
+
+		resp, err := call()
+		if err != nil {
+			return nil, err
+		}
+		if err := offiAccount.AccessToken.Cache.Save(cacheKey, string(resp), ttl); err != nil && offiAccount.Logger != nil {
+			offiAccount.Logger.Printf("Idempotent save %s error %s", cacheKey, err)
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return
+	}
+	return v.([]byte), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestOffiAccount_Idempotent(t *testing.T) {
+	app := New(Config{Appid: "TestOffiAccount_Idempotent"}, WithStaticToken("ACCESS_TOKEN"))
+
+	var calls int
+	var fail bool
+	call := func() ([]byte, error) {
+		calls++
+		if fail {
+			return nil, errors.New("send failed")
+		}
+		return []byte(`{"msgid":` + strconv.Itoa(calls) + `}`), nil
+	}
+
+	// 同一个 key 只调用一次
+	for i := 0; i < 3; i++ {
+		resp, err := app.Idempotent("order:1", 0, call)
+		if err != nil || string(resp) != `{"msgid":1}` {
+			t.Fatalf("Idempotent() = %s, %v", resp, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	// 不同 key
+	if resp, _ := app.Idempotent("order:2", 0, call); string(resp) != `{"msgid":2}` {
+		t.Errorf("Idempotent() = %s", resp)
+	}
+
+	// 失败不缓存 可重试
+	fail = true
+	if _, err := app.Idempotent("order:3", 0, call); err == nil {
+		t.Fatal("Idempotent() want error")
+	}
+	fail = false
+	if resp, err := app.Idempotent("order:3", 0, call); err != nil || string(resp) != `{"msgid":4}` {
+		t.Errorf("Idempotent() = %s, %v", resp, err)
+	}
+
+	// key 为空 不做幂等处理
+	calls = 0
+	_, _ = app.Idempotent("", 0, call)
+	_, _ = app.Idempotent("", 0, call)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestOffiAccount_Idempotent_Concurrent(t *testing.T) {
+	app := New(Config{Appid: "TestOffiAccount_Idempotent_Concurrent"}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	var calls int32
+	call := func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return []byte(`{"msgid":1}`), nil
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			resp, err := app.Idempotent("order:1", 0, call)
+			if err != nil || string(resp) != `{"msgid":1}` {
+				t.Errorf("Idempotent() = %s, %v", resp, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+type failSaveCache struct {
+	cachego.Cache
+}
+
+func (failSaveCache) Save(key string, value string, lifeTime time.Duration) error {
+	return errors.New("cache down")
+}
+
+func TestOffiAccount_Idempotent_SaveError(t *testing.T) {
+	app := New(Config{Appid: "TestOffiAccount_Idempotent_SaveError"}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetAccessTokenCacheDriver(failSaveCache{Cache: cachesync.New()})
+	var buf bytes.Buffer
+	app.SetLogger(log.New(&buf, "", 0))
+
+	resp, err := app.Idempotent("order:1", 0, func() ([]byte, error) {
+		return []byte(`{"msgid":1}`), nil
+	})
+	if err != nil || string(resp) != `{"msgid":1}` {
+		t.Errorf("Idempotent() = %s, %v", resp, err)
+	}
+	if !strings.Contains(buf.String(), "cache down") {
+		t.Errorf("log = %q, want save error", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestId 请求ID 请求头
+const HeaderRequestId = "X-Request-Id"
+
+/*
+Middleware 请求中间件 包装 发送请求的 http.RoundTripper
+
+所有发往微信服务器的请求（包括 刷新 access_token）都会经过中间件
+*/
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc 函数形式的 http.RoundTripper 便于编写中间件
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip 实现 http.RoundTripper
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+/*
+WithMiddleware 添加请求中间件 先添加的 在外层
+
+	app := offiaccount.New(config, offiaccount.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return offiaccount.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Forwarded-For", "127.0.0.1")
+			return next.RoundTrip(req)
+		})
+	}))
+*/
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(offiAccount *OffiAccount) {
+		// 复制 http.Client 避免修改 http.DefaultClient
+		httpClient := *offiAccount.Client.getHTTPClient()
+
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			transport = middlewares[i](transport)
+		}
+		httpClient.Transport = transport
+
+		offiAccount.Client.httpClient = &httpClient
+	}
+}
+
+type requestIdKey struct{}
+
+// ContextWithRequestId 在 context 中 携带请求ID，通过 Client.WithContext 发送的请求 将使用该 ID
+func ContextWithRequestId(parent context.Context, requestId string) context.Context {
+	return context.WithValue(parent, requestIdKey{}, requestId)
+}
+
+// RequestIdFromContext 读取 context 中的请求ID
+func RequestIdFromContext(stdctx context.Context) string {
+	requestId, _ := stdctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+/*
+WithRequestId 为请求 附加 X-Request-Id 请求头 并记录日志
+
+请求ID 依次取自：请求头 X-Request-Id、context（见 ContextWithRequestId）、generate()；generate 为 nil 时 随机生成
+
+access_token 过期 重试的请求 沿用首次请求的 ID
+*/
+func WithRequestId(generate func() string) Option {
+	if generate == nil {
+		generate = newRequestId
+	}
+	return func(offiAccount *OffiAccount) {
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requestId := req.Header.Get(HeaderRequestId)
+				if requestId == "" {
+					requestId = RequestIdFromContext(req.Context())
+				}
+				if requestId == "" {
+					requestId = generate()
+				}
+
+				// RoundTripper 不应修改原请求
+				req = req.Clone(req.Context())
+				req.Header.Set(HeaderRequestId, requestId)
+
+				if offiAccount.Logger != nil {
+					offiAccount.Logger.Printf("%s %s %s %s", HeaderRequestId, requestId, req.Method, req.URL.Path)
+				}
+				return next.RoundTrip(req)
+			})
+		})(offiAccount)
+	}
+}
+
+// newRequestId 随机生成 16 字节 请求ID
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// sentRequestId 响应对应请求 实际发送的请求ID
+func sentRequestId(response *http.Response) string {
+	if response == nil || response.Request == nil {
+		return ""
+	}
+	return response.Request.Header.Get(HeaderRequestId)
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestWithRequestId(t *testing.T) {
+	var lock sync.Mutex
+	var ids []string
+	var tokens int
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		tokens++
+		lock.Unlock()
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/expire", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		ids = append(ids, r.Header.Get(HeaderRequestId))
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("access_token") != "ACCESS_TOKEN_"+strconv.Itoa(tokens) {
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	var seq int
+	app := New(Config{Appid: "TestWithRequestId", Secret: "SECRET", BaseURL: svr.URL}, WithRequestId(func() string {
+		seq++
+		return "REQ_" + strconv.Itoa(seq)
+	}))
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	if app.Client.getHTTPClient() == http.DefaultClient {
+		t.Fatalf("WithRequestId() must not modify http.DefaultClient")
+	}
+
+	expireToken := func() {
+		_ = app.AccessToken.Cache.Save(app.CacheKey(app.Config.Appid), "EXPIRED", 0)
+	}
+
+	// access_token 过期 重试 沿用同一个 ID
+	expireToken()
+	if _, err := app.Client.HTTPGet("/cgi-bin/expire"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] == "" || ids[0] != ids[1] {
+		t.Errorf("HTTPGet() request ids = %v, want same id on retry", ids)
+	}
+
+	ids = nil
+	expireToken()
+	response, err := app.Client.DoRaw(http.MethodGet, "/cgi-bin/expire", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if len(ids) != 2 || ids[0] == "" || ids[0] != ids[1] {
+		t.Errorf("DoRaw() request ids = %v, want same id on retry", ids)
+	}
+
+	// 从 context 传递
+	ids = nil
+	stdctx := ContextWithRequestId(context.Background(), "TRACE_ID")
+	if _, err = app.Client.WithContext(stdctx).HTTPGet("/cgi-bin/expire"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "TRACE_ID" {
+		t.Errorf("WithContext() request ids = %v, want [TRACE_ID]", ids)
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Order")))
+	}))
+	defer svr.Close()
+
+	order := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req = req.Clone(req.Context())
+				req.Header.Add("X-Order", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	app := New(Config{Appid: "TestWithMiddleware", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"), WithMiddleware(order("a"), order("b")))
+	response, err := app.Client.DoRaw(http.MethodGet, "/cgi-bin/order", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if got := response.Request.Header.Values("X-Order"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("middleware order = %v, want [a b]", got)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/apis/user"
+	"github.com/fastwego/offiaccount/mockserver"
+)
+
+func ExampleServer() {
+	svr := mockserver.New()
+	defer svr.Close()
+
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"})
+	app.SetLogger(nil)
+
+	resp, err := user.Get(app, nil)
+	fmt.Println(string(resp), err)
+
+	svr.InjectError("/cgi-bin/user/get", 45009, "reach max api daily quota limit")
+	_, err = user.Get(app, nil)
+	fmt.Println(err)
+
+	// Output:
+	// {"count":2,"data":{"openid":["OPENID1","OPENID2"]},"next_openid":"","total":2} <nil>
+	// errcode 45009: reach max api daily quota limit
+}
+
+func ExampleServer_Handle() {
+	svr := mockserver.New()
+	defer svr.Close()
+
+	svr.Handle("/cgi-bin/menu/get", func(w http.ResponseWriter, r *http.Request) {
+		mockserver.WriteJSON(w, map[string]interface{}{"menu": map[string]interface{}{"button": []interface{}{}}})
+	})
+
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"})
+	app.SetLogger(nil)
+	resp, err := app.Client.HTTPGet("/cgi-bin/menu/get")
+	fmt.Println(string(resp), err)
+
+	// Output:
+	// {"menu":{"button":[]}} <nil>
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package mockserver 模拟微信服务器 用于单元测试
+
+内置 /cgi-bin/token 以及若干常用接口，支持 注入错误 和 统计调用次数
+
+	svr := mockserver.New()
+	defer svr.Close()
+
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"})
+	resp, err := util.GetCallbackIp(app)
+*/
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/offiaccount"
+)
+
+const (
+	AccessToken = "ACCESS_TOKEN" // /cgi-bin/token 下发的 access_token
+	ExpiresIn   = 7200           // access_token 有效期 秒
+)
+
+const (
+	apiToken         = "/cgi-bin/token"
+	apiGetCallbackIp = "/cgi-bin/getcallbackip"
+	apiUserGet       = "/cgi-bin/user/get"
+	apiUserInfo      = "/cgi-bin/user/info"
+)
+
+/*
+Server 模拟的微信服务器
+
+除 /cgi-bin/token 外 请求必须携带 access_token=AccessToken，否则返回 40001
+*/
+type Server struct {
+	*httptest.Server
+
+	mux *http.ServeMux
+
+	lock      sync.Mutex
+	calls     map[string]int
+	injected  map[string][]offiaccount.WXError
+	overrides map[string]http.HandlerFunc
+}
+
+// New 启动模拟服务器 使用完毕后 需调用 Close
+func New() *Server {
+	svr := &Server{
+		mux:       http.NewServeMux(),
+		calls:     map[string]int{},
+		injected:  map[string][]offiaccount.WXError{},
+		overrides: map[string]http.HandlerFunc{},
+	}
+
+	svr.mux.HandleFunc(apiToken, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{"access_token": AccessToken, "expires_in": ExpiresIn})
+	})
+	svr.mux.HandleFunc(apiGetCallbackIp, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{"ip_list": []string{"127.0.0.1", "127.0.0.2"}})
+	})
+	svr.mux.HandleFunc(apiUserGet, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{
+			"total":       2,
+			"count":       2,
+			"data":        map[string][]string{"openid": {"OPENID1", "OPENID2"}},
+			"next_openid": "",
+		})
+	})
+	svr.mux.HandleFunc(apiUserInfo, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{
+			"subscribe": 1,
+			"openid":    r.URL.Query().Get("openid"),
+			"language":  "zh_CN",
+		})
+	})
+
+	svr.Server = httptest.NewServer(http.HandlerFunc(svr.serveHTTP))
+	return svr
+}
+
+func (svr *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	svr.lock.Lock()
+	svr.calls[r.URL.Path]++
+	var wxErr *offiaccount.WXError
+	if queue := svr.injected[r.URL.Path]; len(queue) > 0 {
+		wxErr = &queue[0]
+		svr.injected[r.URL.Path] = queue[1:]
+	}
+	override := svr.overrides[r.URL.Path]
+	svr.lock.Unlock()
+
+	if wxErr != nil {
+		WriteJSON(w, wxErr)
+		return
+	}
+
+	if r.URL.Path != apiToken && r.URL.Query().Get("access_token") != AccessToken {
+		WriteJSON(w, offiaccount.WXError{Errcode: 40001, Errmsg: "invalid credential, access_token is invalid or not latest"})
+		return
+	}
+
+	if override != nil {
+		override(w, r)
+		return
+	}
+	svr.mux.ServeHTTP(w, r)
+}
+
+/*
+Handle 注册(或覆盖)接口 处理方法
+
+path 按请求路径 精确匹配，优先于 内置接口（包括 /cgi-bin/token）；重复注册时 后者生效
+*/
+func (svr *Server) Handle(path string, handler http.HandlerFunc) {
+	svr.lock.Lock()
+	defer svr.lock.Unlock()
+	svr.overrides[path] = handler
+}
+
+// InjectError 下一次请求 path 时 返回指定错误；多次调用 依次返回
+func (svr *Server) InjectError(path string, errcode int64, errmsg string) {
+	svr.lock.Lock()
+	defer svr.lock.Unlock()
+	svr.injected[path] = append(svr.injected[path], offiaccount.WXError{Errcode: errcode, Errmsg: errmsg})
+}
+
+// Calls 接口 path 被请求的次数（包括 注入错误 的请求）
+func (svr *Server) Calls(path string) int {
+	svr.lock.Lock()
+	defer svr.lock.Unlock()
+	return svr.calls[path]
+}
+
+/*
+Apply 将公众号实例 指向模拟服务器
+
+设置 Config.BaseURL 并将 AccessToken 缓存替换为内存缓存，避免读到 其他测试 缓存的 token
+*/
+func (svr *Server) Apply(ctx *offiaccount.OffiAccount) {
+	ctx.Config.BaseURL = svr.URL
+	ctx.SetAccessTokenCacheDriver(cachesync.New())
+}
+
+// NewOffiAccount 创建 指向模拟服务器 的公众号实例
+//
+// opts 在 Apply 之后执行，例如 offiaccount.WithStaticToken 仍然有效
+func (svr *Server) NewOffiAccount(config offiaccount.Config, opts ...offiaccount.Option) *offiaccount.OffiAccount {
+	return offiaccount.New(config, append([]offiaccount.Option{svr.Apply}, opts...)...)
+}
+
+// WriteJSON 输出 json 响应 便于 Handle 自定义接口
+func WriteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	data, _ := json.Marshal(v)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+	"github.com/fastwego/offiaccount/apis/util"
+)
+
+func TestServer(t *testing.T) {
+	svr := New()
+	defer svr.Close()
+
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"})
+	if app.ServerUrl() != svr.URL {
+		t.Fatalf("ServerUrl() = %s, want %s", app.ServerUrl(), svr.URL)
+	}
+
+	resp, err := util.GetCallbackIp(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != `{"ip_list":["127.0.0.1","127.0.0.2"]}` {
+		t.Errorf("GetCallbackIp() = %s", resp)
+	}
+	if svr.Calls(apiToken) != 1 || svr.Calls(apiGetCallbackIp) != 1 {
+		t.Errorf("Calls() token = %d getcallbackip = %d, want 1 1", svr.Calls(apiToken), svr.Calls(apiGetCallbackIp))
+	}
+
+	// 注入错误 只生效一次
+	svr.InjectError(apiGetCallbackIp, 45009, "reach max api daily quota limit")
+	_, err = util.GetCallbackIp(app)
+	var wxErr *offiaccount.WXError
+	if !errors.As(err, &wxErr) || wxErr.Errcode != 45009 {
+		t.Errorf("GetCallbackIp() error = %v, want 45009", err)
+	}
+	if _, err = util.GetCallbackIp(app); err != nil {
+		t.Errorf("GetCallbackIp() error = %v, want nil", err)
+	}
+
+	// 注入 access_token 过期 客户端刷新后重试
+	svr.InjectError(apiGetCallbackIp, 40001, "invalid credential")
+	if _, err = util.GetCallbackIp(app); err != nil {
+		t.Errorf("GetCallbackIp() error = %v, want retried", err)
+	}
+	if svr.Calls(apiToken) != 2 {
+		t.Errorf("Calls(token) = %d, want 2", svr.Calls(apiToken))
+	}
+}
+
+func TestServer_StaticToken(t *testing.T) {
+	svr := New()
+	defer svr.Close()
+
+	// 固定 token 不正确时 返回 40001 客户端刷新后重试
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"}, offiaccount.WithStaticToken("WRONG_TOKEN"))
+	if _, err := util.GetCallbackIp(app); err != nil {
+		t.Errorf("GetCallbackIp() error = %v, want nil", err)
+	}
+	if svr.Calls(apiToken) != 1 || svr.Calls(apiGetCallbackIp) != 2 {
+		t.Errorf("Calls() token = %d getcallbackip = %d, want 1 2", svr.Calls(apiToken), svr.Calls(apiGetCallbackIp))
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	svr := New()
+	defer svr.Close()
+
+	// 覆盖 内置的 /cgi-bin/token
+	svr.Handle(apiToken, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{"access_token": "OVERRIDDEN", "expires_in": ExpiresIn})
+	})
+	app := svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"})
+	token, err := offiaccount.GetAccessToken(app)
+	if err != nil || token != "OVERRIDDEN" {
+		t.Errorf("GetAccessToken() = %s, %v, want OVERRIDDEN", token, err)
+	}
+
+	// 重复注册 后者生效
+	svr.Handle(apiGetCallbackIp, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{"ip_list": []string{"10.0.0.1"}})
+	})
+	svr.Handle(apiGetCallbackIp, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, map[string]interface{}{"ip_list": []string{"10.0.0.2"}})
+	})
+	app = svr.NewOffiAccount(offiaccount.Config{Appid: "APPID", Secret: "SECRET"}, offiaccount.WithStaticToken(AccessToken))
+	resp, err := util.GetCallbackIp(app)
+	if err != nil || string(resp) != `{"ip_list":["10.0.0.2"]}` {
+		t.Errorf("GetCallbackIp() = %s, %v", resp, err)
+	}
+}
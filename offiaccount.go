@@ -21,12 +21,26 @@ package offiaccount
 
 import (
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/faabiosr/cachego"
 	"github.com/faabiosr/cachego/file"
+	cachesync "github.com/faabiosr/cachego/sync"
 )
 
+/*
+Cache 缓存驱动 接口，即 cachego.Cache，用于 缓存 access_token、ticket 等
+
+Fetch 在 key 不存在 时 应返回 空字符串；返回错误时 GetAccessToken 视为 未命中 并刷新 access_token
+
+除了 cachego 提供的驱动，cache 包 提供了 两级缓存，cache/redis 包 提供了 Redis 驱动（多实例 共享 access_token）
+*/
+type Cache = cachego.Cache
+
 // GetAccessTokenFunc 获取 access_token 方法接口
 type GetAccessTokenFunc func(ctx *OffiAccount) (accessToken string, err error)
 
@@ -42,15 +56,33 @@ type OffiAccount struct {
 	Client      Client
 	Server      Server
 	Logger      *log.Logger
+
+	prefetchOnce sync.Once
+
+	skewLock       sync.RWMutex
+	serverTimeSkew time.Duration
+
+	ghIdLock sync.RWMutex
+	ghId     string
+
+	secretLock  sync.RWMutex
+	secretIndex int
+
+	rateLimiterLock sync.RWMutex
 }
 
 /*
 AccessToken 管理器 处理缓存 和 刷新 逻辑
 */
 type AccessToken struct {
-	Cache                          cachego.Cache
+	Cache                          Cache
 	GetAccessTokenHandler          GetAccessTokenFunc
 	NoticeAccessTokenExpireHandler NoticeAccessTokenExpireFunc
+
+	expiryLock sync.RWMutex
+	expiry     map[string]tokenExpiry
+
+	cacheKey func(key string) string // 附加 Config.CacheKeyPrefix 前缀 见 OffiAccount.CacheKey
 }
 
 /*
@@ -61,12 +93,109 @@ type Config struct {
 	Secret         string
 	Token          string
 	EncodingAESKey string
+
+	// Secrets 密钥轮换期间 可同时配置 新旧 AppSecret，设置后 代替 Secret
+	//
+	// 获取 access_token 时 从上次成功的密钥开始 依次尝试，直到成功
+	Secrets []string
+
+	// GhId 公众号原始ID（gh_ 开头） 设置后 明文模式下 会校验推送消息的 ToUserName 是否一致
+	GhId string
+
+	// Timeout 请求微信接口的总超时时间（包括连接、发送、读取响应） 默认 0 不超时
+	Timeout time.Duration
+
+	// DialTimeout 建立连接的超时时间 默认 0 使用系统默认值
+	//
+	// 可以配合 Timeout 使用：微信服务器不可达时快速失败，同时给大文件上传留出足够的总时长
+	DialTimeout time.Duration
+
+	// HTTPClient 请求微信接口（包括 刷新 access_token）使用的 http.Client 默认 nil 使用 http.DefaultClient
+	//
+	// 用于 设置 连接池、TLS、出口代理（Transport.Proxy）等；设置后 Timeout/DialTimeout 不再生效，请在 HTTPClient 上配置
+	HTTPClient *http.Client
+
+	// TokenRefreshAttempts 从微信服务器刷新 access_token 的最大尝试次数 默认 3 次
+	//
+	// 仅在网络错误、http 状态码非 200、系统繁忙(-1) 时重试，每次重试前等待时间翻倍
+	TokenRefreshAttempts int
+
+	// UseStableToken 使用 稳定版 access_token 接口 /cgi-bin/stable_token 获取 access_token
+	//
+	// 稳定版接口 普通模式下 有效期内重复获取 不会使旧 token 失效，适合多个服务 各自获取 access_token 的场景
+	UseStableToken bool
+
+	// CacheKeyPrefix 缓存 key 前缀 例如 "prod:"，多个服务共用 Redis 等缓存时 用于隔离
+	//
+	// access_token 的缓存 key 为 CacheKeyPrefix + Appid，其他缓存（如 ticket）同样附加该前缀
+	CacheKeyPrefix string
+
+	// RetryClassifier 接口调用 重试策略 默认 DefaultRetryClassifier
+	//
+	// 作用于 HTTPGet/HTTPPost 发起的请求，默认最多重试 1 次（见 RetryDecision.MaxRetries）
+	RetryClassifier RetryClassifier
+
+	// MaxRetries http 状态码 5xx 或 网络错误 时 最多重试次数 默认 0 不重试
+	//
+	// 与 RetryClassifier 的重试 分别计数，access_token 无效时 刷新重试 不占用 MaxRetries
+	MaxRetries int
+
+	// RetryBackoff 第 n 次（从 0 开始）5xx/网络错误 重试前的 等待时间 默认 DefaultRetryBackoff
+	RetryBackoff func(n int) time.Duration
+
+	// RateLimiter 接口调用 限流器 默认为 nil 不限流；重试前同样需要放行
+	//
+	// 设置后 每次调用接口（不包括 获取 access_token）前 都会等待限流器放行
+	//
+	// 实例使用中 需修改时 请调用 SetRateLimiter
+	RateLimiter RateLimiter
+
+	// BaseURL 微信 api 服务器地址 默认为空 使用全局 WXServerUrl
+	//
+	// 可指向 反向代理 或 测试用的 mock 服务器（见 mockserver 包），仅影响当前公众号实例
+	BaseURL string
+
+	// ReplayWindow Server.VerifyTimestamp 允许的 推送请求 timestamp 最大偏差，默认 DefaultReplayWindow
+	ReplayWindow time.Duration
+
+	// HostOverrides 按接口路径前缀 指定服务器地址，优先于 BaseURL，前缀最长者优先
+	//
+	// 值可以是 域名 或 完整地址，如 {"/cgi-bin/showqrcode": "mp.weixin.qq.com"}，未带协议时 使用 https
+	HostOverrides map[string]string
+
+	// ValidateResponse 校验 errcode 为 0 的接口响应 默认为 nil 不校验
+	//
+	// 返回错误时 包装为 *ResponseValidationError，DefaultRetryClassifier 会重试一次
+	ValidateResponse ResponseValidator
+
+	// DumpHTTP 调试用 记录 HTTPGet/HTTPPost 的完整请求 和 响应体 到 Logger 默认 false；请求中的 access_token、secret 会替换为 REDACTED
+	//
+	// 开启时 Logger 为 nil（WithLogger(nil) 或 SetLogger(nil)）会回退为 log.Default()，避免 dump 内容无处输出
+	DumpHTTP bool
+}
+
+// Option 创建公众号实例时的可选配置
+type Option func(offiAccount *OffiAccount)
+
+/*
+WithStaticToken 使用固定的 access_token
+
+AccessToken 缓存替换为内存缓存，并预先存入 token（永不过期），接口调用不会再访问 /cgi-bin/token
+
+适用于单元测试，或者 access_token 由外部系统管理的场景
+*/
+func WithStaticToken(token string) Option {
+	return func(offiAccount *OffiAccount) {
+		cache := cachesync.New()
+		_ = cache.Save(offiAccount.CacheKey(offiAccount.Config.Appid), token, 0)
+		offiAccount.AccessToken.Cache = cache
+	}
 }
 
 /*
 创建公众号实例
 */
-func New(config Config) (offiAccount *OffiAccount) {
+func New(config Config, opts ...Option) (offiAccount *OffiAccount) {
 	instance := OffiAccount{
 		Config: config,
 		AccessToken: AccessToken{
@@ -76,14 +205,73 @@ func New(config Config) (offiAccount *OffiAccount) {
 		},
 	}
 
-	instance.Client = Client{Ctx: &instance}
+	instance.AccessToken.cacheKey = instance.CacheKey
+	instance.Client = Client{Ctx: &instance, httpClient: newHTTPClient(config)}
 	instance.Server = Server{Ctx: &instance}
 
 	instance.Logger = log.New(os.Stdout, "[fastwego/offiaccount] ", log.LstdFlags|log.Llongfile)
 
+	for _, opt := range opts {
+		opt(&instance)
+	}
+	instance.ensureDumpLogger()
+
 	return &instance
 }
 
+// ServerUrl 当前实例 请求的微信 api 服务器地址 优先使用 Config.BaseURL
+func (offiAccount *OffiAccount) ServerUrl() string {
+	if offiAccount.Config.BaseURL != "" {
+		return offiAccount.Config.BaseURL
+	}
+	return WXServerUrl
+}
+
+/*
+ServerUrlFor 请求 uri 时 使用的服务器地址
+
+按前缀匹配 Config.HostOverrides，未匹配时 使用 ServerUrl()
+*/
+func (offiAccount *OffiAccount) ServerUrlFor(uri string) string {
+	if host, ok := offiAccount.HostOverride(uri); ok {
+		return host
+	}
+	return offiAccount.ServerUrl()
+}
+
+/*
+HostOverride 按前缀匹配 Config.HostOverrides，ok 表示 是否匹配
+
+默认服务器 不是 api.weixin.qq.com 的接口（如 mp.weixin.qq.com 的 showqrcode）未匹配时 使用各自的默认地址
+*/
+func (offiAccount *OffiAccount) HostOverride(uri string) (host string, ok bool) {
+	path := uri
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	path = "/" + strings.TrimLeft(path, "/")
+
+	matched := ""
+	for prefix, h := range offiAccount.Config.HostOverrides {
+		p := "/" + strings.TrimLeft(prefix, "/")
+		if strings.HasPrefix(path, p) && len(p) > len(matched) {
+			matched, host = p, h
+		}
+	}
+	if host == "" {
+		return "", false
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return host, true
+}
+
+// CacheKey 附加 Config.CacheKeyPrefix 前缀 后的缓存 key
+func (offiAccount *OffiAccount) CacheKey(key string) string {
+	return offiAccount.Config.CacheKeyPrefix + key
+}
+
 /*
 SetAccessTokenCacheDriver 设置 AccessToken 缓存器 默认为文件缓存：目录 os.TempDir()
 
@@ -118,8 +306,9 @@ SetLogger 日志记录 默认输出到 os.Stdout
 
 可以新建 logger 输出到指定文件
 
-如果不想开启日志，可以 SetLogger(nil)
+如果不想开启日志，可以 SetLogger(nil)（开启 Config.DumpHTTP 时 回退为 log.Default()）
 */
 func (offiAccount *OffiAccount) SetLogger(logger *log.Logger) {
 	offiAccount.Logger = logger
+	offiAccount.ensureDumpLogger()
 }
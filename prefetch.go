@@ -0,0 +1,88 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"time"
+)
+
+// 在 access_token 过期前 提前多久刷新
+var tokenPrefetchAhead = 5 * time.Minute
+
+// 后台刷新失败后 重试的间隔
+var tokenPrefetchRetryInterval = 30 * time.Second
+
+/*
+StartTokenPrefetcher 启动后台 goroutine 在 access_token 过期前 主动刷新，保证接口调用总能命中缓存
+
+启动时会立即刷新一次（微信保证新旧 access_token 在 5 分钟内同时可用），之后在每次 缓存过期（0.9 * expires_in）前 5 分钟刷新
+
+stdctx 取消后 goroutine 退出；重复调用只会启动一个 goroutine
+
+注意：后台刷新直接请求微信服务器，多实例服务请使用中控服务器，不要在每个实例上启动
+*/
+func (offiAccount *OffiAccount) StartTokenPrefetcher(stdctx context.Context) {
+	offiAccount.prefetchOnce.Do(func() {
+		go offiAccount.runTokenPrefetcher(stdctx)
+	})
+}
+
+func (offiAccount *OffiAccount) runTokenPrefetcher(stdctx context.Context) {
+	for {
+		wait := tokenPrefetchRetryInterval
+		ttl, err := prefetchAccessToken(offiAccount)
+		if err != nil {
+			if offiAccount.Logger != nil {
+				offiAccount.Logger.Printf("prefetchAccessToken error %s, retry after %s", err, wait)
+			}
+		} else {
+			wait = nextTokenPrefetch(ttl)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-stdctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// nextTokenPrefetch 根据 缓存时间 计算下次刷新的等待时间 缓存时间过短时 至少等待一半缓存时间
+func nextTokenPrefetch(ttl time.Duration) time.Duration {
+	wait := ttl - tokenPrefetchAhead
+	if wait < ttl/2 {
+		wait = ttl / 2
+	}
+	return wait
+}
+
+// prefetchAccessToken 从微信服务器刷新 access_token 并更新缓存 返回缓存时间
+func prefetchAccessToken(ctx *OffiAccount) (ttl time.Duration, err error) {
+	lock := refreshAccessTokenLock(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+
+	result, err := refreshAccessToken(ctx)
+	if err != nil {
+		return
+	}
+
+	ttl = accessTokenTTL(result.ExpiresIn)
+	err = ctx.saveAccessToken(result.AccessToken, result.ExpiresIn)
+	return
+}
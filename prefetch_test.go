@@ -0,0 +1,120 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestNextTokenPrefetch(t *testing.T) {
+	tests := []struct {
+		expiresIn int
+		want      time.Duration
+	}{
+		{expiresIn: 7200, want: 6180 * time.Second},
+		{expiresIn: 600, want: 270 * time.Second},
+		{expiresIn: 1, want: 450 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		ttl := accessTokenTTL(tt.expiresIn)
+		got := nextTokenPrefetch(ttl)
+		if got != tt.want {
+			t.Errorf("nextTokenPrefetch(%s) = %s, want %s", ttl, got, tt.want)
+		}
+		// 必须在 缓存过期前 刷新
+		if got >= ttl {
+			t.Errorf("nextTokenPrefetch(%s) = %s, want before cache expiry", ttl, got)
+		}
+	}
+}
+
+func TestOffiAccount_StartTokenPrefetcher(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid:  "TestOffiAccount_StartTokenPrefetcher",
+		Secret: "SECRET",
+	})
+	MockOffiAccount.SetAccessTokenCacheDriver(cachesync.New())
+	MockOffiAccount.SetLogger(nil)
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	var calls int32
+	MockSvrHandler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":3}`))
+	})
+
+	stdctx, cancel := context.WithCancel(context.Background())
+	MockOffiAccount.StartTokenPrefetcher(stdctx)
+	MockOffiAccount.StartTokenPrefetcher(stdctx) // 重复调用 不会再启动
+
+	// 启动时 立即刷新
+	time.Sleep(100 * time.Millisecond)
+	accessToken, _ := MockOffiAccount.AccessToken.Cache.Fetch(MockOffiAccount.Config.Appid)
+	if accessToken != "ACCESS_TOKEN" {
+		t.Errorf("cached access_token = %s, want ACCESS_TOKEN", accessToken)
+	}
+
+	// 有效期 3s 缓存 2.7s 每 1.35s 刷新一次
+	time.Sleep(2100 * time.Millisecond)
+	got := atomic.LoadInt32(&calls)
+	if got < 2 || got > 4 {
+		t.Errorf("token refreshed %d times in 2.2s, want 2", got)
+	}
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	got = atomic.LoadInt32(&calls)
+	time.Sleep(1100 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != got {
+		t.Errorf("token refreshed after context canceled")
+	}
+}
+
+func TestOffiAccount_StartTokenPrefetcher_BeforeCacheExpiry(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":6}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestOffiAccount_StartTokenPrefetcher_BeforeCacheExpiry", Secret: "SECRET", BaseURL: svr.URL})
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	app.SetLogger(nil)
+
+	stdctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.StartTokenPrefetcher(stdctx)
+	time.Sleep(100 * time.Millisecond)
+
+	// 缓存 5.4s（cachego/sync 按秒取整 至少 4s）过期，后台刷新 2.7s 时 续上，缓存 始终命中
+	deadline := time.Now().Add(3500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		accessToken, _ := app.AccessToken.Cache.Fetch(app.CacheKey(app.Config.Appid))
+		if accessToken == "" {
+			t.Fatal("access_token dropped out of cache before prefetch")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
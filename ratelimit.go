@@ -0,0 +1,58 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+)
+
+/*
+RateLimiter 接口调用 限流器，可直接使用 golang.org/x/time/rate.Limiter
+
+	app := offiaccount.New(offiaccount.Config{
+		Appid:       "APPID",
+		Secret:      "SECRET",
+		RateLimiter: rate.NewLimiter(rate.Limit(50), 10), // 每秒 50 次
+	})
+*/
+type RateLimiter interface {
+	// Wait 阻塞直到允许发送请求 或 stdctx 取消
+	Wait(stdctx context.Context) error
+}
+
+// waitRateLimit 按 Config.RateLimiter 限流 未设置时 不限流
+func (offiAccount *OffiAccount) waitRateLimit(stdctx context.Context) error {
+	offiAccount.rateLimiterLock.RLock()
+	limiter := offiAccount.Config.RateLimiter
+	offiAccount.rateLimiterLock.RUnlock()
+
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(stdctx)
+}
+
+/*
+SetRateLimiter 替换 Config.RateLimiter 并返回 原有的限流器
+
+实例 已在使用中（其他 goroutine 正在调用接口）时 需通过该方法修改，直接修改 Config.RateLimiter 会产生数据竞争
+*/
+func (offiAccount *OffiAccount) SetRateLimiter(limiter RateLimiter) (previous RateLimiter) {
+	offiAccount.rateLimiterLock.Lock()
+	defer offiAccount.rateLimiterLock.Unlock()
+	previous = offiAccount.Config.RateLimiter
+	offiAccount.Config.RateLimiter = limiter
+	return
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+type countLimiter struct {
+	waits int
+	err   error
+}
+
+func (l *countLimiter) Wait(stdctx context.Context) error {
+	l.waits++
+	return l.err
+}
+
+func TestRateLimiter(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	limiter := &countLimiter{}
+	app := New(Config{Appid: "TestRateLimiter", BaseURL: svr.URL, RateLimiter: limiter}, WithStaticToken("ACCESS_TOKEN"))
+
+	if _, err := app.Client.HTTPGet("/cgi-bin/limit"); err != nil {
+		t.Fatal(err)
+	}
+	response, err := app.Client.DoRaw(http.MethodGet, "/cgi-bin/limit", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if limiter.waits != 2 || calls != 2 {
+		t.Errorf("waits = %d calls = %d, want 2 2", limiter.waits, calls)
+	}
+
+	// 限流器返回错误 不发送请求
+	limiter.err = errors.New("rate: Wait(n=1) would exceed context deadline")
+	if _, err = app.Client.HTTPGet("/cgi-bin/limit"); err != limiter.err {
+		t.Errorf("HTTPGet() error = %v, want %v", err, limiter.err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRateLimiter_Retry(t *testing.T) {
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/limit", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	limiter := &countLimiter{}
+	app := New(Config{Appid: "TestRateLimiter_Retry", Secret: "SECRET", BaseURL: svr.URL, RateLimiter: limiter})
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	// 刷新 access_token 后的重试 同样需要 限流器 放行
+	if _, err := app.Client.HTTPGet("/cgi-bin/limit"); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.waits != 2 || calls != 2 {
+		t.Errorf("waits = %d calls = %d, want 2 2", limiter.waits, calls)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// limiter 按固定间隔 放行请求 的限流器，interval 为 0 时 不限流
+type limiter struct {
+	lock     sync.Mutex
+	interval time.Duration
+	next     time.Time
+
+	base offiaccount.RateLimiter // 实例原有的 限流器
+}
+
+func (l *limiter) setRate(rps float64) {
+	var interval time.Duration
+	if rps > 0 {
+		interval = time.Duration(float64(time.Second) / rps)
+	}
+
+	l.lock.Lock()
+	l.interval = interval
+	l.lock.Unlock()
+}
+
+/*
+Wait 实现 offiaccount.RateLimiter
+
+先预留 放行时间，等待期间 stdctx 取消 或 原有限流器 出错时 归还预留（之后已有其他请求预留时 不归还，只会更保守）
+*/
+func (l *limiter) Wait(stdctx context.Context) error {
+	l.lock.Lock()
+	var wait, interval time.Duration
+	var reserved time.Time
+	if l.interval > 0 {
+		interval = l.interval
+		now := time.Now()
+		if l.next.Before(now) {
+			l.next = now
+		}
+		wait = l.next.Sub(now)
+		l.next = l.next.Add(interval)
+		reserved = l.next
+	}
+	base := l.base
+	l.lock.Unlock()
+
+	var err error
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-stdctx.Done():
+			timer.Stop()
+			err = stdctx.Err()
+		}
+	}
+	if err == nil && base != nil {
+		err = base.Wait(stdctx)
+	}
+
+	if err != nil && interval > 0 {
+		l.lock.Lock()
+		if l.next.Equal(reserved) {
+			l.next = l.next.Add(-interval)
+		}
+		l.lock.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package registry 管理 多个公众号实例，按 appid 查找
+
+每个注册的公众号 拥有独立的 限流器，一个公众号调用频繁 不会影响其他公众号
+
+	reg := registry.New()
+	reg.Register(offiaccount.New(config1))
+	reg.Register(offiaccount.New(config2))
+	_ = reg.SetRateLimit("APPID1", 50)
+
+	app, ok := reg.Get("APPID1")
+*/
+package registry
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fastwego/offiaccount"
+)
+
+// Registry 公众号实例 注册表 可并发使用
+type Registry struct {
+	lock     sync.RWMutex
+	accounts map[string]*offiaccount.OffiAccount
+	limiters map[string]*limiter
+}
+
+// New 创建 注册表
+func New() *Registry {
+	return &Registry{
+		accounts: map[string]*offiaccount.OffiAccount{},
+		limiters: map[string]*limiter{},
+	}
+}
+
+/*
+Register 注册 公众号实例，相同 appid 的实例 会被替换
+
+注册时 通过 SetRateLimiter 为实例安装 独立的限流器（默认不限流，见 SetRateLimit）；实例原有的 Config.RateLimiter 仍然生效，重复注册 不会叠加
+
+注册后 请勿再修改 实例的 Config.RateLimiter
+*/
+func (r *Registry) Register(offiAccount *offiaccount.OffiAccount) {
+	l := &limiter{}
+	l.lock.Lock()
+	l.base = offiAccount.SetRateLimiter(l)
+	if registered, ok := l.base.(*limiter); ok {
+		registered.lock.Lock()
+		l.base = registered.base
+		registered.lock.Unlock()
+	}
+	l.lock.Unlock()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.accounts[offiAccount.Config.Appid] = offiAccount
+	r.limiters[offiAccount.Config.Appid] = l
+}
+
+// Get 按 appid 查找 公众号实例
+func (r *Registry) Get(appid string) (offiAccount *offiaccount.OffiAccount, ok bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	offiAccount, ok = r.accounts[appid]
+	return
+}
+
+// Appids 已注册的 appid 列表
+func (r *Registry) Appids() (appids []string) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for appid := range r.accounts {
+		appids = append(appids, appid)
+	}
+	return
+}
+
+// SetRateLimit 设置 appid 对应公众号 每秒最多调用接口次数，rps <= 0 时 不限流；可在运行中调整
+func (r *Registry) SetRateLimit(appid string, rps float64) error {
+	r.lock.RLock()
+	l, ok := r.limiters[appid]
+	r.lock.RUnlock()
+	if !ok {
+		return fmt.Errorf("registry: appid %s not registered", appid)
+	}
+
+	l.setRate(rps)
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestRegistry(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer svr.Close()
+
+	reg := New()
+	busy := offiaccount.New(offiaccount.Config{Appid: "BUSY", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	idle := offiaccount.New(offiaccount.Config{Appid: "IDLE", BaseURL: svr.URL}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	reg.Register(busy)
+	reg.Register(idle)
+
+	if app, ok := reg.Get("BUSY"); !ok || app != busy {
+		t.Errorf("Get(BUSY) = %v, %v", app, ok)
+	}
+	if _, ok := reg.Get("UNKNOWN"); ok {
+		t.Error("Get(UNKNOWN) want not found")
+	}
+	appids := reg.Appids()
+	sort.Strings(appids)
+	if len(appids) != 2 || appids[0] != "BUSY" || appids[1] != "IDLE" {
+		t.Errorf("Appids() = %v", appids)
+	}
+	if err := reg.SetRateLimit("UNKNOWN", 1); err == nil {
+		t.Error("SetRateLimit(UNKNOWN) want error")
+	}
+
+	// BUSY 每秒 10 次，IDLE 不受影响
+	if err := reg.SetRateLimit("BUSY", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = busy.Client.HTTPGet("/cgi-bin/getcallbackip")
+		}()
+	}
+
+	idleStart := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := idle.Client.HTTPGet("/cgi-bin/getcallbackip"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(idleStart); elapsed > 200*time.Millisecond {
+		t.Errorf("idle account throttled: %s", elapsed)
+	}
+
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("busy account not throttled: %s", elapsed)
+	}
+}
+
+type countLimiter struct {
+	lock  sync.Mutex
+	calls int
+}
+
+func (c *countLimiter) Wait(stdctx context.Context) error {
+	c.lock.Lock()
+	c.calls++
+	c.lock.Unlock()
+	return nil
+}
+
+func TestLimiter(t *testing.T) {
+	base := &countLimiter{}
+	l := &limiter{base: base}
+
+	// 默认 不限流
+	if err := l.Wait(context.Background()); err != nil || base.calls != 1 {
+		t.Errorf("Wait() = %v, base calls = %d", err, base.calls)
+	}
+
+	l.setRate(1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	stdctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(stdctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want DeadlineExceeded", err)
+	}
+
+	l.setRate(0)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() after setRate(0) = %v", err)
+	}
+}
+
+func TestLimiter_CancelReturnsSlot(t *testing.T) {
+	l := &limiter{}
+	l.setRate(10)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 取消的请求 归还预留，不推迟 之后的请求
+	for i := 0; i < 3; i++ {
+		stdctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		if err := l.Wait(stdctx); err != context.DeadlineExceeded {
+			t.Errorf("Wait() error = %v, want DeadlineExceeded", err)
+		}
+		cancel()
+	}
+
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Wait() after cancelled waits took %s, want about 100ms", elapsed)
+	}
+}
+
+func TestRegistry_RegisterInUse(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer svr.Close()
+
+	base := &countLimiter{}
+	app := offiaccount.New(offiaccount.Config{Appid: "INUSE", BaseURL: svr.URL, RateLimiter: base}, offiaccount.WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	// 实例使用中 注册（go test -race 检查 数据竞争）
+	reg := New()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			_, _ = app.Client.HTTPGet("/cgi-bin/getcallbackip")
+		}
+	}()
+	reg.Register(app)
+	reg.Register(app)
+	wg.Wait()
+
+	// 重复注册 不叠加 registry 限流器
+	l := app.SetRateLimiter(nil).(*limiter)
+	if l.base != base {
+		t.Errorf("limiter base = %T, want original RateLimiter", l.base)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultReplayWindow 推送请求 timestamp 与 服务器时间 默认允许的最大偏差
+const DefaultReplayWindow = 300 * time.Second
+
+/*
+VerifyTimestamp 校验 微信推送请求的 timestamp 参数，防止 重放攻击，应在 ParseXML 前调用
+
+timestamp 与 当前时间（按 ServerTimeSkew 校正）相差超过 Config.ReplayWindow（默认 DefaultReplayWindow）时，记录日志 并响应 403，返回 false
+
+	if !app.Server.VerifyTimestamp(w, r) {
+		return
+	}
+	message, err := app.Server.ParseXML(body)
+*/
+func (s *Server) VerifyTimestamp(writer http.ResponseWriter, request *http.Request) bool {
+	window := s.Ctx.Config.ReplayWindow
+	if window <= 0 {
+		window = DefaultReplayWindow
+	}
+
+	timestamp := request.URL.Query().Get("timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err == nil {
+		offset := s.Ctx.ServerTime().Sub(time.Unix(seconds, 0))
+		if offset <= window && offset >= -window {
+			return true
+		}
+	}
+
+	if s.Ctx.Logger != nil {
+		s.Ctx.Logger.Printf("VerifyTimestamp: stale timestamp %q from %s", timestamp, request.RemoteAddr)
+	}
+	writer.WriteHeader(http.StatusForbidden)
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestServer_VerifyTimestamp(t *testing.T) {
+	app := New(Config{Appid: "TestServer_VerifyTimestamp"})
+	app.SetLogger(nil)
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		skew      time.Duration
+		timestamp string
+		want      bool
+	}{
+		{name: "now", timestamp: strconv.FormatInt(time.Now().Unix(), 10), want: true},
+		{name: "within default window", timestamp: strconv.FormatInt(time.Now().Add(-200*time.Second).Unix(), 10), want: true},
+		{name: "stale", timestamp: strconv.FormatInt(time.Now().Add(-400*time.Second).Unix(), 10), want: false},
+		{name: "future", timestamp: strconv.FormatInt(time.Now().Add(400*time.Second).Unix(), 10), want: false},
+		{name: "custom window", window: 10 * time.Second, timestamp: strconv.FormatInt(time.Now().Add(-60*time.Second).Unix(), 10), want: false},
+		{name: "skew adjusted", skew: time.Hour, timestamp: strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10), want: true},
+		{name: "invalid", timestamp: "abc", want: false},
+		{name: "missing", timestamp: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.Config.ReplayWindow = tt.window
+			app.setServerTimeSkew(tt.skew)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/?timestamp="+tt.timestamp+"&nonce=NONCE", nil)
+			if got := app.Server.VerifyTimestamp(recorder, request); got != tt.want {
+				t.Errorf("VerifyTimestamp() = %v, want %v", got, tt.want)
+			}
+			if !tt.want && recorder.Code != http.StatusForbidden {
+				t.Errorf("VerifyTimestamp() status = %d, want 403", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// 接口调用 最多重试次数
+const defaultMaxRetries = 1
+
+// RetryDecision 重试决策
+type RetryDecision struct {
+	Retry        bool          // 是否重试
+	RefreshToken bool          // 重试前 是否刷新 access_token
+	Delay        time.Duration // 重试前 等待时间
+	MaxRetries   int           // 最多重试次数 默认 0 表示 1 次
+}
+
+/*
+RetryClassifier 根据 接口响应 判断是否重试
+
+resp 为响应体，httpStatus 为 http 状态码（网络错误时为 0），err 为请求 或 响应检查 的错误
+
+	app := offiaccount.New(offiaccount.Config{
+		Appid:  "APPID",
+		Secret: "SECRET",
+		RetryClassifier: func(resp []byte, httpStatus int, err error) offiaccount.RetryDecision {
+			if wxErr, _ := offiaccount.ParseWXError(resp); wxErr.Errcode == 45011 { // 频率限制
+				return offiaccount.RetryDecision{Retry: true, Delay: time.Second}
+			}
+			return offiaccount.DefaultRetryClassifier(resp, httpStatus, err)
+		},
+	})
+*/
+type RetryClassifier func(resp []byte, httpStatus int, err error) RetryDecision
+
+/*
+DefaultRetryClassifier 默认重试策略：access_token 无效或过期（40001/40014/42001）时 刷新 access_token 后重试；
+Config.ValidateResponse 校验失败时 直接重试
+*/
+func DefaultRetryClassifier(resp []byte, httpStatus int, err error) RetryDecision {
+	var validationErr *ResponseValidationError
+	if errors.As(err, &validationErr) {
+		return RetryDecision{Retry: true}
+	}
+
+	if httpStatus != http.StatusOK {
+		return RetryDecision{}
+	}
+
+	wxErr, parseErr := ParseWXError(resp)
+	if parseErr != nil {
+		return RetryDecision{}
+	}
+	switch wxErr.Errcode {
+	case 40001, 40014, 42001:
+		return RetryDecision{Retry: true, RefreshToken: true}
+	}
+	return RetryDecision{}
+}
+
+// 5xx/网络错误 重试 退避时间 上限
+const maxRetryBackoff = 5 * time.Second
+
+/*
+DefaultRetryBackoff 默认退避策略：第 n 次（从 0 开始）重试前 等待 200ms * 2^n，最多 5s
+*/
+func DefaultRetryBackoff(n int) time.Duration {
+	delay := 200 * time.Millisecond
+	for i := 0; i < n; i++ {
+		delay *= 2
+		if delay >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return delay
+}
+
+// retryBackoff 第 n 次 5xx/网络错误 重试前的 等待时间
+func (offiAccount *OffiAccount) retryBackoff(n int) time.Duration {
+	if offiAccount.Config.RetryBackoff != nil {
+		return offiAccount.Config.RetryBackoff(n)
+	}
+	return DefaultRetryBackoff(n)
+}
+
+// isTransient 是否为 可重试的 临时错误：http 状态码 5xx 或 网络错误（请求被取消 除外）
+func isTransient(req *http.Request, httpStatus int, err error) bool {
+	if httpStatus >= http.StatusInternalServerError {
+		return true
+	}
+	return httpStatus == 0 && err != nil && req.Context().Err() == nil
+}
+
+// retryClassifier 当前使用的重试策略 默认 DefaultRetryClassifier
+func (offiAccount *OffiAccount) retryClassifier() RetryClassifier {
+	if offiAccount.Config.RetryClassifier != nil {
+		return offiAccount.Config.RetryClassifier
+	}
+	return DefaultRetryClassifier
+}
@@ -0,0 +1,250 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestRetryClassifier(t *testing.T) {
+	var tokens, calls int
+	var bodies []string
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + strconv.Itoa(tokens) + `","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/busy", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"errcode":45011,"errmsg":"api minute-quota reach limit"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	handler.HandleFunc("/cgi-bin/invalid", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") == "ACCESS_TOKEN_1" {
+			_, _ = w.Write([]byte(`{"errcode":40014,"errmsg":"invalid access_token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	// 默认策略 40014 刷新 access_token 后重试
+	app := New(Config{Appid: "TestRetryClassifier", Secret: "SECRET", BaseURL: svr.URL})
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	if _, err := app.Client.HTTPGet("/cgi-bin/invalid"); err != nil {
+		t.Fatalf("HTTPGet() error = %v, want retried with new token", err)
+	}
+	if tokens != 2 {
+		t.Errorf("token refreshed %d times, want 2", tokens)
+	}
+
+	// 默认策略 45011 不重试
+	if _, err := app.Client.HTTPPost("/cgi-bin/busy", strings.NewReader("PAYLOAD"), "text/plain"); err == nil {
+		t.Errorf("HTTPPost() want 45011 error with default classifier")
+	}
+
+	// 自定义策略 45011 等待后重试 不刷新 access_token
+	calls, bodies = 0, nil
+	var classified []int
+	app.Config.RetryClassifier = func(resp []byte, httpStatus int, err error) RetryDecision {
+		classified = append(classified, httpStatus)
+		if wxErr, _ := ParseWXError(resp); wxErr.Errcode == 45011 {
+			return RetryDecision{Retry: true, Delay: 10 * time.Millisecond}
+		}
+		return DefaultRetryClassifier(resp, httpStatus, err)
+	}
+	start := time.Now()
+	if _, err := app.Client.HTTPPost("/cgi-bin/busy", strings.NewReader("PAYLOAD"), "text/plain"); err != nil {
+		t.Fatalf("HTTPPost() error = %v, want retried", err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Errorf("retry did not wait for Delay")
+	}
+	if len(bodies) != 2 || bodies[1] != "PAYLOAD" {
+		t.Errorf("request bodies = %q, want payload resent on retry", bodies)
+	}
+	if tokens != 2 || len(classified) != 2 || classified[0] != http.StatusOK {
+		t.Errorf("tokens = %d classified = %v", tokens, classified)
+	}
+}
+
+func TestRetryDecision_MaxRetries(t *testing.T) {
+	var calls int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/busy", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system error"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := New(Config{
+		Appid:   "TestRetryDecision_MaxRetries",
+		BaseURL: svr.URL,
+		RetryClassifier: func(resp []byte, httpStatus int, err error) RetryDecision {
+			return RetryDecision{Retry: true, MaxRetries: 3}
+		},
+	}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	if _, err := app.Client.HTTPGet("/cgi-bin/busy"); err == nil {
+		t.Errorf("HTTPGet() want error after retries")
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+}
+
+func TestConfig_MaxRetries(t *testing.T) {
+	var calls int
+	var bodies []string
+	var statuses []int
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"NEW_TOKEN","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/flaky", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		status := http.StatusOK
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		switch {
+		case status == 40001:
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+		case status != http.StatusOK:
+			w.WriteHeader(status)
+		default:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		}
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	var backoffs []int
+	newApp := func(maxRetries int) *OffiAccount {
+		app := New(Config{
+			Appid:      "TestConfig_MaxRetries",
+			Secret:     "SECRET",
+			BaseURL:    svr.URL,
+			MaxRetries: maxRetries,
+			RetryBackoff: func(n int) time.Duration {
+				backoffs = append(backoffs, n)
+				return time.Millisecond
+			},
+		}, WithStaticToken("ACCESS_TOKEN"))
+		app.SetLogger(nil)
+		return app
+	}
+	reset := func(s ...int) {
+		calls, bodies, backoffs, statuses = 0, nil, nil, s
+	}
+
+	// 失败两次后 成功
+	reset(http.StatusBadGateway, http.StatusInternalServerError)
+	resp, err := newApp(2).Client.HTTPPost("/cgi-bin/flaky", strings.NewReader(`{"a":1}`), "application/json")
+	if err != nil || string(resp) != `{"errcode":0,"errmsg":"ok"}` {
+		t.Fatalf("HTTPPost() = %s, %v", resp, err)
+	}
+	if calls != 3 || len(backoffs) != 2 || backoffs[0] != 0 || backoffs[1] != 1 || bodies[2] != `{"a":1}` {
+		t.Errorf("calls = %d, backoffs = %v, bodies = %v", calls, backoffs, bodies)
+	}
+
+	// 默认 不重试
+	reset(http.StatusBadGateway)
+	if _, err = newApp(0).Client.HTTPGet("/cgi-bin/flaky"); err == nil || calls != 1 {
+		t.Errorf("HTTPGet() error = %v, calls = %d, want no retry", err, calls)
+	}
+
+	// 超过 MaxRetries
+	reset(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	if _, err = newApp(1).Client.HTTPGet("/cgi-bin/flaky"); err == nil || calls != 2 {
+		t.Errorf("HTTPGet() error = %v, calls = %d, want 2 calls", err, calls)
+	}
+
+	// 4xx 不重试
+	reset(http.StatusNotFound)
+	if _, err = newApp(2).Client.HTTPGet("/cgi-bin/flaky"); err == nil || calls != 1 {
+		t.Errorf("HTTPGet() error = %v, calls = %d, want no retry on 4xx", err, calls)
+	}
+
+	// access_token 无效 刷新重试 不占用 MaxRetries
+	reset(40001, http.StatusServiceUnavailable)
+	if _, err = newApp(1).Client.HTTPGet("/cgi-bin/flaky"); err != nil || calls != 3 || len(backoffs) != 1 {
+		t.Errorf("HTTPGet() error = %v, calls = %d, backoffs = %v", err, calls, backoffs)
+	}
+}
+
+func TestHTTPDo_StaleResponse(t *testing.T) {
+	var calls int32
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"NEW_TOKEN","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		// 刷新重试时 连接断开
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestHTTPDo_StaleResponse", Secret: "SECRET", BaseURL: svr.URL})
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	resp, err := app.Client.HTTPPost("/cgi-bin/flaky", strings.NewReader(`{}`), "application/json")
+	if err == nil || err == ErrorAccessTokenExpire {
+		t.Errorf("HTTPPost() error = %v, want transport error", err)
+	}
+	if _, ok := AsWXError(err); ok {
+		t.Errorf("HTTPPost() error = %v, want transport error", err)
+	}
+	if resp != nil {
+		t.Errorf("HTTPPost() resp = %s, want nil", resp)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+}
+
+func TestDefaultRetryBackoff(t *testing.T) {
+	for n, want := range []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, 1600 * time.Millisecond, 3200 * time.Millisecond, 5 * time.Second, 5 * time.Second} {
+		if got := DefaultRetryBackoff(n); got != want {
+			t.Errorf("DefaultRetryBackoff(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
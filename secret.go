@@ -0,0 +1,77 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+/*
+secrets 获取 access_token 可用的 AppSecret 列表，配置了 Config.Secrets 时 优先使用
+*/
+func (offiAccount *OffiAccount) secrets() []string {
+	if len(offiAccount.Config.Secrets) > 0 {
+		return offiAccount.Config.Secrets
+	}
+	return []string{offiAccount.Config.Secret}
+}
+
+/*
+Secret 当前使用的 AppSecret
+
+配置了 Config.Secrets 时 为 最近一次成功获取 access_token 的密钥，尚未获取过时 为 第一个
+*/
+func (offiAccount *OffiAccount) Secret() string {
+	secrets := offiAccount.secrets()
+
+	offiAccount.secretLock.RLock()
+	index := offiAccount.secretIndex
+	offiAccount.secretLock.RUnlock()
+
+	if index >= len(secrets) {
+		index = 0
+	}
+	return secrets[index]
+}
+
+/*
+refreshWithSecrets 依次使用 Config.Secrets 中的密钥 调用 fetch，直到成功 并记录 成功的密钥
+
+从 上次成功的密钥 开始尝试；遇到 临时错误 (retryable) 时 不切换密钥，直接返回 由调用方重试
+*/
+func (offiAccount *OffiAccount) refreshWithSecrets(fetch func(secret string) (TokenRefreshResult, bool, error)) (result TokenRefreshResult, retryable bool, err error) {
+	secrets := offiAccount.secrets()
+
+	offiAccount.secretLock.RLock()
+	start := offiAccount.secretIndex
+	offiAccount.secretLock.RUnlock()
+
+	for i := 0; i < len(secrets); i++ {
+		index := (start + i) % len(secrets)
+		result, retryable, err = fetch(secrets[index])
+		if err == nil {
+			if index != start {
+				offiAccount.secretLock.Lock()
+				offiAccount.secretIndex = index
+				offiAccount.secretLock.Unlock()
+
+				if offiAccount.Logger != nil {
+					offiAccount.Logger.Printf("refreshAccessTokenFromWXServer switched to secret #%d", index)
+				}
+			}
+			return
+		}
+		if retryable {
+			return
+		}
+	}
+	return
+}
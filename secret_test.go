@@ -0,0 +1,74 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfig_Secrets(t *testing.T) {
+	valid := "NEW_SECRET"
+	var tried []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := r.URL.Query().Get("secret")
+		tried = append(tried, secret)
+		if secret != valid {
+			_, _ = w.Write([]byte(`{"errcode":40125,"errmsg":"invalid appsecret"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN_` + secret + `","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestConfig_Secrets", Secrets: []string{"OLD_SECRET", "NEW_SECRET"}, BaseURL: svr.URL})
+	app.SetLogger(nil)
+
+	if secret := app.Secret(); secret != "OLD_SECRET" {
+		t.Errorf("Secret() before refresh = %s, want OLD_SECRET", secret)
+	}
+
+	result, err := refreshAccessToken(app)
+	if err != nil || result.AccessToken != "ACCESS_TOKEN_NEW_SECRET" {
+		t.Fatalf("refreshAccessToken() = %+v, %v", result, err)
+	}
+	if len(tried) != 2 || tried[0] != "OLD_SECRET" || tried[1] != "NEW_SECRET" {
+		t.Errorf("tried secrets = %v", tried)
+	}
+	if secret := app.Secret(); secret != "NEW_SECRET" {
+		t.Errorf("Secret() = %s, want NEW_SECRET", secret)
+	}
+
+	// 之后 直接使用 成功的密钥
+	tried = nil
+	if _, err = refreshAccessToken(app); err != nil || len(tried) != 1 || tried[0] != "NEW_SECRET" {
+		t.Errorf("refreshAccessToken() err = %v, tried = %v", err, tried)
+	}
+
+	// 全部失败
+	valid = ""
+	tried = nil
+	if _, err = refreshAccessToken(app); err == nil || len(tried) != 2 {
+		t.Errorf("refreshAccessToken() err = %v, tried = %v, want error after 2 secrets", err, tried)
+	}
+}
+
+func TestOffiAccount_Secret(t *testing.T) {
+	app := New(Config{Appid: "TestOffiAccount_Secret", Secret: "SECRET"})
+	if secret := app.Secret(); secret != "SECRET" {
+		t.Errorf("Secret() = %s, want SECRET", secret)
+	}
+}
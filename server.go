@@ -15,41 +15,62 @@
 package offiaccount
 
 import (
-	"crypto/sha1"
 	"encoding/xml"
-	"fmt"
+	"errors"
 	"io"
 	"net/http"
-	"sort"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/fastwego/offiaccount/sign"
 	eventtype "github.com/fastwego/offiaccount/type/type_event"
 	messagetype "github.com/fastwego/offiaccount/type/type_message"
 
 	"github.com/fastwego/offiaccount/util"
 )
 
+// ErrorToUserNameMismatch 推送消息的 ToUserName 与 Config.GhId 不一致
+var ErrorToUserNameMismatch = errors.New("ToUserName mismatch")
+
 /*
 响应微信请求 或 推送消息/事件 的服务器
 */
 type Server struct {
 	Ctx *OffiAccount
+
+	token          string // NewServer 指定 为空时 使用 Ctx.Config.Token
+	encodingAESKey string // NewServer 指定 为空时 使用 Ctx.Config.EncodingAESKey
+	handlers       map[string]HandlerFunc
+
+	messagesLock   sync.RWMutex
+	messages       chan InboundMessage // 见 Messages()
+	messagesClosed bool                // 见 Close()
+
+	recentLock   sync.Mutex
+	recent       map[string]time.Time // 最近推送的消息 用于去重
+	recentPruned time.Time
 }
 
-// EchoStr 服务器接口校验
-func (s *Server) EchoStr(writer http.ResponseWriter, request *http.Request) {
-	strs := []string{
-		request.URL.Query().Get("timestamp"),
-		request.URL.Query().Get("nonce"),
-		s.Ctx.Config.Token,
+// getToken 校验签名 使用的 Token
+func (s *Server) getToken() string {
+	if s.token != "" {
+		return s.token
 	}
-	sort.Strings(strs)
+	return s.Ctx.Config.Token
+}
 
-	h := sha1.New()
-	_, _ = io.WriteString(h, strings.Join(strs, ""))
-	signature := fmt.Sprintf("%x", h.Sum(nil))
+// getEncodingAESKey 加解密消息 使用的 EncodingAESKey
+func (s *Server) getEncodingAESKey() string {
+	if s.encodingAESKey != "" {
+		return s.encodingAESKey
+	}
+	return s.Ctx.Config.EncodingAESKey
+}
+
+// EchoStr 服务器接口校验
+func (s *Server) EchoStr(writer http.ResponseWriter, request *http.Request) {
+	signature := sign.ServerURLSign(s.getToken(), request.URL.Query().Get("timestamp"), request.URL.Query().Get("nonce"))
 
 	echoStr := request.URL.Query().Get("echostr")
 	if echoStr != "" && signature == request.URL.Query().Get("signature") {
@@ -63,6 +84,15 @@ func (s *Server) EchoStr(writer http.ResponseWriter, request *http.Request) {
 
 // ParseXML 解析微信推送过来的消息/事件
 func (s *Server) ParseXML(body []byte) (m interface{}, err error) {
+	body, encrypted, err := s.decryptXML(body)
+	if err != nil {
+		return
+	}
+	return s.parseMessage(body, encrypted, encrypted)
+}
+
+// decryptXML 加密消息 解密后返回明文 XML，明文消息 原样返回
+func (s *Server) decryptXML(body []byte) (plain []byte, encrypted bool, err error) {
 
 	if s.Ctx.Logger != nil {
 		s.Ctx.Logger.Println(string(body))
@@ -74,22 +104,28 @@ func (s *Server) ParseXML(body []byte) (m interface{}, err error) {
 	if err != nil {
 		return
 	}
+	if encryptMsg.Encrypt == "" {
+		return body, false, nil
+	}
 
 	// 需要解密
-	if encryptMsg.Encrypt != "" {
-		var xmlMsg []byte
-		_, xmlMsg, _, err = util.AESDecryptMsg(encryptMsg.Encrypt, s.Ctx.Config.EncodingAESKey)
-		if err != nil {
-			return
-		}
-		body = xmlMsg
-
-		if s.Ctx.Logger != nil {
-			s.Ctx.Logger.Println("AESDecryptMsg ", string(body))
-		}
+	_, plain, _, err = util.AESDecryptMsg(encryptMsg.Encrypt, s.getEncodingAESKey())
+	if err != nil {
+		return
+	}
 
+	if s.Ctx.Logger != nil {
+		s.Ctx.Logger.Println("AESDecryptMsg ", string(plain))
 	}
+	return plain, true, nil
+}
 
+/*
+parseMessage 解析 明文 XML 为 消息/事件 类型
+
+trusted 表示 消息 是否可信（已校验签名 或 已解密），只从 可信消息 记录 公众号原始ID（见 GhId）
+*/
+func (s *Server) parseMessage(body []byte, encrypted bool, trusted bool) (m interface{}, err error) {
 	message := messagetype.Message{}
 	err = xml.Unmarshal(body, &message)
 	//fmt.Println(message)
@@ -97,6 +133,18 @@ func (s *Server) ParseXML(body []byte) (m interface{}, err error) {
 		return
 	}
 
+	// 明文模式 消息体没有加密保护 校验 ToUserName 是否为当前公众号
+	if !encrypted && s.Ctx.Config.GhId != "" && message.ToUserName != s.Ctx.Config.GhId {
+		if s.Ctx.Logger != nil {
+			s.Ctx.Logger.Printf("warning: ToUserName %s mismatch GhId %s", message.ToUserName, s.Ctx.Config.GhId)
+		}
+		err = ErrorToUserNameMismatch
+		return
+	}
+	if trusted {
+		s.Ctx.observeGhId(message.ToUserName)
+	}
+
 	switch message.MsgType {
 	case messagetype.MsgTypeText:
 		msg := messagetype.MessageText{}
@@ -418,12 +466,105 @@ func parseEvent(body []byte) (m interface{}, err error) {
 			return
 		}
 		return msg, nil
+
+		// 图文发布任务完成
+	case eventtype.EventTypePublishJobFinish:
+		msg := eventtype.EventPublishJobFinish{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 订阅通知 弹窗
+	case eventtype.EventTypeSubscribeMsgPopup:
+		msg := eventtype.EventSubscribeMsgPopup{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 异步校验图片/音频 结果
+	case eventtype.EventTypeWxaMediaCheck:
+		msg := eventtype.EventWxaMediaCheck{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 群发任务完成
+	case eventtype.EventTypeMassSendJobFinish:
+		msg := eventtype.EventMassSendJobFinish{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 客服会话事件
+	case eventtype.EventTypeKfCreateSession:
+		msg := eventtype.EventKfCreateSession{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case eventtype.EventTypeKfCloseSession:
+		msg := eventtype.EventKfCloseSession{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case eventtype.EventTypeKfSwitchSession:
+		msg := eventtype.EventKfSwitchSession{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 摇一摇周边
+	case eventtype.EventTypeShakearoundUserShake:
+		msg := eventtype.EventShakearoundUserShake{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+
+		// 门店
+	case eventtype.EventTypePoiCheckNotify:
+		msg := eventtype.EventPoiCheckNotify{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case eventtype.EventTypeWifiConnected:
+		msg := eventtype.EventWifiConnected{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
 	}
 
 	return
 }
 
-// Response 响应微信消息 (自动判断是否要加密)
+// isNoReply reply 为 nil 或 messagetype.NoReply() 时 不回复用户
+func isNoReply(reply interface{}) bool {
+	switch reply.(type) {
+	case nil, messagetype.NoReplyMessage, *messagetype.NoReplyMessage:
+		return true
+	}
+	return false
+}
+
+// Response 响应微信消息 (自动判断是否要加密)，reply 为 nil 或 messagetype.NoReply() 时 输出 success
 func (s *Server) Response(writer http.ResponseWriter, request *http.Request, reply interface{}) (err error) {
 
 	// 如果 开启加密，微信服务器 发过来的请求 带有 如下参数
@@ -434,15 +575,15 @@ func (s *Server) Response(writer http.ResponseWriter, request *http.Request, rep
 	//&encrypt_type=aes
 	//&msg_signature=cc24cc38467417603fc3689170e8b0fd3c9bf4a2
 
-	output := []byte("success") // 默认回复
-	if reply != nil {
+	output := []byte(messagetype.ReplySuccess) // 默认回复
+	if !isNoReply(reply) {
 		output, err = xml.Marshal(reply)
 		if err != nil {
 			return
 		}
 
 		// 加密
-		if request.URL.Query().Get("encrypt_type") == "aes" {
+		if request.URL.Query().Get("encrypt_type") == messagetype.EncryptTypeAES {
 			message := s.encryptReplyMessage(output)
 			output, err = xml.Marshal(message)
 			if err != nil {
@@ -462,24 +603,13 @@ func (s *Server) Response(writer http.ResponseWriter, request *http.Request, rep
 
 // encryptReplyMessage 加密回复消息
 func (s *Server) encryptReplyMessage(rawXmlMsg []byte) (replyEncryptMessage messagetype.ReplyEncryptMessage) {
-	cipherText := util.AESEncryptMsg([]byte(util.GetRandString(16)), rawXmlMsg, s.Ctx.Config.Appid, s.Ctx.Config.EncodingAESKey)
+	cipherText := util.AESEncryptMsg(util.AESRandomPrefix(), rawXmlMsg, s.Ctx.Config.Appid, s.getEncodingAESKey())
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 	nonce := util.GetRandString(6)
 
-	strs := []string{
-		timestamp,
-		nonce,
-		s.Ctx.Config.Token,
-		cipherText,
-	}
-	sort.Strings(strs)
-	h := sha1.New()
-	_, _ = io.WriteString(h, strings.Join(strs, ""))
-	signature := fmt.Sprintf("%x", h.Sum(nil))
-
 	return messagetype.ReplyEncryptMessage{
 		Encrypt:      cipherText,
-		MsgSignature: signature,
+		MsgSignature: sign.MsgSign(s.getToken(), timestamp, nonce, cipherText),
 		TimeStamp:    timestamp,
 		Nonce:        nonce,
 	}
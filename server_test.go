@@ -15,6 +15,7 @@
 package offiaccount
 
 import (
+	"crypto/sha1"
 	"encoding/xml"
 	"fmt"
 	"net/http"
@@ -22,6 +23,8 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/fastwego/offiaccount/type/type_event"
@@ -102,6 +105,123 @@ func TestServer_ParseXML(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "poi_check_notify",
+			args: args{body: []byte(`
+			<xml>
+			  <ToUserName><![CDATA[toUser]]></ToUserName>
+			  <FromUserName><![CDATA[fromUser]]></FromUserName>
+			  <CreateTime>1408622107</CreateTime>
+			  <MsgType><![CDATA[event]]></MsgType>
+			  <Event><![CDATA[poi_check_notify]]></Event>
+			  <UniqId><![CDATA[123adb]]></UniqId>
+			  <PoiId><![CDATA[123123]]></PoiId>
+			  <Result><![CDATA[succ]]></Result>
+			  <Msg><![CDATA[]]></Msg>
+			</xml>
+			`)},
+			wantM: type_event.EventPoiCheckNotify{
+				Event: type_event.Event{
+					Message: type_message.Message{
+						ToUserName:   "toUser",
+						FromUserName: "fromUser",
+						CreateTime:   "1408622107",
+						MsgType:      "event",
+					},
+					Event: "poi_check_notify",
+				},
+				UniqId: "123adb",
+				PoiId:  "123123",
+				Result: "succ",
+			},
+			wantErr: false,
+		},
+		{
+			name: "wxa_media_check",
+			args: args{body: []byte(`
+			<xml>
+			  <ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName>
+			  <FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName>
+			  <CreateTime>1626959646</CreateTime>
+			  <MsgType><![CDATA[event]]></MsgType>
+			  <Event><![CDATA[wxa_media_check]]></Event>
+			  <appid><![CDATA[wx8f16a5e5479f8e43]]></appid>
+			  <trace_id><![CDATA[60f96f1d-3845297a-1976a3ee]]></trace_id>
+			  <version>2</version>
+			  <errcode>0</errcode>
+			  <errmsg><![CDATA[ok]]></errmsg>
+			  <result>
+			    <suggest><![CDATA[risky]]></suggest>
+			    <label>20002</label>
+			  </result>
+			</xml>
+			`)},
+			wantM: func() type_event.EventWxaMediaCheck {
+				event := type_event.EventWxaMediaCheck{
+					Event: type_event.Event{
+						Message: type_message.Message{
+							ToUserName:   "gh_38cc49f9733b",
+							FromUserName: "oH1fu0FdHqpToe2T6gBj0WyB8iS1",
+							CreateTime:   "1626959646",
+							MsgType:      "event",
+						},
+						Event: "wxa_media_check",
+					},
+					Appid:   "wx8f16a5e5479f8e43",
+					TraceId: "60f96f1d-3845297a-1976a3ee",
+					Version: "2",
+					Errcode: "0",
+					Errmsg:  "ok",
+				}
+				event.Result.Suggest = "risky"
+				event.Result.Label = "20002"
+				return event
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "subscribe_msg_popup_event",
+			args: args{body: []byte(`
+			<xml>
+			  <ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+			  <FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+			  <CreateTime>1610969440</CreateTime>
+			  <MsgType><![CDATA[event]]></MsgType>
+			  <Event><![CDATA[subscribe_msg_popup_event]]></Event>
+			  <SubscribeMsgPopupEvent>
+			    <List>
+			      <TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+			      <SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString>
+			      <PopupScene>2</PopupScene>
+			    </List>
+			    <List>
+			      <TemplateId><![CDATA[9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI]]></TemplateId>
+			      <SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+			      <PopupScene>2</PopupScene>
+			    </List>
+			  </SubscribeMsgPopupEvent>
+			</xml>
+			`)},
+			wantM: func() type_event.EventSubscribeMsgPopup {
+				event := type_event.EventSubscribeMsgPopup{
+					Event: type_event.Event{
+						Message: type_message.Message{
+							ToUserName:   "gh_123456789abc",
+							FromUserName: "otFpruAK8D-E6EfStSYonYSBZ8_4",
+							CreateTime:   "1610969440",
+							MsgType:      "event",
+						},
+						Event: "subscribe_msg_popup_event",
+					},
+				}
+				event.SubscribeMsgPopupEvent.List = []type_event.SubscribeMsgPopupItem{
+					{TemplateId: "VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc", SubscribeStatusString: "accept", PopupScene: "2"},
+					{TemplateId: "9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI", SubscribeStatusString: "reject", PopupScene: "2"},
+				}
+				return event
+			}(),
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,15 +238,44 @@ func TestServer_ParseXML(t *testing.T) {
 	}
 }
 
+func TestServer_ParseXML_GhId(t *testing.T) {
+	ctx := New(Config{GhId: "gh_7f083739789a"})
+	ctx.SetLogger(nil)
+
+	body := []byte(`<xml><ToUserName><![CDATA[%s]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[this is a test]]></Content><MsgId>1234567890123456</MsgId></xml>`)
+
+	_, err := ctx.Server.ParseXML([]byte(fmt.Sprintf(string(body), "gh_7f083739789a")))
+	if err != nil {
+		t.Errorf("ParseXML() error = %v", err)
+	}
+
+	_, err = ctx.Server.ParseXML([]byte(fmt.Sprintf(string(body), "gh_other")))
+	if err != ErrorToUserNameMismatch {
+		t.Errorf("ParseXML() error = %v, want %v", err, ErrorToUserNameMismatch)
+	}
+
+	_, err = ctx.Server.ParseXML([]byte(`<xml><ToUserName>`))
+	if err == nil {
+		t.Errorf("ParseXML() want error on broken xml")
+	}
+}
+
 func TestReplyMessage(t *testing.T) {
 	tests := []struct {
 		name     string
+		content  type_message.CDATA
 		wantEcho string
 	}{
 		{
 			name:     "case1",
+			content:  "你好",
 			wantEcho: `<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>12345678</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[你好]]></Content></xml>`,
 		},
+		{
+			name:     "cdata end and emoji",
+			content:  "a]]>b 😀\x01",
+			wantEcho: `<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>12345678</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[a]]]]><![CDATA[>b 😀]]></Content></xml>`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -138,7 +287,7 @@ func TestReplyMessage(t *testing.T) {
 					CreateTime:   "12345678",
 					MsgType:      type_message.ReplyMsgTypeText,
 				},
-				Content: "你好",
+				Content: tt.content,
 			}
 			data, err := xml.Marshal(msg)
 			fmt.Println(string(data), err)
@@ -146,6 +295,17 @@ func TestReplyMessage(t *testing.T) {
 			if tt.wantEcho != string(data) {
 				t.Errorf("\nwant %s \nget %s", tt.wantEcho, string(data))
 			}
+
+			// 输出的 XML 可以被正确解析
+			got := type_message.MessageText{}
+			if err = xml.Unmarshal(data, &got); err != nil || got.Content != strings.Map(func(r rune) rune {
+				if r < 0x20 {
+					return -1
+				}
+				return r
+			}, string(tt.content)) {
+				t.Errorf("xml.Unmarshal() = %q, %v", got.Content, err)
+			}
 		})
 	}
 }
@@ -208,3 +368,71 @@ func TestServer_EchoStr(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_Response_NoReply(t *testing.T) {
+	ctx := New(Config{Appid: "wx45f133bf6fce646e"})
+	ctx.SetLogger(nil)
+
+	for _, reply := range []interface{}{nil, type_message.NoReply(), &type_message.NoReplyMessage{}} {
+		// 加密模式 同样 不加密 直接输出 success
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/?encrypt_type=aes", nil)
+		if err := ctx.Server.Response(w, r, reply); err != nil {
+			t.Fatal(err)
+		}
+		if w.Body.String() != type_message.ReplySuccess {
+			t.Errorf("Response(%#v) body = %q, want success", reply, w.Body.String())
+		}
+	}
+}
+
+func TestServer_Response_AES(t *testing.T) {
+	ctx := New(Config{
+		Appid:          "wx45f133bf6fce646e",
+		Token:          "TOKEN",
+		EncodingAESKey: "AdiqDDDvUNCeE1ZW5XJmjf9fqNBJpGBs4vL4cHKmHBS",
+	})
+
+	reply := type_message.ReplyMessageText{
+		ReplyMessage: type_message.ReplyMessage{
+			ToUserName:   "toUser",
+			FromUserName: "fromUser",
+			CreateTime:   "12345678",
+			MsgType:      type_message.ReplyMsgTypeText,
+		},
+		Content: "你好 😀",
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/?encrypt_type=aes", nil)
+	if err := ctx.Server.Response(w, r, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	envelope := struct {
+		Encrypt      string
+		MsgSignature string
+		TimeStamp    string
+		Nonce        string
+	}{}
+	if err := xml.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	// 校验 签名
+	strs := []string{envelope.TimeStamp, envelope.Nonce, ctx.Config.Token, envelope.Encrypt}
+	sort.Strings(strs)
+	if signature := fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(strs, "")))); signature != envelope.MsgSignature {
+		t.Errorf("MsgSignature = %s, want %s", envelope.MsgSignature, signature)
+	}
+
+	// 解密 还原
+	m, err := ctx.Server.ParseXML(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := m.(type_message.MessageText)
+	if !ok || got.Content != string(reply.Content) || got.ToUserName != string(reply.ToUserName) || got.FromUserName != string(reply.FromUserName) {
+		t.Errorf("ParseXML() = %#v, want content %s", m, reply.Content)
+	}
+}
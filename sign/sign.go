@@ -0,0 +1,101 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package sign 微信 签名算法
+
+微信使用两种 sha1 签名：
+
+- 字符串排序：将参数值 按字典序排序 后直接拼接，如 服务器地址验证（ServerURLSign）、消息体签名（MsgSign）、卡券 cardExt
+
+- 键值对排序：将 key=value 按 key 的字典序排序 后用 & 拼接，如 JS-SDK 权限验证配置（JSAPISign）
+*/
+package sign
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SortedSign 字符串排序 签名：参数值 字典序排序 后拼接 sha1
+func SortedSign(strs ...string) string {
+	sorted := append([]string(nil), strs...)
+	sort.Strings(sorted)
+
+	h := sha1.New()
+	_, _ = io.WriteString(h, strings.Join(sorted, ""))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+/*
+QuerySign 键值对排序 签名：key=value 按 key 字典序排序 后用 & 拼接 sha1
+
+key 区分大小写，value 不做 url 编码
+*/
+func QuerySign(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+params[key])
+	}
+
+	h := sha1.New()
+	_, _ = io.WriteString(h, strings.Join(pairs, "&"))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+/*
+ServerURLSign 服务器地址验证 及 消息推送 的 signature 参数
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Basic_Information/Access_Overview.html
+*/
+func ServerURLSign(token, timestamp, nonce string) string {
+	return SortedSign(token, timestamp, nonce)
+}
+
+/*
+MsgSign 安全模式下 加密消息 的 msg_signature 参数，encrypt 为 消息体中的 Encrypt 密文
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Message_encryption_and_decryption_instructions.html
+*/
+func MsgSign(token, timestamp, nonce, encrypt string) string {
+	return SortedSign(token, timestamp, nonce, encrypt)
+}
+
+/*
+JSAPISign JS-SDK 权限验证配置（wx.config）的 signature
+
+url 为 当前网页的 URL，不包含 # 及其后面部分
+
+See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#62
+*/
+func JSAPISign(jsapiTicket, nonceStr, timestamp, url string) string {
+	if i := strings.IndexByte(url, '#'); i >= 0 {
+		url = url[:i]
+	}
+	return QuerySign(map[string]string{
+		"jsapi_ticket": jsapiTicket,
+		"noncestr":     nonceStr,
+		"timestamp":    timestamp,
+		"url":          url,
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"testing"
+)
+
+func TestServerURLSign(t *testing.T) {
+	if got := ServerURLSign("TOKEN", "1526545852", "nonce"); got != "7aa016688a328036de9ea9164ee00f9fa581da5f" {
+		t.Errorf("ServerURLSign() = %s", got)
+	}
+	// 与 参数顺序 无关
+	if ServerURLSign("TOKEN", "1526545852", "nonce") != SortedSign("nonce", "TOKEN", "1526545852") {
+		t.Error("SortedSign() depends on argument order")
+	}
+}
+
+func TestMsgSign(t *testing.T) {
+	got := MsgSign("pamtest", "1409304348", "xxxxxx", "msg_encrypt")
+	if want := "5bc3af02957a2e0ef0cfede3324f33c777ed6040"; got != want {
+		t.Errorf("MsgSign() = %s, want %s", got, want)
+	}
+}
+
+func TestJSAPISign(t *testing.T) {
+	// 官方 JS-SDK 签名 示例 See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#62
+	ticket := "sM4AOVdWfPE4DxkXGEs8VMCPGGVi4C3VM0P37wVUCFvkVAy_90u5h9nbSlYy3-Sl-HhTdfl2fzFy1AOcHKP7qg"
+	want := "0f9de62fce790f9a083d5c99e95740ceb90c27ed"
+
+	if got := JSAPISign(ticket, "Wm3WZYTPz0wzccnW", "1414587457", "http://mp.weixin.qq.com?params=value"); got != want {
+		t.Errorf("JSAPISign() = %s, want %s", got, want)
+	}
+	// # 及其后面部分 不参与签名
+	if got := JSAPISign(ticket, "Wm3WZYTPz0wzccnW", "1414587457", "http://mp.weixin.qq.com?params=value#wechat_redirect"); got != want {
+		t.Errorf("JSAPISign() with fragment = %s, want %s", got, want)
+	}
+}
+
+func TestQuerySign(t *testing.T) {
+	got := QuerySign(map[string]string{"b": "2", "a": "1"})
+	if want := SortedSign("a=1&b=2"); got != want {
+		t.Errorf("QuerySign() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+ServerTimeSkew 最近一次观测到的 微信服务器时间 与 本地时间 的偏差（服务器时间 - 本地时间）
+
+根据接口响应的 Date 头计算，精度为 秒；尚未发起过请求时 返回 0
+
+本地时钟偏差过大 会导致签名校验失败，可以定期检查 并告警
+*/
+func (offiAccount *OffiAccount) ServerTimeSkew() time.Duration {
+	offiAccount.skewLock.RLock()
+	defer offiAccount.skewLock.RUnlock()
+	return offiAccount.serverTimeSkew
+}
+
+// ServerTime 按 ServerTimeSkew 校正后的 当前时间 可用于生成签名所需的时间戳
+func (offiAccount *OffiAccount) ServerTime() time.Time {
+	return time.Now().Add(offiAccount.ServerTimeSkew())
+}
+
+// observeServerTime 根据响应的 Date 头 更新时间偏差
+func (offiAccount *OffiAccount) observeServerTime(response *http.Response) {
+	date := serverTimeOf(response)
+	if date.IsZero() {
+		return
+	}
+	offiAccount.setServerTimeSkew(time.Until(date).Truncate(time.Second))
+}
+
+func (offiAccount *OffiAccount) setServerTimeSkew(skew time.Duration) {
+	offiAccount.skewLock.Lock()
+	offiAccount.serverTimeSkew = skew
+	offiAccount.skewLock.Unlock()
+}
+
+// serverTimeOf 解析响应的 Date 头，没有或格式错误时 返回零值
+func serverTimeOf(response *http.Response) time.Time {
+	date, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
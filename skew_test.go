@@ -0,0 +1,90 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOffiAccount_ServerTimeSkew(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid: "TestOffiAccount_ServerTimeSkew",
+	}, WithStaticToken("ACCESS_TOKEN"))
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	// 模拟 微信服务器 时间快 1 小时
+	MockSvrHandler.HandleFunc("/cgi-bin/menu/get", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	if skew := MockOffiAccount.ServerTimeSkew(); skew != 0 {
+		t.Errorf("ServerTimeSkew() before request = %s, want 0", skew)
+	}
+
+	if _, err := MockOffiAccount.Client.HTTPGet("/cgi-bin/menu/get"); err != nil {
+		t.Fatal(err)
+	}
+
+	skew := MockOffiAccount.ServerTimeSkew()
+	if skew < time.Hour-2*time.Second || skew > time.Hour {
+		t.Errorf("ServerTimeSkew() = %s, want about 1h", skew)
+	}
+	if d := time.Until(MockOffiAccount.ServerTime()); d < time.Hour-3*time.Second || d > time.Hour {
+		t.Errorf("ServerTime() is %s ahead, want about 1h", d)
+	}
+}
+
+func TestNoticeRefreshAccessTokenWithResult(t *testing.T) {
+	var code string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 模拟 微信服务器 时间慢 1 小时
+		w.Header().Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(code))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestNoticeRefreshAccessTokenWithResult", BaseURL: svr.URL})
+	app.SetLogger(nil)
+
+	// 刷新失败 仍然返回 服务器时间
+	code = `{"errcode":40164,"errmsg":"invalid ip"}`
+	result, err := NoticeRefreshAccessTokenWithResult(app)
+	if err == nil {
+		t.Fatal("NoticeRefreshAccessTokenWithResult() want error")
+	}
+	if skew := result.Skew(); skew > -time.Hour+2*time.Second || skew < -time.Hour-time.Second {
+		t.Errorf("result.Skew() = %s, want about -1h", skew)
+	}
+	if skew := app.ServerTimeSkew(); skew > -time.Hour+2*time.Second || skew < -time.Hour-time.Second {
+		t.Errorf("ServerTimeSkew() = %s, want about -1h", skew)
+	}
+
+	code = `{"access_token":"ACCESS_TOKEN","expires_in":7200}`
+	result, err = NoticeRefreshAccessTokenWithResult(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AccessToken != "ACCESS_TOKEN" || result.ExpiresAt() != result.FetchedAt.Add(7200*time.Second) || result.ServerTime.IsZero() {
+		t.Errorf("NoticeRefreshAccessTokenWithResult() = %+v", result)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const apiStableToken = "/cgi-bin/stable_token"
+
+/*
+ForceRefreshStableToken 强制刷新 稳定版 access_token 并更新缓存
+
+force_refresh 模式下 旧 token 立即失效，适用于 access_token 泄露 等需要立即轮换的场景；该模式 每天限用 20 次，30 秒内只能使用一次
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Basic_Information/getStableAccessToken.html
+*/
+func ForceRefreshStableToken(ctx *OffiAccount) (accessToken string, err error) {
+	lock := refreshAccessTokenLock(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+
+	result, _, err := refreshStableTokenFromWXServer(ctx.Client.getHTTPClient(), ctx.ServerUrlFor(apiStableToken), ctx.Config.Appid, ctx.Secret(), true)
+	if !result.ServerTime.IsZero() {
+		ctx.setServerTimeSkew(result.Skew())
+	}
+	if err != nil {
+		return
+	}
+	accessToken = result.AccessToken
+
+	err = ctx.saveAccessToken(accessToken, result.ExpiresIn)
+
+	if ctx.Logger != nil {
+		ctx.Logger.Printf("%s %s %d\n", "ForceRefreshStableToken", accessToken, result.ExpiresIn)
+	}
+	return
+}
+
+/*
+从微信服务器获取 稳定版 AccessToken
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Basic_Information/getStableAccessToken.html
+*/
+func refreshStableTokenFromWXServer(httpClient *http.Client, serverUrl string, appid string, secret string, forceRefresh bool) (result TokenRefreshResult, retryable bool, err error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"grant_type":    "client_credential",
+		"appid":         appid,
+		"secret":        secret,
+		"force_refresh": forceRefresh,
+	})
+	if err != nil {
+		return
+	}
+
+	url := JoinUrl(serverUrl, apiStableToken)
+	result.FetchedAt = time.Now()
+	response, err := httpClient.Post(url, "application/json;charset=utf-8", bytes.NewReader(payload))
+	if err != nil {
+		retryable = true
+		return
+	}
+
+	defer response.Body.Close()
+	result.ServerTime = serverTimeOf(response)
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("POST %s RETURN %s", url, response.Status)
+		retryable = true
+		return
+	}
+
+	result.AccessToken, result.ExpiresIn, retryable, err = parseAccessTokenResponse(response)
+	return
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestStableToken(t *testing.T) {
+	var requests []map[string]interface{}
+	handler := http.NewServeMux()
+	handler.HandleFunc(apiStableToken, func(w http.ResponseWriter, r *http.Request) {
+		params := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		requests = append(requests, params)
+		if params["force_refresh"] == true {
+			_, _ = w.Write([]byte(`{"access_token":"FORCED_TOKEN","expires_in":7200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"STABLE_TOKEN","expires_in":7200}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestStableToken", Secret: "SECRET", BaseURL: svr.URL, UseStableToken: true})
+	cache := cachesync.New()
+	app.SetAccessTokenCacheDriver(cache)
+
+	accessToken, err := GetAccessToken(app)
+	if err != nil || accessToken != "STABLE_TOKEN" {
+		t.Fatalf("GetAccessToken() = %s, %v, want STABLE_TOKEN", accessToken, err)
+	}
+	if len(requests) != 1 || requests[0]["appid"] != "TestStableToken" || requests[0]["grant_type"] != "client_credential" || requests[0]["force_refresh"] != false {
+		t.Errorf("stable_token request = %v", requests)
+	}
+
+	accessToken, err = ForceRefreshStableToken(app)
+	if err != nil || accessToken != "FORCED_TOKEN" {
+		t.Fatalf("ForceRefreshStableToken() = %s, %v, want FORCED_TOKEN", accessToken, err)
+	}
+	if cached, _ := cache.Fetch(app.CacheKey(app.Config.Appid)); cached != "FORCED_TOKEN" {
+		t.Errorf("cached token = %s, want FORCED_TOKEN", cached)
+	}
+	if len(requests) != 2 || requests[1]["force_refresh"] != true {
+		t.Errorf("stable_token request = %v", requests)
+	}
+}
+
+func TestAccessTokenTTL(t *testing.T) {
+	if got := accessTokenTTL(7200); got != 6480*time.Second {
+		t.Errorf("accessTokenTTL(7200) = %s, want 1h48m", got)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultMessagesBuffer Messages() 返回的 channel 缓冲大小
+const DefaultMessagesBuffer = 100
+
+// ErrorMessagesFull Messages() 的 channel 缓冲已满，消息 未能在 messagesPushTimeout 内 推送
+var ErrorMessagesFull = errors.New("messages channel full")
+
+// ErrorMessagesClosed Messages() 的 channel 已经 Close
+var ErrorMessagesClosed = errors.New("messages channel closed")
+
+// channel 已满时 推送消息 最多等待的时间，需远小于 微信服务器的 5 秒超时
+var messagesPushTimeout = time.Second
+
+// 消息去重 的时间窗口，覆盖 微信服务器 的 三次重试
+var messagesDedupeWindow = time.Minute
+
+// InboundMessage 通过 Messages() 接收的 推送消息/事件
+type InboundMessage struct {
+	Message    interface{} // ParseXML 解析后的 消息/事件 类型（如 messagetype.MessageText）
+	Raw        []byte      // 解密后的 明文 xml
+	MsgType    string
+	Event      string
+	ReceivedAt time.Time
+}
+
+/*
+Messages 以 channel 的方式 接收推送消息（拉模式）
+
+首次调用后 ServeHTTP 不再按 HandleFunc 路由，校验、解密后的消息 推送到 channel 并立即回复 success，
+需要回复用户的 请通过 客服消息接口 发送
+
+微信服务器 重试的消息 按 MsgId（事件 按 FromUserName + CreateTime）去重，只推送一次
+
+channel 缓冲已满时 最多等待 1 秒，仍无法推送时 响应 503 并记录日志（微信服务器会重试），消费者应及时读取；
+不再接收时 调用 Close 关闭 channel
+
+	messages := server.Messages()
+	go http.ListenAndServe(":80", server)
+	for inbound := range messages {
+		// ...
+	}
+*/
+func (s *Server) Messages() <-chan InboundMessage {
+	s.messagesLock.Lock()
+	defer s.messagesLock.Unlock()
+
+	if s.messages == nil {
+		s.messages = make(chan InboundMessage, DefaultMessagesBuffer)
+	}
+	return s.messages
+}
+
+/*
+Close 关闭 Messages() 返回的 channel，之后推送的消息 响应 503
+
+未调用 Messages() 或 重复调用时 不做任何处理
+*/
+func (s *Server) Close() {
+	s.messagesLock.Lock()
+	defer s.messagesLock.Unlock()
+
+	if s.messages != nil && !s.messagesClosed {
+		s.messagesClosed = true
+		close(s.messages)
+	}
+}
+
+// streaming 是否 调用过 Messages()
+func (s *Server) streaming() bool {
+	s.messagesLock.RLock()
+	defer s.messagesLock.RUnlock()
+
+	return s.messages != nil
+}
+
+// push 推送消息到 channel 重复的消息 直接忽略
+func (s *Server) push(request *http.Request, plain []byte, message interface{}) error {
+	s.messagesLock.RLock()
+	defer s.messagesLock.RUnlock()
+
+	if s.messagesClosed {
+		return ErrorMessagesClosed
+	}
+
+	key := dedupeKey(plain)
+	if !s.markRecent(key) {
+		return nil
+	}
+
+	msgType, event := messageHeader(plain)
+	inbound := InboundMessage{
+		Message:    message,
+		Raw:        plain,
+		MsgType:    msgType,
+		Event:      event,
+		ReceivedAt: time.Now(),
+	}
+
+	var err error
+	timer := time.NewTimer(messagesPushTimeout)
+	defer timer.Stop()
+	select {
+	case s.messages <- inbound:
+		return nil
+	case <-timer.C:
+		err = ErrorMessagesFull
+	case <-request.Context().Done():
+		err = request.Context().Err()
+	}
+
+	// 未推送 微信服务器重试时 需要再次推送
+	s.forgetRecent(key)
+	return err
+}
+
+/*
+dedupeKey 消息去重 key：有 MsgId 的消息 使用 MsgId，事件 使用 FromUserName + CreateTime
+
+See: https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Receiving_standard_messages.html
+*/
+func dedupeKey(plain []byte) string {
+	header := struct {
+		MsgId        string `xml:"MsgId"`
+		FromUserName string `xml:"FromUserName"`
+		CreateTime   string `xml:"CreateTime"`
+	}{}
+	_ = xml.Unmarshal(plain, &header)
+
+	if header.MsgId != "" {
+		return "msgid:" + header.MsgId
+	}
+	if header.FromUserName != "" && header.CreateTime != "" {
+		return "event:" + header.FromUserName + ":" + header.CreateTime
+	}
+	return ""
+}
+
+// markRecent 记录 key，时间窗口内 已经记录过时 返回 false；key 为空时 不去重
+func (s *Server) markRecent(key string) bool {
+	if key == "" {
+		return true
+	}
+
+	s.recentLock.Lock()
+	defer s.recentLock.Unlock()
+
+	now := time.Now()
+	if s.recent == nil {
+		s.recent = map[string]time.Time{}
+	}
+	// 每个时间窗口 清理一次 过期记录
+	if now.Sub(s.recentPruned) > messagesDedupeWindow {
+		for k, at := range s.recent {
+			if now.Sub(at) > messagesDedupeWindow {
+				delete(s.recent, k)
+			}
+		}
+		s.recentPruned = now
+	}
+
+	if at, ok := s.recent[key]; ok && now.Sub(at) <= messagesDedupeWindow {
+		return false
+	}
+	s.recent[key] = now
+	return true
+}
+
+// forgetRecent 删除 key 的记录
+func (s *Server) forgetRecent(key string) {
+	if key == "" {
+		return
+	}
+
+	s.recentLock.Lock()
+	defer s.recentLock.Unlock()
+	delete(s.recent, key)
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+)
+
+func TestServer_Messages(t *testing.T) {
+	server := newHandlerTestServer()
+	messages := server.Messages()
+	if server.Messages() != messages {
+		t.Fatal("Messages() returned different channel")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(handlerTestTextXML))
+	server.ServeHTTP(w, r)
+
+	// 不再路由到 HandleFunc 统一回复 success
+	if w.Code != http.StatusOK || w.Body.String() != "success" {
+		t.Fatalf("code = %d, body = %q", w.Code, w.Body.String())
+	}
+
+	inbound := <-messages
+	msg, ok := inbound.Message.(type_message.MessageText)
+	if !ok || msg.Content != "hello" || inbound.MsgType != type_message.MsgTypeText || inbound.Event != "" || string(inbound.Raw) != handlerTestTextXML {
+		t.Errorf("inbound = %#v", inbound)
+	}
+	if inbound.ReceivedAt.IsZero() {
+		t.Error("ReceivedAt is zero")
+	}
+}
+
+func textXML(msgId int) string {
+	return strings.Replace(handlerTestTextXML, "1234567890123456", strconv.Itoa(msgId), 1)
+}
+
+func serveXML(server *Server, xml string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(xml)))
+	return w
+}
+
+func TestServer_Messages_Full(t *testing.T) {
+	defer func(timeout time.Duration) { messagesPushTimeout = timeout }(messagesPushTimeout)
+	messagesPushTimeout = 10 * time.Millisecond
+
+	server := newHandlerTestServer()
+	messages := server.Messages()
+	for i := 0; i < cap(messages); i++ {
+		serveXML(server, textXML(i))
+	}
+
+	// 缓冲已满 超时后 响应 503，不阻塞到请求取消
+	start := time.Now()
+	if w := serveXML(server, textXML(cap(messages))); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ServeHTTP blocked %s", elapsed)
+	}
+	if len(messages) != cap(messages) {
+		t.Errorf("len(messages) = %d", len(messages))
+	}
+
+	// 请求取消时 同样响应 503
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/?"+signedQuery("TOKEN").Encode(), strings.NewReader(textXML(cap(messages)))).WithContext(ctx)
+	server.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	// 推送失败的消息 微信服务器重试时 再次推送
+	<-messages
+	if w := serveXML(server, textXML(cap(messages))); w.Code != http.StatusOK {
+		t.Errorf("retry code = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(messages) != cap(messages) {
+		t.Errorf("len(messages) = %d", len(messages))
+	}
+}
+
+func TestServer_Messages_Dedupe(t *testing.T) {
+	server := newHandlerTestServer()
+	messages := server.Messages()
+
+	// 微信服务器 重试 同一条消息
+	for i := 0; i < 3; i++ {
+		if w := serveXML(server, handlerTestTextXML); w.Code != http.StatusOK || w.Body.String() != "success" {
+			t.Fatalf("code = %d, body = %q", w.Code, w.Body.String())
+		}
+	}
+	serveXML(server, textXML(1))
+	if len(messages) != 2 {
+		t.Errorf("len(messages) = %d, want 2", len(messages))
+	}
+
+	// 事件 没有 MsgId 按 FromUserName + CreateTime 去重
+	if key := dedupeKey([]byte(`<xml><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>123456789</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[subscribe]]></Event></xml>`)); key != "event:fromUser:123456789" {
+		t.Errorf("dedupeKey() = %s", key)
+	}
+}
+
+func TestServer_Close(t *testing.T) {
+	server := newHandlerTestServer()
+	messages := server.Messages()
+	serveXML(server, handlerTestTextXML)
+
+	server.Close()
+	server.Close() // 重复调用 不会 panic
+
+	var received int
+	for range messages {
+		received++
+	}
+	if received != 1 {
+		t.Errorf("received = %d, want 1", received)
+	}
+
+	if w := serveXML(server, textXML(1)); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d after Close, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+const apiGetTicket = "/cgi-bin/ticket/getticket"
+
+// ticket 类型
+const (
+	TicketTypeJsapi  = "jsapi"   // JS-SDK 权限验证签名 使用的 jsapi_ticket
+	TicketTypeWxCard = "wx_card" // 卡券 签名 使用的 api_ticket
+)
+
+/*
+ticketManager 管理 getticket 接口 返回的各类 ticket
+
+不同 type 的 ticket 获取、缓存、过期逻辑 完全一致，只是缓存 key 不同；每个 缓存 key（即 appid + type）使用独立的锁 防止并发重复刷新
+*/
+type ticketManager struct {
+	locks sync.Map // 缓存 key -> *sync.Mutex
+}
+
+var tickets ticketManager
+
+func (m *ticketManager) lock(key string) *sync.Mutex {
+	lock, _ := m.locks.LoadOrStore(key, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// cacheKey ticket 缓存 key 附加 Config.CacheKeyPrefix 前缀
+func (m *ticketManager) cacheKey(ctx *OffiAccount, ticketType string) string {
+	return ctx.CacheKey("ticket:" + ticketType + ":" + ctx.Config.Appid)
+}
+
+func (m *ticketManager) get(ctx *OffiAccount, ticketType string) (ticket string, err error) {
+	key := m.cacheKey(ctx, ticketType)
+	if ticket, _ = ctx.AccessToken.Cache.Fetch(key); ticket != "" {
+		return
+	}
+
+	lock := m.lock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if ticket, _ = ctx.AccessToken.Cache.Fetch(key); ticket != "" {
+		return
+	}
+
+	params := url.Values{}
+	params.Add("type", ticketType)
+	resp, err := ctx.Client.HTTPGetWithParams(apiGetTicket, params)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	if result.Ticket == "" {
+		return "", fmt.Errorf("getticket type %s: no ticket in response %s", ticketType, resp)
+	}
+
+	// 与 access_token 一样 提前过期，避免 下发即将过期的 ticket
+	_ = ctx.AccessToken.Cache.Save(key, result.Ticket, accessTokenTTL(result.ExpiresIn))
+	return result.Ticket, nil
+}
+
+/*
+GetTicket 获取 指定类型的 ticket，过期前 从缓存中获取
+
+ticket 有效期 7200 秒，频繁刷新 会导致 api 调用受限，所以和 access_token 一样 缓存在 ctx.AccessToken.Cache 中，缓存时间为 0.9 * expires_in
+
+See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#62
+
+GET https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=ACCESS_TOKEN&type=jsapi
+*/
+func GetTicket(ctx *OffiAccount, ticketType string) (ticket string, err error) {
+	return tickets.get(ctx, ticketType)
+}
+
+// GetJsapiTicket 获取 JS-SDK 使用的 jsapi_ticket
+func GetJsapiTicket(ctx *OffiAccount) (ticket string, err error) {
+	return GetTicket(ctx, TicketTypeJsapi)
+}
+
+// GetWxCardTicket 获取 卡券 使用的 api_ticket
+func GetWxCardTicket(ctx *OffiAccount) (ticket string, err error) {
+	return GetTicket(ctx, TicketTypeWxCard)
+}
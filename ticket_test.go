@@ -0,0 +1,121 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+// lifeTimeCache 记录 Save 的 缓存时间
+type lifeTimeCache struct {
+	cachego.Cache
+	lifeTimes map[string]time.Duration
+}
+
+func (c *lifeTimeCache) Save(key string, value string, lifeTime time.Duration) error {
+	c.lifeTimes[key] = lifeTime
+	return c.Cache.Save(key, value, lifeTime)
+}
+
+func TestGetTicket(t *testing.T) {
+	var MockOffiAccount = New(Config{
+		Appid:          "TestGetTicket",
+		CacheKeyPrefix: "prod:",
+	})
+	cache := cachesync.New()
+	_ = cache.Save("prod:TestGetTicket", "ACCESS_TOKEN", 0)
+	MockOffiAccount.SetAccessTokenCacheDriver(cache)
+
+	var MockSvrHandler = http.NewServeMux()
+	var MockSvr = httptest.NewServer(MockSvrHandler)
+	defer MockSvr.Close()
+	WXServerUrl = MockSvr.URL
+
+	calls := map[string]int{}
+	MockSvrHandler.HandleFunc(apiGetTicket, func(w http.ResponseWriter, r *http.Request) {
+		ticketType := r.URL.Query().Get("type")
+		calls[ticketType]++
+		if ticketType == "invalid" {
+			_, _ = w.Write([]byte(`{"errcode":40097,"errmsg":"invalid args"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","ticket":"TICKET_` + ticketType + `","expires_in":7200}`))
+	})
+
+	for i := 0; i < 2; i++ {
+		ticket, err := GetJsapiTicket(MockOffiAccount)
+		if err != nil || ticket != "TICKET_jsapi" {
+			t.Errorf("GetJsapiTicket() = %s, %v", ticket, err)
+		}
+		ticket, err = GetWxCardTicket(MockOffiAccount)
+		if err != nil || ticket != "TICKET_wx_card" {
+			t.Errorf("GetWxCardTicket() = %s, %v", ticket, err)
+		}
+	}
+	if calls[TicketTypeJsapi] != 1 || calls[TicketTypeWxCard] != 1 {
+		t.Errorf("getticket calls = %v, want each type once", calls)
+	}
+	if !cache.Contains("prod:ticket:jsapi:TestGetTicket") {
+		t.Errorf("ticket should be cached under prod:ticket:jsapi:TestGetTicket")
+	}
+
+	if _, err := GetTicket(MockOffiAccount, "invalid"); err == nil {
+		t.Errorf("GetTicket() want error for invalid type")
+	}
+}
+
+func TestGetTicket_TTLAndLocks(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","ticket":"TICKET","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	cache := &lifeTimeCache{Cache: cachesync.New(), lifeTimes: map[string]time.Duration{}}
+	app := New(Config{Appid: "TestGetTicket_TTLAndLocks", BaseURL: svr.URL}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cache)
+	_ = cache.Cache.Save("TestGetTicket_TTLAndLocks", "ACCESS_TOKEN", 0)
+
+	// 其他 appid 刷新同类型 ticket 时 不阻塞
+	other := New(Config{Appid: "TestGetTicket_TTLAndLocks_Other"})
+	lock := tickets.lock(tickets.cacheKey(other, TicketTypeJsapi))
+	lock.Lock()
+	defer lock.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GetJsapiTicket(app)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("GetJsapiTicket() blocked by another appid")
+	}
+
+	// 缓存时间 0.9 * expires_in
+	if got := cache.lifeTimes["ticket:jsapi:TestGetTicket_TTLAndLocks"]; got != 6480*time.Second {
+		t.Errorf("ticket cached for %s, want 6480s", got)
+	}
+}
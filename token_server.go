@@ -0,0 +1,222 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 中控服务器 无法得知 剩余有效期 时 下发的有效期：微信保证 刷新后 旧 token 在 5 分钟内仍可用
+var accessTokenServerFallbackTTL = 5 * time.Minute
+
+/*
+AccessTokenServer 中控服务器：唯一负责 从微信服务器 获取、刷新 access_token，通过 http 下发给 业务服务器
+
+GET 返回 当前 access_token；POST 表单参数 expired 为 业务服务器 调用接口时 发现失效的 token，
+仅当 expired 与 当前 token 一致时 才从微信服务器刷新，避免 多个业务服务器 同时通知 导致重复刷新
+
+响应格式 与 /cgi-bin/token 一致：{"access_token":"ACCESS_TOKEN","expires_in":7200}，expires_in 为 剩余有效期（秒）
+
+	http.Handle("/access_token", offiaccount.NewAccessTokenServer(app, "AUTH_TOKEN"))
+
+业务服务器 使用 WithAccessTokenServer 获取
+*/
+type AccessTokenServer struct {
+	Ctx       *OffiAccount
+	AuthToken string // 非空时 校验请求头 Authorization: Bearer AuthToken
+
+	refreshLock sync.Mutex
+}
+
+// NewAccessTokenServer 创建 中控服务器 authToken 为空时 不校验身份
+func NewAccessTokenServer(ctx *OffiAccount, authToken string) *AccessTokenServer {
+	return &AccessTokenServer{Ctx: ctx, AuthToken: authToken}
+}
+
+// accessTokenServerResponse 中控服务器 响应
+type accessTokenServerResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *AccessTokenServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if s.AuthToken != "" && request.Header.Get("Authorization") != "Bearer "+s.AuthToken {
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var accessToken string
+	var err error
+	switch request.Method {
+	case http.MethodGet:
+		accessToken, err = s.Ctx.AccessToken.GetAccessTokenHandler(s.Ctx)
+	case http.MethodPost:
+		accessToken, err = s.refresh(request.FormValue("expired"))
+	default:
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err != nil {
+		if s.Ctx.Logger != nil {
+			s.Ctx.Logger.Printf("AccessTokenServer %s: %s", request.Method, err)
+		}
+		writer.WriteHeader(http.StatusBadGateway)
+		data, _ := json.Marshal(WXError{Errcode: -1, Errmsg: err.Error()})
+		_, _ = writer.Write(data)
+		return
+	}
+
+	ttl, err := s.Ctx.AccessToken.RemainingTTL(s.Ctx.Config.Appid)
+	if err != nil || ttl <= 0 {
+		if err != nil && s.Ctx.Logger != nil {
+			s.Ctx.Logger.Printf("AccessTokenServer RemainingTTL: %s, use %s", err, accessTokenServerFallbackTTL)
+		}
+		ttl = accessTokenServerFallbackTTL
+	}
+	data, _ := json.Marshal(accessTokenServerResponse{AccessToken: accessToken, ExpiresIn: int64(ttl / time.Second)})
+	_, _ = writer.Write(data)
+}
+
+// refresh expired 仍是当前 token 时 从微信服务器刷新，否则 直接返回 当前 token
+func (s *AccessTokenServer) refresh(expired string) (accessToken string, err error) {
+	s.refreshLock.Lock()
+	defer s.refreshLock.Unlock()
+
+	accessToken, err = s.Ctx.AccessToken.GetAccessTokenHandler(s.Ctx)
+	if err != nil || accessToken != expired {
+		return
+	}
+
+	result, err := NoticeRefreshAccessTokenWithResult(s.Ctx)
+	if err != nil {
+		return
+	}
+	return result.AccessToken, nil
+}
+
+/*
+WithAccessTokenServer 业务服务器 从 中控服务器（见 AccessTokenServer）获取 access_token，不直接访问微信服务器
+
+获取的 access_token 按 中控服务器 返回的 剩余有效期 缓存在 AccessToken.Cache 中；
+调用接口 发现 access_token 失效时，通知 中控服务器 刷新（而不是 各自从微信服务器刷新），然后重试
+
+	app := offiaccount.New(config, offiaccount.WithAccessTokenServer("http://token-server/access_token", "AUTH_TOKEN"))
+*/
+func WithAccessTokenServer(serverUrl string, authToken string) Option {
+	return func(offiAccount *OffiAccount) {
+		client := &accessTokenServerClient{serverUrl: serverUrl, authToken: authToken}
+		offiAccount.AccessToken.GetAccessTokenHandler = client.get
+		offiAccount.AccessToken.NoticeAccessTokenExpireHandler = client.noticeExpire
+	}
+}
+
+// accessTokenServerClient 从 中控服务器 获取 access_token
+type accessTokenServerClient struct {
+	serverUrl string
+	authToken string
+
+	lock sync.Mutex
+	last atomic.Value // 最近一次 返回给调用方的 access_token，通知过期时 发送给 中控服务器
+}
+
+func (c *accessTokenServerClient) get(ctx *OffiAccount) (accessToken string, err error) {
+	key := ctx.CacheKey(ctx.Config.Appid)
+	if accessToken, _ = ctx.AccessToken.Cache.Fetch(key); accessToken != "" {
+		c.last.Store(accessToken)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if accessToken, _ = ctx.AccessToken.Cache.Fetch(key); accessToken != "" {
+		c.last.Store(accessToken)
+		return
+	}
+	return c.request(ctx, http.MethodGet, nil)
+}
+
+func (c *accessTokenServerClient) noticeExpire(ctx *OffiAccount) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// 本地缓存 可能没有保存 access_token（如 缓存失败），使用 最近一次 返回的 token
+	expired, _ := c.last.Load().(string)
+	_ = ctx.AccessToken.Cache.Delete(ctx.CacheKey(ctx.Config.Appid))
+
+	_, err = c.request(ctx, http.MethodPost, url.Values{"expired": []string{expired}})
+	return
+}
+
+// request 请求 中控服务器，并缓存 返回的 access_token
+func (c *accessTokenServerClient) request(ctx *OffiAccount, method string, form url.Values) (accessToken string, err error) {
+	var req *http.Request
+	if form != nil {
+		req, err = http.NewRequest(method, c.serverUrl, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(method, c.serverUrl, nil)
+	}
+	if err != nil {
+		return
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	response, err := ctx.Client.getHTTPClient().Do(req)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	resp, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access token server %s %s: %s %s", method, c.serverUrl, response.Status, resp)
+	}
+
+	result := accessTokenServerResponse{}
+	if err = json.Unmarshal(resp, &result); err != nil {
+		return
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("access token server %s %s: no access_token in %s", method, c.serverUrl, resp)
+	}
+
+	c.last.Store(result.AccessToken)
+
+	// 剩余有效期 未知时 不缓存，每次调用 都从 中控服务器 获取
+	if result.ExpiresIn > 0 {
+		_ = ctx.AccessToken.Cache.Save(ctx.CacheKey(ctx.Config.Appid), result.AccessToken, time.Duration(result.ExpiresIn)*time.Second)
+	}
+	return result.AccessToken, nil
+}
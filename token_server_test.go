@@ -0,0 +1,153 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestAccessTokenServer(t *testing.T) {
+	var lock sync.Mutex
+	var refreshes int
+	var valid string
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		refreshes++
+		valid = "TOKEN_" + strconv.Itoa(refreshes)
+		_, _ = w.Write([]byte(`{"access_token":"` + valid + `","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/api", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.URL.Query().Get("access_token") != valid {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	wx := httptest.NewServer(handler)
+	defer wx.Close()
+
+	// 中控服务器
+	central := New(Config{Appid: "TestAccessTokenServer", Secret: "SECRET", BaseURL: wx.URL})
+	central.SetAccessTokenCacheDriver(cachesync.New())
+	central.SetLogger(nil)
+	tokenSvr := httptest.NewServer(NewAccessTokenServer(central, "AUTH"))
+	defer tokenSvr.Close()
+
+	// 业务服务器
+	newPod := func(authToken string) *OffiAccount {
+		pod := New(Config{Appid: "TestAccessTokenServer", BaseURL: wx.URL}, WithAccessTokenServer(tokenSvr.URL, authToken))
+		pod.SetAccessTokenCacheDriver(cachesync.New())
+		pod.SetLogger(nil)
+		return pod
+	}
+	pod1, pod2 := newPod("AUTH"), newPod("AUTH")
+
+	for _, pod := range []*OffiAccount{pod1, pod2, pod1} {
+		if _, err := pod.Client.HTTPGet("/cgi-bin/api"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes)
+	}
+
+	// access_token 失效：只有 第一个通知的 业务服务器 触发 中控刷新
+	lock.Lock()
+	valid = ""
+	lock.Unlock()
+	for _, pod := range []*OffiAccount{pod1, pod2} {
+		if _, err := pod.Client.HTTPGet("/cgi-bin/api"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d, want 2", refreshes)
+	}
+	if token, _ := pod2.AccessToken.Cache.Fetch(pod2.CacheKey(pod2.Config.Appid)); token != "TOKEN_2" {
+		t.Errorf("pod2 cached token = %q, want TOKEN_2", token)
+	}
+
+	// 身份校验
+	if _, err := newPod("WRONG").Client.HTTPGet("/cgi-bin/api"); err == nil {
+		t.Error("HTTPGet() want error with wrong auth token")
+	}
+}
+
+func TestAccessTokenServer_UnknownTTL(t *testing.T) {
+	var lock sync.Mutex
+	var refreshes int
+	var valid string
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/token", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		refreshes++
+		valid = "TOKEN_" + strconv.Itoa(refreshes)
+		_, _ = w.Write([]byte(`{"access_token":"` + valid + `","expires_in":7200}`))
+	})
+	handler.HandleFunc("/cgi-bin/api", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.URL.Query().Get("access_token") != valid {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	wx := httptest.NewServer(handler)
+	defer wx.Close()
+
+	// 中控服务器 重启后 共用缓存中 已有 失效的 token，缓存不支持 TTLFetcher，剩余有效期 未知
+	central := New(Config{Appid: "TestAccessTokenServer_UnknownTTL", Secret: "SECRET", BaseURL: wx.URL})
+	central.SetAccessTokenCacheDriver(cachesync.New())
+	central.SetLogger(nil)
+	_ = central.AccessToken.Cache.Save(central.CacheKey(central.Config.Appid), "TOKEN_0", time.Hour)
+	tokenSvr := httptest.NewServer(NewAccessTokenServer(central, ""))
+	defer tokenSvr.Close()
+
+	response, err := http.Get(tokenSvr.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := accessTokenServerResponse{}
+	_ = json.NewDecoder(response.Body).Decode(&result)
+	response.Body.Close()
+	if result.AccessToken != "TOKEN_0" || result.ExpiresIn != int64(accessTokenServerFallbackTTL/time.Second) {
+		t.Errorf("GET = %+v, want TOKEN_0 with fallback expires_in", result)
+	}
+
+	// 业务服务器 本地缓存 保存失败，通知过期时 仍需带上 失效的 token
+	pod := New(Config{Appid: "TestAccessTokenServer_UnknownTTL", BaseURL: wx.URL}, WithAccessTokenServer(tokenSvr.URL, ""))
+	pod.SetAccessTokenCacheDriver(failSaveCache{Cache: cachesync.New()})
+	pod.SetLogger(nil)
+	if _, err = pod.Client.HTTPGet("/cgi-bin/api"); err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faabiosr/cachego"
+)
+
+// ErrorTTLUnknown 无法得知 access_token 剩余有效期：本实例未获取过 且 缓存不支持 TTLFetcher
+var ErrorTTLUnknown = errors.New("access token remaining ttl unknown")
+
+/*
+TTLFetcher 可以返回 剩余有效期 的缓存
+
+AccessToken.Cache 实现该接口时，RemainingTTL 从缓存读取，多实例共用缓存时 结果也准确；key 不存在时 返回 空 value 和 nil
+*/
+type TTLFetcher interface {
+	FetchWithTTL(key string) (value string, ttl time.Duration, err error)
+}
+
+type tokenExpiry struct {
+	key       string
+	expiresAt time.Time
+}
+
+/*
+RemainingTTL appid 对应 access_token 在缓存中的 剩余有效期，已过期 或 不存在时 返回 0，读取缓存出错时 返回该错误
+
+缓存实现 TTLFetcher 时 本实例未获取过 也会按 CacheKey(appid) 读取；缓存未实现 TTLFetcher 时，根据 本实例最近一次 保存 access_token 的时间 计算；本实例未获取过时 返回 ErrorTTLUnknown
+
+	ttl, err := app.AccessToken.RemainingTTL(app.Config.Appid)
+*/
+func (manager *AccessToken) RemainingTTL(appid string) (ttl time.Duration, err error) {
+	manager.expiryLock.RLock()
+	expiry, ok := manager.expiry[appid]
+	manager.expiryLock.RUnlock()
+
+	if fetcher, isFetcher := manager.Cache.(TTLFetcher); isFetcher {
+		key := appid
+		if ok {
+			key = expiry.key
+		} else if manager.cacheKey != nil {
+			key = manager.cacheKey(appid)
+		}
+		value, ttl, err := fetcher.FetchWithTTL(key)
+		if errors.Is(err, cachego.ErrCacheExpired) {
+			return 0, nil
+		}
+		if err != nil || value == "" {
+			return 0, err
+		}
+		return ttl, nil
+	}
+
+	if !ok {
+		return 0, ErrorTTLUnknown
+	}
+	if ttl = time.Until(expiry.expiresAt); ttl < 0 {
+		ttl = 0
+	}
+	return ttl, nil
+}
+
+// recordExpiry 记录 access_token 缓存 过期时间
+func (manager *AccessToken) recordExpiry(appid string, key string, ttl time.Duration) {
+	manager.expiryLock.Lock()
+	defer manager.expiryLock.Unlock()
+	if manager.expiry == nil {
+		manager.expiry = map[string]tokenExpiry{}
+	}
+	manager.expiry[appid] = tokenExpiry{key: key, expiresAt: time.Now().Add(ttl)}
+}
+
+// saveAccessToken 缓存 access_token 缓存时间为 0.9 * expiresIn
+func (offiAccount *OffiAccount) saveAccessToken(accessToken string, expiresIn int) (err error) {
+	key := offiAccount.CacheKey(offiAccount.Config.Appid)
+	ttl := accessTokenTTL(expiresIn)
+	if err = offiAccount.AccessToken.Cache.Save(key, accessToken, ttl); err != nil {
+		return
+	}
+	offiAccount.AccessToken.recordExpiry(offiAccount.Config.Appid, key, ttl)
+	return
+}
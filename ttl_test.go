@@ -0,0 +1,98 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+type ttlCache struct {
+	cachego.Cache
+	ttl time.Duration
+	key string
+	err error
+}
+
+func (c *ttlCache) FetchWithTTL(key string) (value string, ttl time.Duration, err error) {
+	c.key = key
+	if c.err != nil {
+		return "", 0, c.err
+	}
+	value, err = c.Fetch(key)
+	return value, c.ttl, err
+}
+
+func TestAccessToken_RemainingTTL(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestAccessToken_RemainingTTL", BaseURL: svr.URL, CacheKeyPrefix: "test:"})
+	app.SetLogger(nil)
+	app.SetAccessTokenCacheDriver(cachesync.New())
+
+	if _, err := app.AccessToken.RemainingTTL(app.Config.Appid); err != ErrorTTLUnknown {
+		t.Errorf("RemainingTTL() before fetch error = %v, want ErrorTTLUnknown", err)
+	}
+
+	if _, err := GetAccessToken(app); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := app.AccessToken.RemainingTTL(app.Config.Appid)
+	if err != nil || ttl > 6480*time.Second || ttl < 6470*time.Second {
+		t.Errorf("RemainingTTL() = %s, %v, want about 6480s (0.9 * 7200)", ttl, err)
+	}
+
+	// 缓存 支持 TTLFetcher 时 从缓存读取
+	cache := &ttlCache{Cache: cachesync.New(), ttl: time.Minute}
+	app.SetAccessTokenCacheDriver(cache)
+	if _, err = NoticeRefreshAccessTokenWithResult(app); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err = app.AccessToken.RemainingTTL(app.Config.Appid)
+	if err != nil || ttl != time.Minute || cache.key != "test:TestAccessToken_RemainingTTL" {
+		t.Errorf("RemainingTTL() = %s, %v, key = %s", ttl, err, cache.key)
+	}
+
+	// 未获取过 access_token 的实例 共用缓存时 按 CacheKey(appid) 读取
+	other := New(Config{Appid: "TestAccessToken_RemainingTTL", BaseURL: svr.URL, CacheKeyPrefix: "test:"})
+	other.SetLogger(nil)
+	other.SetAccessTokenCacheDriver(cache)
+	cache.key = ""
+	ttl, err = other.AccessToken.RemainingTTL(other.Config.Appid)
+	if err != nil || ttl != time.Minute || cache.key != "test:TestAccessToken_RemainingTTL" {
+		t.Errorf("RemainingTTL() shared cache = %s, %v, key = %s", ttl, err, cache.key)
+	}
+
+	// 读取缓存出错时 返回错误
+	cache.err = errors.New("connection refused")
+	if _, err = other.AccessToken.RemainingTTL(other.Config.Appid); err != cache.err {
+		t.Errorf("RemainingTTL() error = %v, want %v", err, cache.err)
+	}
+
+	// 已过期 返回 0
+	cache.err = cachego.ErrCacheExpired
+	if ttl, err = other.AccessToken.RemainingTTL(other.Config.Appid); err != nil || ttl != 0 {
+		t.Errorf("RemainingTTL() expired = %s, %v, want 0", ttl, err)
+	}
+}
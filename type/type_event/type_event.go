@@ -14,7 +14,11 @@
 
 package type_event
 
-import "github.com/fastwego/offiaccount/type/type_message"
+import (
+	"strings"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+)
 
 const (
 	EventTypeSubscribe   = "subscribe"   // 关注
@@ -23,6 +27,9 @@ const (
 	EventTypeLocation    = "LOCATION"    // 上报位置
 )
 
+// QRScenePrefix 未关注用户 扫码关注时 EventKey 的前缀
+const QRScenePrefix = "qrscene_"
+
 type Event type_message.MessageEvent
 
 /*
@@ -44,6 +51,16 @@ type EventSubscribe struct {
 	Ticket   string
 }
 
+// IsQRSubscribe 是否 扫描带参数二维码 关注
+func (e EventSubscribe) IsQRSubscribe() bool {
+	return strings.HasPrefix(e.EventKey, QRScenePrefix)
+}
+
+// SceneID 二维码 场景值 (去掉 qrscene_ 前缀)；普通关注 返回空
+func (e EventSubscribe) SceneID() string {
+	return strings.TrimPrefix(e.EventKey, QRScenePrefix)
+}
+
 /*
 <xml>
   <ToUserName><![CDATA[toUser]]></ToUserName>
@@ -56,10 +73,21 @@ type EventSubscribe struct {
 </xml>
 */
 type EventScan struct {
+	Event
 	EventKey string
 	Ticket   string
 }
 
+// IsQRSubscribe 已关注用户 扫码 始终返回 false
+func (e EventScan) IsQRSubscribe() bool {
+	return false
+}
+
+// SceneID 二维码 场景值
+func (e EventScan) SceneID() string {
+	return strings.TrimPrefix(e.EventKey, QRScenePrefix)
+}
+
 /*
 <xml>
   <ToUserName><![CDATA[toUser]]></ToUserName>
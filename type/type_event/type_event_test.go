@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestEventMenuPicSysPhoto(t *testing.T) {
@@ -28,4 +29,186 @@ func TestEventMenuPicSysPhoto(t *testing.T) {
 		t.Fatalf("xml parser error=%+v\n", err)
 	}
 	fmt.Printf("photo=%+v\n", photo)
+
+	if !photo.Time().Equal(time.Unix(1408090651, 0)) {
+		t.Errorf("Time() = %s", photo.Time())
+	}
+}
+
+func TestEventPublishJobFinish(t *testing.T) {
+	s := `<xml>
+<ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+<FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+<CreateTime>1481013459</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+<PublishEventInfo>
+<publish_id>2247503051</publish_id>
+<publish_status>0</publish_status>
+<article_id><![CDATA[b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvyWP4Hq4]]></article_id>
+<article_detail>
+<count>1</count>
+<item>
+<idx>1</idx>
+<article_url><![CDATA[ARTICLE_URL]]></article_url>
+</item>
+</article_detail>
+</PublishEventInfo>
+</xml>`
+
+	event := EventPublishJobFinish{}
+	err := xml.Unmarshal([]byte(s), &event)
+	if err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if event.Event.Event != EventTypePublishJobFinish {
+		t.Errorf("Event = %s, want %s", event.Event.Event, EventTypePublishJobFinish)
+	}
+	info := event.PublishEventInfo
+	if info.PublishId != "2247503051" || info.PublishStatus != "0" || info.ArticleId != "b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvyWP4Hq4" {
+		t.Errorf("PublishEventInfo = %+v", info)
+	}
+	if len(info.ArticleDetail.Item) != 1 || info.ArticleDetail.Item[0].ArticleUrl != "ARTICLE_URL" {
+		t.Errorf("ArticleDetail = %+v", info.ArticleDetail)
+	}
+}
+
+func TestEventKfSwitchSession(t *testing.T) {
+	s := `<xml>
+<ToUserName><![CDATA[touser]]></ToUserName>
+<FromUserName><![CDATA[fromuser]]></FromUserName>
+<CreateTime>1399197672</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[kf_switch_session]]></Event>
+<FromKfAccount><![CDATA[test1@test]]></FromKfAccount>
+<ToKfAccount><![CDATA[test2@test]]></ToKfAccount>
+</xml>`
+
+	event := EventKfSwitchSession{}
+	err := xml.Unmarshal([]byte(s), &event)
+	if err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if event.Event.Event != EventTypeKfSwitchSession || event.FromKfAccount != "test1@test" || event.ToKfAccount != "test2@test" {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestEventShakearoundUserShake(t *testing.T) {
+	s := `<xml>
+<ToUserName><![CDATA[toUser]]></ToUserName>
+<FromUserName><![CDATA[fromUser]]></FromUserName>
+<CreateTime>1433332012</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[ShakearoundUserShake]]></Event>
+<ChosenBeacon>
+<Uuid><![CDATA[uuid]]></Uuid>
+<Major>major</Major>
+<Minor>minor</Minor>
+<Distance>0.057</Distance>
+</ChosenBeacon>
+<AroundBeacons>
+<AroundBeacon>
+<Uuid><![CDATA[uuid1]]></Uuid>
+<Major>major1</Major>
+<Minor>minor1</Minor>
+<Distance>166.816</Distance>
+</AroundBeacon>
+<AroundBeacon>
+<Uuid><![CDATA[uuid2]]></Uuid>
+<Major>major2</Major>
+<Minor>minor2</Minor>
+<Distance>15.013</Distance>
+</AroundBeacon>
+</AroundBeacons>
+</xml>`
+
+	event := EventShakearoundUserShake{}
+	err := xml.Unmarshal([]byte(s), &event)
+	if err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if event.Event.Event != EventTypeShakearoundUserShake || event.ChosenBeacon != (Beacon{Uuid: "uuid", Major: "major", Minor: "minor", Distance: "0.057"}) {
+		t.Errorf("event = %+v", event)
+	}
+	if len(event.AroundBeacons) != 2 || event.AroundBeacons[1].Uuid != "uuid2" || event.AroundBeacons[1].Distance != "15.013" {
+		t.Errorf("AroundBeacons = %+v", event.AroundBeacons)
+	}
+}
+
+func TestEventSceneID(t *testing.T) {
+	subscribe := EventSubscribe{}
+	err := xml.Unmarshal([]byte(`<xml><ToUserName><![CDATA[toUser]]></ToUserName>
+<FromUserName><![CDATA[FromUser]]></FromUserName>
+<CreateTime>123456789</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[subscribe]]></Event>
+<EventKey><![CDATA[qrscene_123123]]></EventKey>
+<Ticket><![CDATA[TICKET]]></Ticket>
+</xml>`), &subscribe)
+	if err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if !subscribe.IsQRSubscribe() || subscribe.SceneID() != "123123" {
+		t.Errorf("IsQRSubscribe() = %v SceneID() = %q, want true 123123", subscribe.IsQRSubscribe(), subscribe.SceneID())
+	}
+
+	plain := EventSubscribe{}
+	if plain.IsQRSubscribe() || plain.SceneID() != "" {
+		t.Errorf("IsQRSubscribe() = %v SceneID() = %q, want false empty", plain.IsQRSubscribe(), plain.SceneID())
+	}
+
+	scan := EventScan{}
+	err = xml.Unmarshal([]byte(`<xml><ToUserName><![CDATA[toUser]]></ToUserName>
+<FromUserName><![CDATA[FromUser]]></FromUserName>
+<CreateTime>123456789</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[SCAN]]></Event>
+<EventKey><![CDATA[123123]]></EventKey>
+<Ticket><![CDATA[TICKET]]></Ticket>
+</xml>`), &scan)
+	if err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if scan.Event.Event != EventTypeScan || string(scan.FromUserName) != "FromUser" {
+		t.Errorf("EventScan = %+v", scan)
+	}
+	if scan.IsQRSubscribe() || scan.SceneID() != "123123" {
+		t.Errorf("IsQRSubscribe() = %v SceneID() = %q, want false 123123", scan.IsQRSubscribe(), scan.SceneID())
+	}
+}
+
+func TestEventWxaMediaCheck_Risky(t *testing.T) {
+	v2 := EventWxaMediaCheck{}
+	v2.Result.Suggest = "risky"
+	v1 := EventWxaMediaCheck{Isrisky: "1"}
+	pass := EventWxaMediaCheck{Isrisky: "0"}
+	pass.Result.Suggest = "pass"
+
+	if !v2.Risky() || !v1.Risky() || pass.Risky() {
+		t.Errorf("Risky() = %v %v %v, want true true false", v2.Risky(), v1.Risky(), pass.Risky())
+	}
+}
+
+func TestEventSubscribeMsgPopup_Accepted(t *testing.T) {
+	s := `<xml>
+<ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+<FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+<CreateTime>1610969440</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[subscribe_msg_popup_event]]></Event>
+<SubscribeMsgPopupEvent>
+<List><TemplateId><![CDATA[TEMPLATE_ID_1]]></TemplateId><SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString><PopupScene>2</PopupScene></List>
+<List><TemplateId><![CDATA[TEMPLATE_ID_2]]></TemplateId><SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString><PopupScene>2</PopupScene></List>
+<List><TemplateId><![CDATA[TEMPLATE_ID_3]]></TemplateId><SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString><PopupScene>1</PopupScene></List>
+</SubscribeMsgPopupEvent>
+</xml>`
+
+	event := EventSubscribeMsgPopup{}
+	if err := xml.Unmarshal([]byte(s), &event); err != nil {
+		t.Fatalf("xml parser error=%+v\n", err)
+	}
+	if got := event.Accepted(); len(got) != 2 || got[0] != "TEMPLATE_ID_1" || got[1] != "TEMPLATE_ID_3" {
+		t.Errorf("Accepted() = %v, want [TEMPLATE_ID_1 TEMPLATE_ID_3]", got)
+	}
 }
@@ -0,0 +1,68 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypeKfCreateSession = "kf_create_session" // 接入会话
+	EventTypeKfCloseSession  = "kf_close_session"  // 关闭会话
+	EventTypeKfSwitchSession = "kf_switch_session" // 转接会话
+)
+
+/*
+<xml>
+  <ToUserName><![CDATA[touser]]></ToUserName>
+  <FromUserName><![CDATA[fromuser]]></FromUserName>
+  <CreateTime>1399197672</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[kf_create_session]]></Event>
+  <KfAccount><![CDATA[test1@test]]></KfAccount>
+</xml>
+*/
+type EventKfCreateSession struct {
+	Event
+	KfAccount string
+}
+
+/*
+<xml>
+  <ToUserName><![CDATA[touser]]></ToUserName>
+  <FromUserName><![CDATA[fromuser]]></FromUserName>
+  <CreateTime>1399197672</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[kf_close_session]]></Event>
+  <KfAccount><![CDATA[test1@test]]></KfAccount>
+</xml>
+*/
+type EventKfCloseSession struct {
+	Event
+	KfAccount string
+}
+
+/*
+<xml>
+  <ToUserName><![CDATA[touser]]></ToUserName>
+  <FromUserName><![CDATA[fromuser]]></FromUserName>
+  <CreateTime>1399197672</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[kf_switch_session]]></Event>
+  <FromKfAccount><![CDATA[test1@test]]></FromKfAccount>
+  <ToKfAccount><![CDATA[test2@test]]></ToKfAccount>
+</xml>
+*/
+type EventKfSwitchSession struct {
+	Event
+	FromKfAccount string
+	ToKfAccount   string
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypeMassSendJobFinish = "MASSSENDJOBFINISH" // 群发任务完成
+)
+
+/*
+<xml>
+  <ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+  <FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+  <CreateTime>1481013459</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[MASSSENDJOBFINISH]]></Event>
+  <MsgID>1000001625</MsgID>
+  <Status><![CDATA[err(30003)]]></Status>
+  <TotalCount>0</TotalCount>
+  <FilterCount>0</FilterCount>
+  <SentCount>0</SentCount>
+  <ErrorCount>0</ErrorCount>
+  <CopyrightCheckResult>
+    <Count>2</Count>
+    <ResultList>
+      <item>
+        <ArticleIdx>1</ArticleIdx>
+        <UserDeclareState>0</UserDeclareState>
+        <AuditState>2</AuditState>
+        <OriginalArticleUrl><![CDATA[Url_1]]></OriginalArticleUrl>
+        <OriginalArticleType>1</OriginalArticleType>
+        <CanReprint>1</CanReprint>
+        <NeedReplaceContent>1</NeedReplaceContent>
+        <NeedShowReprintSource>1</NeedShowReprintSource>
+      </item>
+    </ResultList>
+    <CheckState>2</CheckState>
+  </CopyrightCheckResult>
+  <ArticleUrlResult>
+    <Count>1</Count>
+    <ResultList>
+      <item>
+        <ArticleIdx>1</ArticleIdx>
+        <ArticleUrl><![CDATA[Url]]></ArticleUrl>
+      </item>
+    </ResultList>
+  </ArticleUrlResult>
+</xml>
+*/
+type EventMassSendJobFinish struct {
+	Event
+	MsgID                string
+	Status               string
+	TotalCount           string
+	FilterCount          string
+	SentCount            string
+	ErrorCount           string
+	CopyrightCheckResult struct {
+		Count      string
+		ResultList struct {
+			Item []struct {
+				ArticleIdx            string
+				UserDeclareState      string
+				AuditState            string
+				OriginalArticleUrl    string
+				OriginalArticleType   string
+				CanReprint            string
+				NeedReplaceContent    string
+				NeedShowReprintSource string
+			} `xml:"item"`
+		}
+		CheckState string
+	}
+	ArticleUrlResult struct {
+		Count      string
+		ResultList struct {
+			Item []struct {
+				ArticleIdx string
+				ArticleUrl string
+			} `xml:"item"`
+		}
+	}
+}
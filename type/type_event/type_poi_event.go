@@ -0,0 +1,68 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypePoiCheckNotify = "poi_check_notify" // 门店 审核结果
+	EventTypeWifiConnected  = "WifiConnected"    // 门店 Wi-Fi 连网成功
+)
+
+/*
+<xml>
+
+	<ToUserName><![CDATA[toUser]]></ToUserName>
+	<FromUserName><![CDATA[fromUser]]></FromUserName>
+	<CreateTime>1408622107</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[poi_check_notify]]></Event>
+	<UniqId><![CDATA[123adb]]></UniqId>
+	<PoiId><![CDATA[123123]]></PoiId>
+	<Result><![CDATA[fail]]></Result>
+	<Msg><![CDATA[xxxxxx]]></Msg>
+
+</xml>
+*/
+type EventPoiCheckNotify struct {
+	Event
+	UniqId string // 商户自己内部ID，即字段中的sid
+	PoiId  string // 微信的门店ID，微信内门店唯一标示ID
+	Result string // 审核结果，成功succ 或失败fail
+	Msg    string // 成功的通知信息，或审核失败的驳回理由
+}
+
+/*
+<xml>
+
+	<ToUserName><![CDATA[toUser]]></ToUserName>
+	<FromUserName><![CDATA[FromUser]]></FromUserName>
+	<CreateTime>123456789</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[WifiConnected]]></Event>
+	<ConnectTime>0</ConnectTime>
+	<ExpireTime>0</ExpireTime>
+	<VendorId>3001224419</VendorId>
+	<ShopId>PlaceId</ShopId>
+	<DeviceNo>DeviceNo</DeviceNo>
+
+</xml>
+*/
+type EventWifiConnected struct {
+	Event
+	ConnectTime string // 连网时间
+	ExpireTime  string // 系统保留字段，固定值
+	VendorId    string // 系统保留字段，固定值
+	ShopId      string // 门店ID，即 shop_id
+	DeviceNo    string // 连网的设备无线mac地址，对应bssid
+}
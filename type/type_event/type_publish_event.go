@@ -0,0 +1,57 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypePublishJobFinish = "PUBLISHJOBFINISH" // 图文发布任务完成
+)
+
+/*
+<xml>
+  <ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+  <FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+  <CreateTime>1481013459</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+  <PublishEventInfo>
+    <publish_id>2247503051</publish_id>
+    <publish_status>0</publish_status>
+    <article_id><![CDATA[b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvyWP4Hq4]]></article_id>
+    <article_detail>
+      <count>1</count>
+      <item>
+        <idx>1</idx>
+        <article_url><![CDATA[ARTICLE_URL]]></article_url>
+      </item>
+    </article_detail>
+  </PublishEventInfo>
+</xml>
+*/
+type EventPublishJobFinish struct {
+	Event
+	PublishEventInfo struct {
+		PublishId     string `xml:"publish_id"`
+		PublishStatus string `xml:"publish_status"` // 0:成功, 1:发布中，2:原创失败, 3: 常规失败, 4:平台审核不通过, 5:成功后用户删除所有文章, 6: 成功后系统封禁所有文章
+		ArticleId     string `xml:"article_id"`
+		ArticleDetail struct {
+			Count string `xml:"count"`
+			Item  []struct {
+				Idx        string `xml:"idx"`
+				ArticleUrl string `xml:"article_url"`
+			} `xml:"item"`
+		} `xml:"article_detail"`
+		FailIdx []string `xml:"fail_idx"`
+	}
+}
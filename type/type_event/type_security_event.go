@@ -0,0 +1,65 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypeWxaMediaCheck = "wxa_media_check" // 异步校验图片/音频 结果推送
+)
+
+/*
+<xml>
+
+	<ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName>
+	<FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName>
+	<CreateTime>1626959646</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[wxa_media_check]]></Event>
+	<appid><![CDATA[wx8f16a5e5479f8e43]]></appid>
+	<trace_id><![CDATA[60f96f1d-3845297a-1976a3ee]]></trace_id>
+	<version>2</version>
+	<errcode>0</errcode>
+	<errmsg><![CDATA[ok]]></errmsg>
+	<result>
+	  <suggest><![CDATA[pass]]></suggest>
+	  <label>100</label>
+	</result>
+
+</xml>
+*/
+type EventWxaMediaCheck struct {
+	Event
+	Appid   string `xml:"appid"`
+	TraceId string `xml:"trace_id"` // 与 security.MediaCheckAsync 返回的 trace_id 对应
+	Version string `xml:"version"`
+	Errcode string `xml:"errcode"`
+	Errmsg  string `xml:"errmsg"`
+	Result  struct {
+		Suggest string `xml:"suggest"` // risky、pass、review
+		Label   string `xml:"label"`   // 命中标签
+	} `xml:"result"`
+
+	// 1.0 版本 推送字段
+	Isrisky    string `xml:"isrisky"` // 0 正常 1 违规
+	StatusCode string `xml:"status_code"`
+}
+
+/*
+Risky 检测结果 是否违规
+
+2.0 版本 推送 Result.Suggest 为 risky 时 违规；1.0 版本 推送 Isrisky 为 "1" 时 违规；suggest 为 review（需人工审核）时 返回 false，需要时 自行判断 Result.Suggest
+*/
+func (e EventWxaMediaCheck) Risky() bool {
+	return e.Result.Suggest == "risky" || e.Isrisky == "1"
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypeShakearoundUserShake = "ShakearoundUserShake" // 摇一摇 周边 事件通知
+)
+
+// Beacon 摇一摇 设备信息
+type Beacon struct {
+	Uuid     string
+	Major    string
+	Minor    string
+	Distance string // 设备与用户的距离（浮点数；单位：米）
+}
+
+/*
+<xml>
+
+	<ToUserName><![CDATA[toUser]]></ToUserName>
+	<FromUserName><![CDATA[fromUser]]></FromUserName>
+	<CreateTime>1433332012</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[ShakearoundUserShake]]></Event>
+	<ChosenBeacon>
+	  <Uuid><![CDATA[uuid]]></Uuid>
+	  <Major>major</Major>
+	  <Minor>minor</Minor>
+	  <Distance>0.057</Distance>
+	</ChosenBeacon>
+	<AroundBeacons>
+	  <AroundBeacon>
+	    <Uuid><![CDATA[uuid]]></Uuid>
+	    <Major>major</Major>
+	    <Minor>minor</Minor>
+	    <Distance>166.816</Distance>
+	  </AroundBeacon>
+	</AroundBeacons>
+
+</xml>
+*/
+type EventShakearoundUserShake struct {
+	Event
+	ChosenBeacon  Beacon   // 用户摇到的设备
+	AroundBeacons []Beacon `xml:"AroundBeacons>AroundBeacon"` // 摇到的周边设备列表
+}
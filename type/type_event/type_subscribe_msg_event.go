@@ -0,0 +1,71 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_event
+
+const (
+	EventTypeSubscribeMsgPopup = "subscribe_msg_popup_event" // 用户操作 订阅通知 弹窗
+)
+
+const (
+	SubscribeStatusAccept = "accept" // 同意
+	SubscribeStatusReject = "reject" // 拒绝
+)
+
+// SubscribeMsgPopupItem 用户对 某个模板 的操作
+type SubscribeMsgPopupItem struct {
+	TemplateId            string
+	SubscribeStatusString string // accept 同意 reject 拒绝
+	PopupScene            string // 1 弹窗来自 H5 页面 2 弹窗来自 图文消息
+}
+
+/*
+<xml>
+
+	<ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+	<FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+	<CreateTime>1610969440</CreateTime>
+	<MsgType><![CDATA[event]]></MsgType>
+	<Event><![CDATA[subscribe_msg_popup_event]]></Event>
+	<SubscribeMsgPopupEvent>
+	  <List>
+	    <TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+	    <SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString>
+	    <PopupScene>2</PopupScene>
+	  </List>
+	  <List>
+	    <TemplateId><![CDATA[9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI]]></TemplateId>
+	    <SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+	    <PopupScene>2</PopupScene>
+	  </List>
+	</SubscribeMsgPopupEvent>
+
+</xml>
+*/
+type EventSubscribeMsgPopup struct {
+	Event
+	SubscribeMsgPopupEvent struct {
+		List []SubscribeMsgPopupItem
+	}
+}
+
+// Accepted 用户同意订阅的 模板ID 列表
+func (e EventSubscribeMsgPopup) Accepted() (templateIds []string) {
+	for _, item := range e.SubscribeMsgPopupEvent.List {
+		if item.SubscribeStatusString == SubscribeStatusAccept {
+			templateIds = append(templateIds, item.TemplateId)
+		}
+	}
+	return
+}
@@ -14,7 +14,11 @@
 
 package type_message
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
 
 const (
 	MsgTypeText       = "text"
@@ -28,6 +32,12 @@ const (
 	MsgTypeEvent      = "event"
 )
 
+// 推送请求 url 上的 encrypt_type 参数
+const (
+	EncryptTypeRaw = "raw" // 明文模式
+	EncryptTypeAES = "aes" // 安全模式 / 兼容模式
+)
+
 type Message struct {
 	XMLName      xml.Name `xml:"xml"`
 	ToUserName   string
@@ -36,6 +46,19 @@ type Message struct {
 	MsgType      string
 }
 
+/*
+Time 以 time.Time 返回 CreateTime（unix 秒），CreateTime 无法解析时 返回 零值
+
+所有 消息 和 事件（type_event.Event）都嵌入了 Message，均可直接调用
+*/
+func (m Message) Time() time.Time {
+	seconds, err := strconv.ParseInt(m.CreateTime, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
 /*
 启用 加密模式 后 收到的 消息格式
 <xml>
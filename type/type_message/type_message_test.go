@@ -0,0 +1,42 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_message
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestMessage_Time(t *testing.T) {
+	msg := MessageText{}
+	err := xml.Unmarshal([]byte(`<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[this is a test]]></Content><MsgId>1234567890123456</MsgId></xml>`), &msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.Time(); !got.Equal(time.Unix(1348831860, 0)) {
+		t.Errorf("Time() = %s", got)
+	}
+
+	// 事件 同样可用
+	event := MessageEvent{Message: Message{CreateTime: "123456789"}}
+	if got := event.Time(); got.Unix() != 123456789 {
+		t.Errorf("Time() = %s", got)
+	}
+
+	if got := (Message{CreateTime: "abc"}).Time(); !got.IsZero() {
+		t.Errorf("Time() = %s, want zero", got)
+	}
+}
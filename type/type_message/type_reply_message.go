@@ -14,14 +14,37 @@
 
 package type_message
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CDATA 以 <![CDATA[...]]> 形式输出的文本
 
+内容中的 "]]>" 会被拆分到两个 CDATA 段中；XML 不允许出现的控制字符 会被去除，保证输出的 XML 合法
+*/
 type CDATA string
 
 func (c CDATA) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeElement(struct {
 		string `xml:",cdata"`
-	}{string(c)}, start)
+	}{strings.Map(xmlChar, string(c))}, start)
+}
+
+// xmlChar 去除 XML 1.0 不允许的字符 See: https://www.w3.org/TR/xml/#charsets
+func xmlChar(r rune) rune {
+	switch {
+	case r == 0x09 || r == 0x0A || r == 0x0D,
+		r >= 0x20 && r <= 0xD7FF,
+		r >= 0xE000 && r <= 0xFFFD,
+		r >= 0x10000 && r <= 0x10FFFF:
+		return r
+	}
+	return -1
 }
 
 const (
@@ -34,6 +57,24 @@ const (
 	ReplyMsgTypeTransferCustomerService = "transfer_customer_service" // 消息转发到(指定)客服
 )
 
+// ReplySuccess 不回复用户时 响应微信服务器的内容，微信服务器 收到后 不再重试，也不会提示用户
+const ReplySuccess = "success"
+
+/*
+NoReplyMessage 不回复用户，见 NoReply
+*/
+type NoReplyMessage struct{}
+
+/*
+NoReply 不回复用户，Server.Response 输出 ReplySuccess（"success"）
+
+收到消息后 必须在 5 秒内 响应 success 或 空字符串，否则微信服务器会重试 3 次 并提示用户 "该公众号暂时无法提供服务"；
+推荐 返回 NoReply() 明确表示 不回复，处理函数 返回 nil 时 效果相同
+*/
+func NoReply() NoReplyMessage {
+	return NoReplyMessage{}
+}
+
 type ReplyMessage struct {
 	XMLName      xml.Name `xml:"xml"`
 	ToUserName   CDATA
@@ -42,6 +83,25 @@ type ReplyMessage struct {
 	MsgType      CDATA
 }
 
+/*
+ReplyFor 构造 回复 inbound 消息的 消息头：ToUserName 为 inbound.FromUserName（用户），FromUserName 为 inbound.ToUserName（公众号），CreateTime 为 当前时间
+
+避免 ToUserName / FromUserName 填反 导致 回复被微信丢弃；事件 可以传入 event.Message
+
+	reply := type_message.ReplyMessageText{
+		ReplyMessage: type_message.ReplyFor(msg.Message, type_message.ReplyMsgTypeText),
+		Content:      "你好",
+	}
+*/
+func ReplyFor(inbound Message, msgType string) ReplyMessage {
+	return ReplyMessage{
+		ToUserName:   CDATA(inbound.FromUserName),
+		FromUserName: CDATA(inbound.ToUserName),
+		CreateTime:   strconv.FormatInt(time.Now().Unix(), 10),
+		MsgType:      CDATA(msgType),
+	}
+}
+
 /*
 加密处理后 的 回复 消息体
 <xml>
@@ -148,14 +208,46 @@ type ReplyMessageVideo struct {
 */
 type ReplyMessageMusic struct {
 	ReplyMessage
-	Music struct {
-		MediaId      CDATA
-		Title        CDATA
-		Description  CDATA
-		MusicUrl     CDATA
-		HQMusicUrl   CDATA
-		ThumbMediaId CDATA
+	Music Music
+}
+
+// Music 音乐消息内容 ThumbMediaId 必填，否则 微信客户端 无法展示
+type Music struct {
+	Title        CDATA
+	Description  CDATA
+	MusicUrl     CDATA
+	HQMusicUrl   CDATA
+	ThumbMediaId CDATA
+}
+
+// Validate 校验 音乐消息 必填字段
+func (m ReplyMessageMusic) Validate() error {
+	if m.Music.ThumbMediaId == "" {
+		return fmt.Errorf("reply music: ThumbMediaId required")
+	}
+	return nil
+}
+
+/*
+NewReplyMessageMusic 构造 回复音乐消息，toUserName 为 用户 openid，fromUserName 为 公众号原始ID
+
+	reply, err := type_message.NewReplyMessageMusic(msg.FromUserName, msg.ToUserName, type_message.Music{...})
+	err = app.Server.Response(w, r, reply)
+*/
+func NewReplyMessageMusic(toUserName string, fromUserName string, music Music) (reply ReplyMessageMusic, err error) {
+	reply = ReplyMessageMusic{
+		ReplyMessage: ReplyMessage{
+			ToUserName:   CDATA(toUserName),
+			FromUserName: CDATA(fromUserName),
+			CreateTime:   strconv.FormatInt(time.Now().Unix(), 10),
+			MsgType:      ReplyMsgTypeMusic,
+		},
+		Music: music,
+	}
+	if err = reply.Validate(); err != nil {
+		return ReplyMessageMusic{}, err
 	}
+	return
 }
 
 /*
@@ -0,0 +1,65 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package type_message
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestReplyMessageMusic(t *testing.T) {
+	music := Music{
+		Title:        "TITLE",
+		Description:  "DESCRIPTION",
+		MusicUrl:     "MUSIC_Url",
+		HQMusicUrl:   "HQ_MUSIC_Url",
+		ThumbMediaId: "media_id",
+	}
+	reply, err := NewReplyMessageMusic("toUser", "fromUser", music)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.CreateTime == "" {
+		t.Error("NewReplyMessageMusic() CreateTime empty")
+	}
+	reply.CreateTime = "12345678"
+
+	data, err := xml.Marshal(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<xml><ToUserName><![CDATA[toUser]]></ToUserName><FromUserName><![CDATA[fromUser]]></FromUserName><CreateTime>12345678</CreateTime><MsgType><![CDATA[music]]></MsgType>` +
+		`<Music><Title><![CDATA[TITLE]]></Title><Description><![CDATA[DESCRIPTION]]></Description><MusicUrl><![CDATA[MUSIC_Url]]></MusicUrl><HQMusicUrl><![CDATA[HQ_MUSIC_Url]]></HQMusicUrl><ThumbMediaId><![CDATA[media_id]]></ThumbMediaId></Music></xml>`
+	if string(data) != want {
+		t.Errorf("\nwant %s \nget %s", want, data)
+	}
+
+	music.ThumbMediaId = ""
+	if _, err = NewReplyMessageMusic("toUser", "fromUser", music); err == nil {
+		t.Error("NewReplyMessageMusic() want error for missing ThumbMediaId")
+	}
+}
+
+func TestReplyFor(t *testing.T) {
+	inbound := Message{ToUserName: "gh_123456789abc", FromUserName: "OPENID", CreateTime: "1348831860", MsgType: MsgTypeText}
+
+	reply := ReplyFor(inbound, ReplyMsgTypeText)
+	if reply.ToUserName != "OPENID" || reply.FromUserName != "gh_123456789abc" || reply.MsgType != ReplyMsgTypeText {
+		t.Errorf("ReplyFor() = %#v", reply)
+	}
+	if reply.CreateTime == "" || reply.CreateTime == inbound.CreateTime {
+		t.Errorf("ReplyFor() CreateTime = %q, want current time", reply.CreateTime)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/url"
+	"strings"
+)
+
+/*
+JoinUrl 拼接 服务器地址 和 接口路径，去除多余的 "/"
+
+	JoinUrl("https://api.weixin.qq.com/", "/cgi-bin/token") // https://api.weixin.qq.com/cgi-bin/token
+*/
+func JoinUrl(base string, uri string) string {
+	if uri == "" {
+		return base
+	}
+	if base == "" {
+		return uri
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(uri, "/")
+}
+
+/*
+AppendQuery 将 params 编码后 作为查询参数 附加到 uri 上（uri 可已带有查询参数），params 为空时 原样返回
+
+	AppendQuery("/cgi-bin/showqrcode", url.Values{"ticket": []string{"TICKET"}}) // /cgi-bin/showqrcode?ticket=TICKET
+*/
+func AppendQuery(uri string, params url.Values) string {
+	if len(params) == 0 {
+		return uri
+	}
+	return uri + querySeparator(uri) + params.Encode()
+}
+
+// querySeparator uri 追加查询参数 使用的分隔符："?" 或 "&"
+func querySeparator(uri string) string {
+	if strings.Contains(uri, "?") {
+		return "&"
+	}
+	return "?"
+}
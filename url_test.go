@@ -0,0 +1,180 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	cachesync "github.com/faabiosr/cachego/sync"
+)
+
+func TestJoinUrl(t *testing.T) {
+	tests := []struct {
+		base string
+		uri  string
+		want string
+	}{
+		{base: "https://api.weixin.qq.com", uri: "/cgi-bin/token", want: "https://api.weixin.qq.com/cgi-bin/token"},
+		{base: "https://api.weixin.qq.com/", uri: "/cgi-bin/token", want: "https://api.weixin.qq.com/cgi-bin/token"},
+		{base: "https://api.weixin.qq.com", uri: "cgi-bin/token", want: "https://api.weixin.qq.com/cgi-bin/token"},
+		{base: "https://api.weixin.qq.com/", uri: "cgi-bin/token", want: "https://api.weixin.qq.com/cgi-bin/token"},
+		{base: "https://proxy.example.com/wx//", uri: "//cgi-bin/token?a=b", want: "https://proxy.example.com/wx/cgi-bin/token?a=b"},
+		{base: "https://api.weixin.qq.com", uri: "", want: "https://api.weixin.qq.com"},
+	}
+	for _, tt := range tests {
+		if got := JoinUrl(tt.base, tt.uri); got != tt.want {
+			t.Errorf("JoinUrl(%q, %q) = %q, want %q", tt.base, tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestAppendQuery(t *testing.T) {
+	params := url.Values{"ticket": []string{"TICKET"}}
+	tests := []struct {
+		uri    string
+		params url.Values
+		want   string
+	}{
+		{uri: "/cgi-bin/showqrcode", params: params, want: "/cgi-bin/showqrcode?ticket=TICKET"},
+		{uri: "/cgi-bin/showqrcode?a=b", params: params, want: "/cgi-bin/showqrcode?a=b&ticket=TICKET"},
+		{uri: "/cgi-bin/showqrcode", params: nil, want: "/cgi-bin/showqrcode"},
+	}
+	for _, tt := range tests {
+		if got := AppendQuery(tt.uri, tt.params); got != tt.want {
+			t.Errorf("AppendQuery(%q, %v) = %q, want %q", tt.uri, tt.params, got, tt.want)
+		}
+	}
+	if got := appendAccessToken("/cgi-bin/showqrcode?a=b", "ACCESS_TOKEN"); got != "/cgi-bin/showqrcode?a=b&access_token=ACCESS_TOKEN" {
+		t.Errorf("appendAccessToken() = %q", got)
+	}
+}
+
+func TestJoinUrl_BaseURL(t *testing.T) {
+	var uris []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.URL.Path)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	app := New(Config{Appid: "TestJoinUrl_BaseURL", BaseURL: svr.URL + "/"}, WithStaticToken("ACCESS_TOKEN"))
+	if _, err := app.Client.HTTPGet("/cgi-bin/getcallbackip"); err != nil {
+		t.Fatal(err)
+	}
+	if len(uris) != 1 || uris[0] != "/cgi-bin/getcallbackip" {
+		t.Errorf("request path = %v, want [/cgi-bin/getcallbackip]", uris)
+	}
+}
+
+func TestOffiAccount_ServerUrlFor(t *testing.T) {
+	app := New(Config{
+		Appid:   "TestOffiAccount_ServerUrlFor",
+		BaseURL: "https://proxy.example.com",
+		HostOverrides: map[string]string{
+			"/cgi-bin/showqrcode": "mp.weixin.qq.com",
+			"/cgi-bin/":           "https://api2.weixin.qq.com",
+			"datacube/":           "http://127.0.0.1:8080",
+		},
+	})
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{uri: "/cgi-bin/showqrcode?ticket=TICKET", want: "https://mp.weixin.qq.com"},
+		{uri: "/cgi-bin/menu/get?access_token=ACCESS_TOKEN", want: "https://api2.weixin.qq.com"},
+		{uri: "/datacube/getusersummary", want: "http://127.0.0.1:8080"},
+		{uri: "/card/create", want: "https://proxy.example.com"},
+	}
+	for _, tt := range tests {
+		if got := app.ServerUrlFor(tt.uri); got != tt.want {
+			t.Errorf("ServerUrlFor(%s) = %s, want %s", tt.uri, got, tt.want)
+		}
+	}
+
+	if host, ok := app.HostOverride("/cgi-bin/showqrcode?ticket=TICKET"); !ok || host != "https://mp.weixin.qq.com" {
+		t.Errorf("HostOverride() = %s, %v, want https://mp.weixin.qq.com", host, ok)
+	}
+	if host, ok := app.HostOverride("/card/create"); ok {
+		t.Errorf("HostOverride() = %s, %v, want not matched", host, ok)
+	}
+}
+
+func TestClient_HostOverrides(t *testing.T) {
+	var hit string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = "api " + r.URL.Path
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer api.Close()
+	mp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = "mp " + r.URL.Path
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer mp.Close()
+
+	app := New(Config{
+		Appid:         "TestClient_HostOverrides",
+		BaseURL:       api.URL,
+		HostOverrides: map[string]string{"/cgi-bin/showqrcode": mp.URL},
+	}, WithStaticToken("ACCESS_TOKEN"))
+
+	if _, err := app.Client.HTTPGet("/cgi-bin/showqrcode?ticket=TICKET"); err != nil || hit != "mp /cgi-bin/showqrcode" {
+		t.Errorf("HTTPGet() hit = %s, err = %v, want mp", hit, err)
+	}
+	if _, err := app.Client.HTTPGet("/cgi-bin/menu/get"); err != nil || hit != "api /cgi-bin/menu/get" {
+		t.Errorf("HTTPGet() hit = %s, err = %v, want api", hit, err)
+	}
+}
+
+func TestHostOverrides_Token(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s should be sent to the override host", r.URL.Path)
+	}))
+	defer api.Close()
+	var hits []string
+	token := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.URL.Path)
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	}))
+	defer token.Close()
+
+	overrides := map[string]string{apiToken: token.URL, apiStableToken: token.URL}
+
+	app := New(Config{Appid: "TestHostOverrides_Token", Secret: "SECRET", BaseURL: api.URL, HostOverrides: overrides})
+	app.SetAccessTokenCacheDriver(cachesync.New())
+	app.SetLogger(nil)
+	if _, err := GetAccessToken(app); err != nil {
+		t.Fatal(err)
+	}
+
+	stable := New(Config{Appid: "TestHostOverrides_StableToken", Secret: "SECRET", BaseURL: api.URL, HostOverrides: overrides, UseStableToken: true})
+	stable.SetAccessTokenCacheDriver(cachesync.New())
+	stable.SetLogger(nil)
+	if _, err := GetAccessToken(stable); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ForceRefreshStableToken(stable); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{apiToken, apiStableToken, apiStableToken}; strings.Join(hits, ",") != strings.Join(want, ",") {
+		t.Errorf("override host hits = %v, want %v", hits, want)
+	}
+}
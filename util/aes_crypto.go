@@ -17,6 +17,7 @@ package util
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 )
@@ -37,6 +38,18 @@ func decodeNetworkByteOrder(b []byte) (n uint32) {
 		uint32(b[3])
 }
 
+// AESRandomPrefix 生成 消息加密 时 拼接在明文前的 16 字节随机串
+func AESRandomPrefix() []byte {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		panic(err)
+	}
+	for i := range random {
+		random[i] = charset[int(random[i])%len(charset)]
+	}
+	return random
+}
+
 // AESEncryptMsg 消息加密
 // ciphertext = AES_Encrypt[random(16B) + msg_len(4B) + rawXMLMsg + appId]
 func AESEncryptMsg(random, rawXMLMsg []byte, appId string, encodingAESKey string) (ciphertext string) {
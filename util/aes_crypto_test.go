@@ -102,3 +102,25 @@ func TestAESDecryptData(t *testing.T) {
 	}
 
 }
+
+func TestAESEncryptMsg_RoundTrip(t *testing.T) {
+	appId := "wx45f133bf6fce646e"
+	encodingAESKey := "AdiqDDDvUNCeE1ZW5XJmjf9fqNBJpGBs4vL4cHKmHBS"
+	wantPlaintext := []byte(`<xml><Content><![CDATA[你好 😀]]></Content></xml>`)
+
+	wantRandom := AESRandomPrefix()
+	if len(wantRandom) != 16 {
+		t.Fatalf("AESRandomPrefix() length = %d, want 16", len(wantRandom))
+	}
+	if bytes.Equal(wantRandom, AESRandomPrefix()) {
+		t.Errorf("AESRandomPrefix() should differ between calls")
+	}
+
+	random, plaintext, gotAppId, err := AESDecryptMsg(AESEncryptMsg(wantRandom, wantPlaintext, appId, encodingAESKey), encodingAESKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(random, wantRandom) || !bytes.Equal(plaintext, wantPlaintext) || string(gotAppId) != appId {
+		t.Errorf("round trip failed,\nhave: %s %s %s\nwant: %s %s %s", random, plaintext, gotAppId, wantRandom, wantPlaintext, appId)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"fmt"
+)
+
+/*
+ResponseValidator 校验 errcode 为 0 的接口响应，返回错误时 视为 临时错误
+
+uri 为 接口路径（如 /cgi-bin/user/info），body 为 响应体
+
+	app := offiaccount.New(offiaccount.Config{
+		Appid:  "APPID",
+		Secret: "SECRET",
+		ValidateResponse: func(uri string, body []byte) error {
+			if uri == "/cgi-bin/user/info" && !bytes.Contains(body, []byte(`"openid"`)) {
+				return errors.New("missing openid")
+			}
+			return nil
+		},
+	})
+*/
+type ResponseValidator func(uri string, body []byte) error
+
+// ResponseValidationError Config.ValidateResponse 校验失败
+type ResponseValidationError struct {
+	Uri string
+	Err error
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("validate response %s: %s", e.Uri, e.Err)
+}
+
+func (e *ResponseValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validateResponse 执行 Config.ValidateResponse 未设置时 不校验
+func (offiAccount *OffiAccount) validateResponse(uri string, body []byte) error {
+	if offiAccount.Config.ValidateResponse == nil {
+		return nil
+	}
+	if err := offiAccount.Config.ValidateResponse(uri, body); err != nil {
+		return &ResponseValidationError{Uri: uri, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offiaccount
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfig_ValidateResponse(t *testing.T) {
+	var calls int
+	broken := false
+	handler := http.NewServeMux()
+	handler.HandleFunc("/cgi-bin/user/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 || broken {
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"openid":"OPENID"}`))
+	})
+	svr := httptest.NewServer(handler)
+	defer svr.Close()
+
+	errMissing := errors.New("missing openid")
+	var uris []string
+	app := New(Config{
+		Appid:   "TestConfig_ValidateResponse",
+		BaseURL: svr.URL,
+		ValidateResponse: func(uri string, body []byte) error {
+			uris = append(uris, uri)
+			if !bytes.Contains(body, []byte(`"openid"`)) {
+				return errMissing
+			}
+			return nil
+		},
+	}, WithStaticToken("ACCESS_TOKEN"))
+	app.SetLogger(nil)
+
+	// 第一次响应 缺少字段 重试后成功
+	resp, err := app.Client.HTTPGet("/cgi-bin/user/info?openid=OPENID")
+	if err != nil || string(resp) != `{"openid":"OPENID"}` {
+		t.Fatalf("HTTPGet() = %s, %v", resp, err)
+	}
+	if calls != 2 || len(uris) != 2 || uris[0] != "/cgi-bin/user/info" {
+		t.Errorf("calls = %d, validated uris = %v", calls, uris)
+	}
+
+	// 重试后仍然失败 返回 *ResponseValidationError
+	broken = true
+	_, err = app.Client.HTTPGet("/cgi-bin/user/info")
+	var validationErr *ResponseValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, errMissing) || validationErr.Uri != "/cgi-bin/user/info" {
+		t.Errorf("HTTPGet() error = %v, want ResponseValidationError", err)
+	}
+
+	// 默认 不校验
+	app.Config.ValidateResponse = nil
+	calls = 0
+	if _, err = app.Client.HTTPGet("/cgi-bin/user/info"); err != nil || calls != 1 {
+		t.Errorf("HTTPGet() error = %v, calls = %d, want no validation", err, calls)
+	}
+}